@@ -5,17 +5,42 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"strings"
 
 	"github.com/TheEditor/strung/pkg/beads"
+	"github.com/TheEditor/strung/pkg/config"
 )
 
+// beadsBackend runs the br CLI against one Beads workspace
+type beadsBackend struct {
+	dir    string // Working directory of br; "" is the current directory
+	prefix string // Expected issue ID prefix; "" accepts any
+}
+
+// newBeadsBackend returns the backend configured for a project
+func newBeadsBackend(cfg *config.Config, project string) *beadsBackend {
+	p := cfg.Projects[project]
+	return &beadsBackend{dir: p.BeadsDir, prefix: p.IssuePrefix}
+}
+
+func (b *beadsBackend) command(args ...string) *exec.Cmd {
+	cmd := exec.Command("br", args...)
+	cmd.Dir = b.dir
+	return cmd
+}
+
+// ownsIssue reports whether an issue ID carries the backend's prefix
+func (b *beadsBackend) ownsIssue(issueID string) bool {
+	return b.prefix == "" || strings.HasPrefix(issueID, b.prefix+"-")
+}
+
 // BeadsCreateResponse is the JSON response from br create
 type BeadsCreateResponse struct {
 	ID string `json:"id"`
 }
 
-// createBeadsIssue creates an issue via br CLI, returns assigned issue ID
-func createBeadsIssue(issue *beads.Issue) (string, error) {
+// createIssue creates an issue via br CLI, returns assigned issue ID
+func (b *beadsBackend) createIssue(issue *beads.Issue) (string, error) {
 	args := []string{
 		"create",
 		issue.Title,
@@ -31,6 +56,9 @@ func createBeadsIssue(issue *beads.Issue) (string, error) {
 	if issue.Acceptance != "" {
 		args = append(args, "--acceptance", issue.Acceptance)
 	}
+	if issue.Assignee != nil {
+		args = append(args, "--assignee", *issue.Assignee)
+	}
 	if len(issue.Tags) > 0 {
 		// br uses -l/--labels with comma-separated values
 		labels := ""
@@ -43,7 +71,7 @@ func createBeadsIssue(issue *beads.Issue) (string, error) {
 		args = append(args, "-l", labels)
 	}
 
-	cmd := exec.Command("br", args...)
+	cmd := b.command(args...)
 	var stdout, stderr bytes.Buffer
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
@@ -65,9 +93,28 @@ func createBeadsIssue(issue *beads.Issue) (string, error) {
 	return result.ID, nil
 }
 
-// updateBeadsPriority updates issue priority
-func updateBeadsPriority(issueID string, priority int) error {
-	cmd := exec.Command("br", "update", issueID, "-p", fmt.Sprintf("%d", priority))
+// updateIssue rewrites an existing issue with freshly rendered content
+func (b *beadsBackend) updateIssue(issueID string, issue *beads.Issue) error {
+	args := []string{
+		"update",
+		issueID,
+		"--title", issue.Title,
+		"-p", fmt.Sprintf("%d", issue.Priority),
+		"-d", issue.Description,
+		"--design", issue.Design,
+		"--acceptance", issue.Acceptance,
+	}
+
+	// Only set the assignee when ownership is known, so manual assignment
+	// of unowned findings survives updates
+	if issue.Assignee != nil {
+		args = append(args, "--assignee", *issue.Assignee)
+	}
+	if len(issue.Tags) > 0 {
+		args = append(args, "--set-labels", strings.Join(issue.Tags, ","))
+	}
+
+	cmd := b.command(args...)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -78,9 +125,88 @@ func updateBeadsPriority(issueID string, priority int) error {
 	return nil
 }
 
-// closeBeadsIssue closes an issue
-func closeBeadsIssue(issueID string) error {
-	cmd := exec.Command("br", "close", issueID)
+// BeadsIssueInfo is the subset of br show --json output strung reads
+type BeadsIssueInfo struct {
+	ID          string   `json:"id"`
+	Title       string   `json:"title"`
+	Status      string   `json:"status"`
+	Priority    int      `json:"priority"`
+	Assignee    string   `json:"assignee,omitempty"`
+	Labels      []string `json:"labels,omitempty"`
+	CloseReason string   `json:"close_reason,omitempty"`
+	UpdatedAt   string   `json:"updated_at,omitempty"`
+}
+
+// showIssue fetches an issue via br CLI
+func (b *beadsBackend) showIssue(issueID string) (*BeadsIssueInfo, error) {
+	issues, err := b.showIssues([]string{issueID})
+	if err != nil {
+		return nil, err
+	}
+	if len(issues) == 0 || issues[0].ID == "" {
+		return nil, fmt.Errorf("br show %s returned no issue", issueID)
+	}
+	return &issues[0], nil
+}
+
+// showIssues fetches several issues with one br call. br fails the whole
+// call if any issue is missing.
+func (b *beadsBackend) showIssues(issueIDs []string) ([]BeadsIssueInfo, error) {
+	args := append(append([]string{"show"}, issueIDs...), "--json")
+	out, err := b.output(args...)
+	if err != nil {
+		return nil, err
+	}
+	return parseIssues(out)
+}
+
+// listIssues returns all issues with a label, open or closed
+func (b *beadsBackend) listIssues(label string) ([]BeadsIssueInfo, error) {
+	out, err := b.output("list", "--json", "--all", "--limit", "0", "-l", label)
+	if err != nil {
+		return nil, err
+	}
+	return parseIssues(out)
+}
+
+// output runs br and returns its stdout
+func (b *beadsBackend) output(args ...string) ([]byte, error) {
+	cmd := b.command(args...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("br %s failed: %w\nstderr: %s", args[0], err, stderr.String())
+	}
+	return stdout.Bytes(), nil
+}
+
+// parseIssues reads br's JSON issue output: an array, or a bare object
+func parseIssues(data []byte) ([]BeadsIssueInfo, error) {
+	out := bytes.TrimSpace(data)
+	if len(out) == 0 {
+		return nil, nil
+	}
+
+	var issues []BeadsIssueInfo
+	if bytes.HasPrefix(out, []byte("[")) {
+		if err := json.Unmarshal(out, &issues); err != nil {
+			return nil, fmt.Errorf("parse br output: %w\noutput: %s", err, data)
+		}
+		return issues, nil
+	}
+
+	var issue BeadsIssueInfo
+	if err := json.Unmarshal(out, &issue); err != nil {
+		return nil, fmt.Errorf("parse br output: %w\noutput: %s", err, data)
+	}
+	return append(issues, issue), nil
+}
+
+// closeIssue closes an issue
+func (b *beadsBackend) closeIssue(issueID string) error {
+	cmd := b.command("close", issueID)
 	var stderr bytes.Buffer
 	cmd.Stderr = &stderr
 
@@ -91,9 +217,35 @@ func closeBeadsIssue(issueID string) error {
 	return nil
 }
 
-// checkBeadsCLI verifies br CLI is available
-func checkBeadsCLI() error {
-	cmd := exec.Command("br", "version")
+// reopenIssue reopens a closed issue
+func (b *beadsBackend) reopenIssue(issueID string) error {
+	cmd := b.command("reopen", issueID)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("br reopen %s failed: %w\nstderr: %s", issueID, err, stderr.String())
+	}
+
+	return nil
+}
+
+// commentIssue adds a comment to an issue
+func (b *beadsBackend) commentIssue(issueID, text string) error {
+	cmd := b.command("comments", "add", issueID, text)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("br comments add %s failed: %w\nstderr: %s", issueID, err, stderr.String())
+	}
+
+	return nil
+}
+
+// check verifies br CLI is available
+func (b *beadsBackend) check() error {
+	cmd := b.command("version")
 	if err := cmd.Run(); err != nil {
 		return fmt.Errorf("br CLI not found or not working: %w\nInstall: https://github.com/Dicklesworthstone/beads_rust", err)
 	}
@@ -0,0 +1,284 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/config"
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+type branchListCmd struct {
+	projectOptions // --project
+
+	dbPath string
+}
+
+func newBranchListCmd() *branchListCmd {
+	return &branchListCmd{}
+}
+
+func (l *branchListCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&l.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	l.projectFlag(fs)
+}
+
+type branchMergeCmd struct {
+	projectOptions // --project
+
+	dbPath     string
+	configPath string
+	autoClose  bool
+	dryRun     bool
+
+	branch string
+}
+
+func newBranchMergeCmd() *branchMergeCmd {
+	return &branchMergeCmd{}
+}
+
+func (m *branchMergeCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&m.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	fs.StringVar(&m.configPath, "config", "", "Path to config file (default: .strung.json if present)")
+	m.projectFlag(fs)
+	fs.BoolVar(&m.autoClose, "auto-close", false, "Close issues of base findings the branch fixed")
+	fs.BoolVar(&m.dryRun, "dry-run", false, "Show the merge without writing")
+}
+
+func branchUsage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung branch <command> [flags]
+
+Manage branch views of the tracking database (see 'strung sync --branch').
+
+Commands:
+  list        List branches with their tracked findings
+  merge       Promote a merged branch's state into the base branch
+
+Run 'strung branch <command> --help' for command-specific help.
+`)
+}
+
+func (l *branchListCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung branch list [flags]
+
+List branches synced with --branch. OPEN and RESOLVED count findings first
+seen on the branch; FIXED counts base branch findings the branch resolved.
+
+Flags:
+  --db-path PATH  Path to tracking database (default: .strung.db)
+  --project NAME  Only list branches of project NAME (default: all projects)
+`)
+}
+
+func (m *branchMergeCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung branch merge [flags] <branch>
+
+Promote a merged branch's state into the base branch. Findings first seen on
+the branch become base branch findings, keeping their issues. Base findings
+the branch fixed are resolved with --auto-close; otherwise they stay open
+until the next base branch sync resolves them.
+
+Flags:
+  --db-path PATH  Path to tracking database (default: .strung.db)
+  --config PATH   Path to config file (default: .strung.json if present)
+  --project NAME  Only merge the branch of project NAME (default: all projects)
+  --auto-close    Close issues of base findings the branch fixed
+  --dry-run       Show the merge without writing
+
+Examples:
+  # After merging feature/login into main
+  strung branch merge --auto-close feature/login
+`)
+}
+
+// runBranchCommand dispatches "strung branch" subcommands
+func runBranchCommand(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		branchUsage()
+		return ExitSuccess
+	}
+
+	switch args[0] {
+	case "list":
+		fs := flag.NewFlagSet("branch list", flag.ExitOnError)
+		listCmd := newBranchListCmd()
+		listCmd.flags(fs)
+
+		if hasHelpArg(args[1:]) {
+			listCmd.usage()
+			return ExitSuccess
+		}
+
+		fs.Parse(args[1:])
+		return listCmd.run()
+
+	case "merge":
+		fs := flag.NewFlagSet("branch merge", flag.ExitOnError)
+		mergeCmd := newBranchMergeCmd()
+		mergeCmd.flags(fs)
+
+		if hasHelpArg(args[1:]) {
+			mergeCmd.usage()
+			return ExitSuccess
+		}
+
+		if err := mergeCmd.parse(fs, args[1:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			mergeCmd.usage()
+			return ExitUsageError
+		}
+		return mergeCmd.run()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown branch command: %s\n", args[0])
+		branchUsage()
+		return ExitUsageError
+	}
+}
+
+func (l *branchListCmd) run() int {
+	database, err := db.Open(l.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+	l.scopeProject(database)
+
+	branches, err := database.Branches()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	if len(branches) == 0 {
+		fmt.Fprintf(os.Stderr, "No branches tracked\n")
+		return ExitSuccess
+	}
+
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "BRANCH\tOPEN\tRESOLVED\tFIXED\tLAST SCAN")
+	for _, b := range branches {
+		last := "-"
+		if b.LastScan != nil {
+			last = b.LastScan.Local().Format("2006-01-02 15:04")
+		}
+		fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%s\n", b.Name, b.Open, b.Resolved, b.Fixed, last)
+	}
+	tw.Flush()
+	return ExitSuccess
+}
+
+// parse reads flags and the branch name, allowing flags after it
+func (m *branchMergeCmd) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("missing branch name")
+	}
+	m.branch = fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+func (m *branchMergeCmd) run() int {
+	cfg, err := config.Load(m.configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitUsageError
+	}
+	if m.autoClose && !m.dryRun {
+		if err := newBeadsBackend(cfg, m.project).check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitDBError
+		}
+	}
+
+	database, err := db.Open(m.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+	m.scopeProject(database)
+
+	if m.dryRun {
+		return m.preview(database)
+	}
+
+	now := time.Now()
+	result, err := database.MergeBranch(m.branch, now)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	fmt.Fprintf(os.Stderr, "Merged %s: %d findings promoted, %d base findings fixed\n",
+		m.branch, len(result.Promoted), len(result.Fixed))
+
+	if !m.autoClose {
+		if len(result.Fixed) > 0 {
+			fmt.Fprintf(os.Stderr, "Note: %d fixed findings stay open until the next sync (use --auto-close to close)\n",
+				len(result.Fixed))
+		}
+		return ExitSuccess
+	}
+
+	exitCode := 0
+	for _, fixed := range result.Fixed {
+		if err := newBeadsBackend(cfg, fixed.Project).closeIssue(fixed.IssueID); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR closing %s: %v\n", fixed.IssueID, err)
+			exitCode = 3
+			continue
+		}
+
+		if err := database.MarkResolved(fixed.Fingerprint, now); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR marking resolved: %v\n", err)
+			exitCode = 3
+		}
+
+		if !recordEvent(database, &db.Event{
+			Fingerprint: fixed.Fingerprint, Event: db.EventResolved,
+			Severity: fixed.Severity, Line: fixed.Line, Detail: fixed.IssueID, CreatedAt: now,
+		}) {
+			exitCode = 3
+		}
+
+		fmt.Fprintf(os.Stderr, "Closed: %s\n", fixed.IssueID)
+	}
+	return exitCode
+}
+
+// preview reports what merging the branch would change
+func (m *branchMergeCmd) preview(database *db.TrackingDB) int {
+	branches, err := database.Branches()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+
+	for _, b := range branches {
+		if b.Name != m.branch {
+			continue
+		}
+		fmt.Fprintf(os.Stderr, "[DRY RUN] Would promote %d findings from %s\n", b.Open+b.Resolved, m.branch)
+		if m.autoClose {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would close %d base findings fixed on %s\n", b.Fixed, m.branch)
+		} else {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] %d base findings fixed on %s would stay open\n", b.Fixed, m.branch)
+		}
+		return ExitSuccess
+	}
+
+	fmt.Fprintf(os.Stderr, "[DRY RUN] Branch %s has no tracked state\n", m.branch)
+	return ExitSuccess
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+// comment posts comments on tracked issues unless --no-comments is set.
+// Issues sync must leave alone get no comments. Returns false if a write
+// failed.
+func (s *syncCmd) comment(database *db.TrackingDB, comments []sync.IssueComment, scanID string, now time.Time) bool {
+	if s.noComments {
+		return true
+	}
+
+	ok := true
+	for _, c := range comments {
+		if s.skip[c.Fingerprint] {
+			continue
+		}
+		if s.dryRun {
+			if posted, err := database.CommentPosted(c.IssueID, c.Kind, c.Digest()); err == nil && !posted {
+				fmt.Fprintf(os.Stderr, "[DRY RUN] Would comment on %s: %s\n", c.IssueID, c.Summary)
+			}
+			continue
+		}
+		if err := s.postComment(database, c, scanID, now); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR commenting on %s: %v\n", c.IssueID, err)
+			ok = false
+		}
+	}
+	return ok
+}
+
+// postComment posts a comment unless it repeats the last comment of its
+// kind on the issue, and records it
+func (s *syncCmd) postComment(database *db.TrackingDB, c sync.IssueComment, scanID string, now time.Time) error {
+	digest := c.Digest()
+	posted, err := database.CommentPosted(c.IssueID, c.Kind, digest)
+	if err != nil || posted {
+		return err
+	}
+
+	if err := s.backend.commentIssue(c.IssueID, c.Body(scanID)); err != nil {
+		return err
+	}
+	if s.verbose {
+		fmt.Fprintf(os.Stderr, "Commented: %s (%s)\n", c.IssueID, c.Kind)
+	}
+
+	return database.RecordComment(&db.Comment{
+		IssueID: c.IssueID, Digest: digest, Fingerprint: c.Fingerprint,
+		Kind: c.Kind, ScanID: scanID, CreatedAt: now,
+	})
+}
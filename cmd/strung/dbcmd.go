@@ -0,0 +1,132 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+type dbMigrateCmd struct {
+	dbPath string
+	dryRun bool
+}
+
+func newDBMigrateCmd() *dbMigrateCmd {
+	return &dbMigrateCmd{}
+}
+
+func (m *dbMigrateCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&m.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	fs.BoolVar(&m.dryRun, "dry-run", false, "Show pending migrations without applying them")
+}
+
+func dbUsage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung db <command> [flags]
+
+Manage the tracking database.
+
+Commands:
+  migrate     Upgrade the database schema to the current version
+
+Run 'strung db <command> --help' for command-specific help.
+`)
+}
+
+func (m *dbMigrateCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung db migrate [flags]
+
+Upgrade the tracking database schema. Existing databases are backed up to
+<db-path>.v<version>-<timestamp>.bak before migrating. Commands that write
+the database migrate automatically on open; read-only commands (status,
+list, show, report) require a current schema. Use this to preview or apply
+migrations explicitly.
+
+Flags:
+  --db-path PATH  Path to tracking database (default: .strung.db)
+  --dry-run       Show pending migrations without applying them
+
+Examples:
+  # Preview pending migrations
+  strung db migrate --dry-run
+
+  # Apply migrations
+  strung db migrate --db-path=.strung.db
+`)
+}
+
+// runDBCommand dispatches "strung db" subcommands
+func runDBCommand(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		dbUsage()
+		return ExitSuccess
+	}
+
+	switch args[0] {
+	case "migrate":
+		fs := flag.NewFlagSet("db migrate", flag.ExitOnError)
+		migrateCmd := newDBMigrateCmd()
+		migrateCmd.flags(fs)
+
+		if hasHelpArg(args[1:]) {
+			migrateCmd.usage()
+			return ExitSuccess
+		}
+
+		fs.Parse(args[1:])
+		return migrateCmd.run()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown db command: %s\n", args[0])
+		dbUsage()
+		return ExitUsageError
+	}
+}
+
+func (m *dbMigrateCmd) run() int {
+	plan, err := db.PlanMigrations(m.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+
+	fmt.Fprintf(os.Stderr, "Database: %s (schema version %d, current is %d)\n",
+		m.dbPath, plan.Current, plan.Target)
+
+	if len(plan.Pending) == 0 {
+		fmt.Fprintf(os.Stderr, "Schema is up to date\n")
+		return ExitSuccess
+	}
+
+	prefix := "Pending: "
+	if m.dryRun {
+		prefix = "[DRY RUN] Would apply: "
+	}
+	for _, p := range plan.Pending {
+		fmt.Fprintf(os.Stderr, "%sv%d %s\n", prefix, p.Version, p.Name)
+	}
+
+	if m.dryRun {
+		if plan.Exists {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would back up database before migrating\n")
+		}
+		return ExitSuccess
+	}
+
+	database, err := db.Open(m.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+
+	if result := database.Migration(); result != nil {
+		if result.Backup != "" {
+			fmt.Fprintf(os.Stderr, "Backup: %s\n", result.Backup)
+		}
+		fmt.Fprintf(os.Stderr, "Migrated: v%d → v%d\n", result.From, result.To)
+	}
+
+	return ExitSuccess
+}
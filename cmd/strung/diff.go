@@ -0,0 +1,192 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/git"
+	"github.com/TheEditor/strung/pkg/report"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+// exitNewFindings is returned by strung diff when new findings exceed --max-new
+const exitNewFindings = 4
+
+type diffCmd struct {
+	reviewOptions
+
+	format         string
+	output         string
+	minSeverity    string
+	failSeverity   string
+	maxNew         int
+	matchThreshold float64
+	baseRef        string
+	verbose        bool
+
+	base, head string
+}
+
+func newDiffCmd() *diffCmd {
+	return &diffCmd{}
+}
+
+func (d *diffCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&d.format, "format", "text", "Output format: "+strings.Join(report.CompareFormats, ", "))
+	fs.StringVar(&d.output, "output", "", "Write the comparison to this file instead of stdout")
+	fs.StringVar(&d.minSeverity, "min-severity", "warning", "Minimum severity compared (critical, warning, info)")
+	fs.StringVar(&d.failSeverity, "fail-severity", "", "Count only new findings at or above this severity toward --max-new")
+	fs.IntVar(&d.maxNew, "max-new", -1, "Exit 4 when new findings exceed this count (-1 disables)")
+	fs.Float64Var(&d.matchThreshold, "match-threshold", sync.DefaultMatchThreshold, "Similarity for matching moved findings (0 disables)")
+	fs.StringVar(&d.baseRef, "base-ref", "", "Git revision of the base scan, for rename detection against the working tree")
+	fs.BoolVar(&d.verbose, "verbose", false, "Enable verbose output")
+	d.reviewFlags(fs)
+}
+
+func (d *diffCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung diff [flags] <base.json> <head.json>
+
+Compare two UBS scans directly, without a tracking database. Findings are
+matched by fingerprint and classified as new, fixed, changed, moved or
+unchanged. Either file may be "-" for stdin.
+
+Flags:
+  --format FORMAT          text, json or markdown (default: text)
+  --output FILE            Write to FILE instead of stdout
+  --min-severity LEVEL     Minimum severity compared (default: warning)
+  --max-new N              Exit 4 when more than N new findings (default: -1, disabled)
+  --fail-severity LEVEL    Count only new findings at or above LEVEL toward
+                           --max-new (default: --min-severity)
+  --match-threshold SCORE  Similarity for matching moved findings (default: 0.7,
+                           0 disables)
+  --base-ref REV           Pair files renamed since REV (run in the head checkout)
+  --verbose                Enable verbose output
+
+Review mode (limit the comparison to a pull request's changes):
+  --changed-from REV       Diff the checkout against REV or a range like
+                           origin/main...HEAD
+  --patch FILE             Read the changes from a unified diff file instead
+  --changed-scope SCOPE    lines (added/modified lines) or files (default: lines)
+  --changed-radius N       Also keep findings N lines from a change (default: 0)
+
+Fixed findings are kept when their file is in the diff.
+
+Examples:
+  # PR comment comparing the base branch scan with the head scan
+  strung diff --format=markdown base.json head.json > comment.md
+
+  # Fail CI on any new critical finding
+  ubs --format=json src/ | strung diff --max-new=0 --fail-severity=critical base.json -
+
+  # Gate only on findings in lines the PR touched
+  strung diff --changed-from=origin/main...HEAD --changed-radius=3 --max-new=0 base.json head.json
+`)
+}
+
+// parse reads flags and the two scan paths, allowing flags on either side
+// of them
+func (d *diffCmd) parse(fs *flag.FlagSet, args []string) error {
+	var paths []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return err
+		}
+		if fs.NArg() == 0 {
+			break
+		}
+		paths = append(paths, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+	if len(paths) != 2 {
+		return fmt.Errorf("expected base and head scan files, got %d arguments", len(paths))
+	}
+	if paths[0] == "-" && paths[1] == "-" {
+		return fmt.Errorf("only one scan can be read from stdin")
+	}
+	d.base, d.head = paths[0], paths[1]
+	return nil
+}
+
+func (d *diffCmd) run() int {
+	validSeverities := map[string]bool{"critical": true, "warning": true, "info": true}
+	if !validSeverities[d.minSeverity] {
+		fmt.Fprintf(os.Stderr, "Error: invalid severity %q (use: critical, warning, info)\n", d.minSeverity)
+		return ExitUsageError
+	}
+	if d.failSeverity == "" {
+		d.failSeverity = d.minSeverity
+	}
+	if !validSeverities[d.failSeverity] {
+		fmt.Fprintf(os.Stderr, "Error: invalid --fail-severity %q (use: critical, warning, info)\n", d.failSeverity)
+		return ExitUsageError
+	}
+	if !validFormat(d.format, report.CompareFormats) {
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q (use %s)\n", d.format, strings.Join(report.CompareFormats, ", "))
+		return ExitUsageError
+	}
+	if d.matchThreshold < 0 || d.matchThreshold > 1 {
+		fmt.Fprintf(os.Stderr, "Error: --match-threshold must be between 0 and 1\n")
+		return ExitUsageError
+	}
+	filter, err := d.changeFilter(d.verbose)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitUsageError
+	}
+
+	base, err := readScan(d.base)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: base scan %s: %v\n", d.base, err)
+		return ExitInputError
+	}
+	head, err := readScan(d.head)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: head scan %s: %v\n", d.head, err)
+		return ExitInputError
+	}
+
+	result := sync.DiffScans(base.FilterBySeverity(d.minSeverity), head.FilterBySeverity(d.minSeverity),
+		d.renames(), d.matchThreshold)
+	if filter != nil {
+		filterDiff(result, filter)
+	}
+	comparison := report.NewComparison(d.base, d.head, result)
+
+	if err := writeOutput(d.output, func(w io.Writer) error { return comparison.Write(w, d.format) }); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitInputError
+	}
+
+	if d.maxNew >= 0 {
+		if n := comparison.CountNew(d.failSeverity); n > d.maxNew {
+			fmt.Fprintf(os.Stderr, "%d new %s+ findings exceed --max-new=%d\n", n, d.failSeverity, d.maxNew)
+			return exitNewFindings
+		}
+	}
+	return ExitSuccess
+}
+
+// renames returns files renamed since --base-ref, or nil
+func (d *diffCmd) renames() map[string]string {
+	if d.baseRef == "" {
+		return nil
+	}
+
+	repo, err := git.Open(".")
+	if err == nil {
+		var renames map[string]string
+		if renames, err = repo.Renames(d.baseRef); err == nil {
+			if d.verbose {
+				fmt.Fprintf(os.Stderr, "Detected %d renamed files since %s\n", len(renames), d.baseRef)
+			}
+			return renames
+		}
+	}
+	if d.verbose {
+		fmt.Fprintf(os.Stderr, "Rename detection skipped: %v\n", err)
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/patch"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+func TestDiffParse(t *testing.T) {
+	d := newDiffCmd()
+	fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+	d.flags(fs)
+
+	if err := d.parse(fs, []string{"--format=json", "base.json", "--max-new=0", "-"}); err != nil {
+		t.Fatalf("parse failed: %v", err)
+	}
+	if d.base != "base.json" || d.head != "-" || d.format != "json" || d.maxNew != 0 {
+		t.Errorf("parsed %+v", d)
+	}
+
+	for _, args := range [][]string{{"base.json"}, {"a.json", "b.json", "c.json"}, {"-", "-"}} {
+		d := newDiffCmd()
+		fs := flag.NewFlagSet("diff", flag.ContinueOnError)
+		d.flags(fs)
+		if err := d.parse(fs, args); err == nil {
+			t.Errorf("parse(%v) should fail", args)
+		}
+	}
+}
+
+func TestDiffRun(t *testing.T) {
+	scans := filepath.Join("..", "..", "testdata", "sync-scenarios")
+	base, head := filepath.Join(scans, "scan1.json"), filepath.Join(scans, "scan2.json")
+
+	d := &diffCmd{format: "json", output: filepath.Join(t.TempDir(), "diff.json"), minSeverity: "info", maxNew: -1, base: base, head: head}
+	if code := d.run(); code != 0 {
+		t.Fatalf("run = %d, want 0", code)
+	}
+
+	d.maxNew = 0
+	d.failSeverity = ""
+	if code := d.run(); code != exitNewFindings {
+		t.Errorf("run with --max-new=0 = %d, want %d", code, exitNewFindings)
+	}
+
+	d.head = filepath.Join(scans, "missing.json")
+	if code := d.run(); code != 1 {
+		t.Errorf("run with missing scan = %d, want 1", code)
+	}
+}
+
+func TestFilterDiff(t *testing.T) {
+	// Outside a checkout, so scanner paths are not resolved against this repo
+	t.Chdir(t.TempDir())
+	patchFile := filepath.Join(t.TempDir(), "pr.diff")
+	os.WriteFile(patchFile, []byte("--- a/a.go\n+++ b/a.go\n@@ -10,0 +11,2 @@\n+x\n+y\n"), 0o644)
+
+	o := reviewOptions{patchFile: patchFile, changedScope: patch.ScopeLines, changedRadius: 1}
+	filter, err := o.changeFilter(false)
+	if err != nil {
+		t.Fatalf("changeFilter failed: %v", err)
+	}
+
+	d := &sync.DiffResult{
+		New: []parser.UBSFinding{{File: "a.go", Line: 12}, {File: "a.go", Line: 20}, {File: "b.go", Line: 11}},
+		Changed: []sync.ChangeRecord{
+			{Current: parser.UBSFinding{File: "a.go", Line: 13}},
+			{Current: parser.UBSFinding{File: "a.go", Line: 14}},
+		},
+		Resolved: []*db.Finding{{File: "a.go", Line: 90}, {File: "b.go", Line: 1}},
+	}
+	filterDiff(d, filter)
+
+	if len(d.New) != 1 || d.New[0].Line != 12 {
+		t.Errorf("New = %+v", d.New)
+	}
+	if len(d.Changed) != 1 || d.Changed[0].Current.Line != 13 {
+		t.Errorf("Changed = %+v", d.Changed)
+	}
+	if len(d.Resolved) != 1 || d.Resolved[0].File != "a.go" {
+		t.Errorf("Resolved = %+v", d.Resolved)
+	}
+
+	for _, bad := range []reviewOptions{
+		{patchFile: patchFile, changedFrom: "main", changedScope: patch.ScopeLines},
+		{patchFile: patchFile, changedScope: "hunks"},
+		{patchFile: filepath.Join(t.TempDir(), "missing.diff"), changedScope: patch.ScopeLines},
+	} {
+		if _, err := bad.changeFilter(false); err == nil {
+			t.Errorf("changeFilter(%+v) should fail", bad)
+		}
+	}
+	if f, err := (&reviewOptions{}).changeFilter(false); f != nil || err != nil {
+		t.Errorf("review mode off: got %v, %v", f, err)
+	}
+}
@@ -0,0 +1,160 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/source"
+)
+
+type fingerprintMigrateCmd struct {
+	dbPath     string
+	to         int
+	sourceRoot string
+	dryRun     bool
+	verbose    bool
+}
+
+func newFingerprintMigrateCmd() *fingerprintMigrateCmd {
+	return &fingerprintMigrateCmd{}
+}
+
+func (m *fingerprintMigrateCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&m.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	fs.IntVar(&m.to, "to", db.FingerprintVersion, "Target fingerprint algorithm version")
+	fs.StringVar(&m.sourceRoot, "source-root", "", "Key findings without a snippet on source context read under this directory")
+	fs.BoolVar(&m.dryRun, "dry-run", false, "Show planned re-keying without writing")
+	fs.BoolVar(&m.verbose, "verbose", false, "List every re-keyed finding")
+}
+
+func fingerprintUsage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung fingerprint <command> [flags]
+
+Manage finding fingerprints.
+
+Commands:
+  migrate     Re-key tracked findings to a fingerprint algorithm version
+
+Run 'strung fingerprint <command> --help' for command-specific help.
+`)
+}
+
+func (m *fingerprintMigrateCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung fingerprint migrate [flags]
+
+Re-key tracked findings to a fingerprint algorithm version using the payload
+stored in the tracking database. Issue IDs, first-seen dates and event history
+are preserved. Findings whose stored payload no longer reproduces their
+fingerprint, or whose new fingerprint collides with another finding, are
+skipped and reported.
+
+Findings the scanner reported without a code snippet are keyed on their line.
+With --source-root, they are keyed on the code around that line instead
+(version 3 and later), as 'strung sync --source-root' does for new findings.
+
+Flags:
+  --db-path PATH     Path to tracking database (default: .strung.db)
+  --to VERSION       Target algorithm version, 1 to %[1]d (default: %[1]d)
+  --source-root DIR  Key findings without a snippet on source context read under DIR
+  --dry-run          Show planned re-keying without writing
+  --verbose          List every re-keyed finding
+
+Examples:
+  # Preview re-keying to the current algorithm
+  strung fingerprint migrate --dry-run --verbose
+
+  # Apply
+  strung fingerprint migrate --db-path=.strung.db
+
+  # Before syncing with --source-root, key snippet-less findings on context
+  strung fingerprint migrate --source-root=.
+`, db.FingerprintVersion)
+}
+
+// runFingerprintCommand dispatches "strung fingerprint" subcommands
+func runFingerprintCommand(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		fingerprintUsage()
+		return ExitSuccess
+	}
+
+	switch args[0] {
+	case "migrate":
+		fs := flag.NewFlagSet("fingerprint migrate", flag.ExitOnError)
+		migrateCmd := newFingerprintMigrateCmd()
+		migrateCmd.flags(fs)
+
+		if hasHelpArg(args[1:]) {
+			migrateCmd.usage()
+			return ExitSuccess
+		}
+
+		fs.Parse(args[1:])
+		return migrateCmd.run()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown fingerprint command: %s\n", args[0])
+		fingerprintUsage()
+		return ExitUsageError
+	}
+}
+
+func (m *fingerprintMigrateCmd) run() int {
+	if m.to < db.FingerprintV1 || m.to > db.FingerprintVersion {
+		fmt.Fprintf(os.Stderr, "Error: invalid --to %d (use %d-%d)\n", m.to, db.FingerprintV1, db.FingerprintVersion)
+		return ExitUsageError
+	}
+
+	// A dry run must not create, migrate or back up the database
+	open := db.Open
+	if m.dryRun {
+		open = openExistingDB
+	}
+	database, err := open(m.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+
+	var load db.ContextLoader
+	if m.sourceRoot != "" {
+		reader := source.NewReader(m.sourceRoot)
+		load = func(file string, line int) string {
+			return readSourceContext(reader, file, line)
+		}
+	}
+
+	result, err := database.MigrateFingerprints(m.to, load, m.dryRun)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+
+	prefix := ""
+	if m.dryRun {
+		prefix = "[DRY RUN] "
+	}
+
+	for _, r := range result.Rekeys {
+		switch {
+		case r.Outcome == db.RekeyUnverifiable:
+			fmt.Fprintf(os.Stderr, "%sSkipped %s (%s:%d): stored payload does not reproduce fingerprint v%d\n",
+				prefix, r.IssueID, r.File, r.Line, r.FromVersion)
+		case r.Outcome == db.RekeyConflict:
+			fmt.Fprintf(os.Stderr, "%sSkipped %s (%s:%d): new fingerprint %s already tracked\n",
+				prefix, r.IssueID, r.File, r.Line, r.NewFP[:12])
+		case m.verbose && r.Outcome == db.RekeyMigrated:
+			fmt.Fprintf(os.Stderr, "%sRe-keyed %s: %s → %s\n", prefix, r.IssueID, r.OldFP[:12], r.NewFP[:12])
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "%sFingerprint v%d: %d re-keyed, %d unchanged, %d unverifiable, %d conflicts\n",
+		prefix, result.Target,
+		result.Count(db.RekeyMigrated), result.Count(db.RekeyUnchanged),
+		result.Count(db.RekeyUnverifiable), result.Count(db.RekeyConflict))
+
+	return ExitSuccess
+}
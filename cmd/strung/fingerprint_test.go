@@ -0,0 +1,74 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+func TestFingerprintMigrateDryRun(t *testing.T) {
+	dir := t.TempDir()
+
+	missing := filepath.Join(dir, "missing.db")
+	cmd := &fingerprintMigrateCmd{dbPath: missing, to: db.FingerprintVersion, dryRun: true}
+	if code := cmd.run(); code != ExitDBError {
+		t.Errorf("dry run on a missing database = %d, want %d", code, ExitDBError)
+	}
+	if _, err := os.Stat(missing); !os.IsNotExist(err) {
+		t.Error("dry run created the database")
+	}
+
+	dbPath := filepath.Join(dir, "test.db")
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	// A v1 row the dry run would re-key
+	fp, err := db.ComputeFingerprintVersion(db.FingerprintV1, "a.ts", "null-safety", "msg", "", 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err := database.Store(&db.Finding{
+		Fingerprint: fp, FingerprintVersion: db.FingerprintV1,
+		IssueID: "bd-1", File: "a.ts", Line: 1, Severity: "critical", Category: "null-safety",
+		Message: "msg", FirstSeen: now, LastSeen: now,
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	database.Close()
+
+	before, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cmd = &fingerprintMigrateCmd{dbPath: dbPath, to: db.FingerprintVersion, dryRun: true}
+	if code := cmd.run(); code != ExitSuccess {
+		t.Fatalf("dry run = %d, want %d", code, ExitSuccess)
+	}
+	after, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("dry run modified the database")
+	}
+}
+
+func TestFingerprintMigrateTarget(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	for _, to := range []int{0, -1, db.FingerprintVersion + 1} {
+		cmd := &fingerprintMigrateCmd{dbPath: dbPath, to: to}
+		if code := cmd.run(); code != ExitUsageError {
+			t.Errorf("--to %d = %d, want %d", to, code, ExitUsageError)
+		}
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Error("invalid --to opened the database")
+	}
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/git"
+	"github.com/TheEditor/strung/pkg/transform"
+)
+
+// linkOptions holds the flags that place findings in the repository for
+// file permalinks
+type linkOptions struct {
+	repoURL    string
+	repoBranch string
+	forge      string
+	commit     string
+	pathPrefix string
+	links      transform.LinkBuilder
+}
+
+func (l *linkOptions) linkFlags(fs *flag.FlagSet) {
+	fs.StringVar(&l.repoURL, "repo-url", "", "Repository URL for file links (default: detected from git remote)")
+	fs.StringVar(&l.repoBranch, "repo-branch", "", "Repository branch for file links (default: detected, else main)")
+	fs.StringVar(&l.forge, "forge", "auto", "Link format: auto, "+strings.Join(transform.Forges(), ", "))
+	fs.StringVar(&l.commit, "commit", "", "Git commit the scan was taken at (default: detected HEAD)")
+}
+
+// applyGitDefaults fills --repo-url, --repo-branch and --commit from the
+// checkout when they were not given. repo may be nil outside a checkout.
+func (l *linkOptions) applyGitDefaults(repo *git.Repo, verbose bool) {
+	if repo != nil {
+		if l.repoURL == "" {
+			if remote, err := repo.RemoteURL("origin"); err == nil {
+				l.repoURL = git.WebURL(remote)
+			}
+		}
+		if l.repoBranch == "" {
+			l.repoBranch, _ = repo.Branch()
+		}
+		if l.commit == "" {
+			l.commit, _ = repo.Head()
+		}
+		l.pathPrefix, _ = repo.Prefix()
+
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Git: root=%s commit=%s branch=%s url=%s\n",
+				repo.Root, l.commit, l.repoBranch, l.repoURL)
+		}
+	}
+
+	if l.repoBranch == "" {
+		l.repoBranch = "main"
+	}
+}
+
+// detectRenames asks git which files were renamed since the commit of the
+// last recorded scan. Renames are a matching hint only, so failures (no
+// history, not a git checkout) just disable them.
+func detectRenames(database *db.TrackingDB, repo *git.Repo, verbose bool) map[string]string {
+	if repo == nil {
+		return nil
+	}
+
+	last, err := database.LatestScan()
+	if err != nil || last == nil || last.GitCommit == "" {
+		return nil
+	}
+
+	renames, err := repo.Renames(last.GitCommit)
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Rename detection skipped: %v\n", err)
+		}
+		return nil
+	}
+	if verbose && len(renames) > 0 {
+		fmt.Fprintf(os.Stderr, "Detected %d renamed files since %s\n", len(renames), last.GitCommit)
+	}
+	return renames
+}
@@ -179,6 +179,7 @@ func TestIntegration_ErrorCases(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cmd := exec.Command(binPath, tt.args...)
+			cmd.Dir = t.TempDir() // sync creates .strung.db in the working directory
 			if tt.stdin != "" {
 				cmd.Stdin = strings.NewReader(tt.stdin)
 			}
@@ -0,0 +1,322 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+type listCmd struct {
+	projectOptions // --project
+
+	dbPath     string
+	status     string
+	severity   string
+	category   string
+	path       string
+	issueID    string
+	since      string
+	until      string
+	seenSince  string
+	seenUntil  string
+	sort       string
+	limit      int
+	offset     int
+	format     string
+	fullHashes bool
+}
+
+func newListCmd() *listCmd {
+	return &listCmd{}
+}
+
+func (l *listCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&l.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	l.projectFlag(fs)
+	fs.StringVar(&l.status, "status", "open", "Finding status: open, resolved, suppressed, all")
+	fs.StringVar(&l.severity, "severity", "", "Comma-separated severities to include")
+	fs.StringVar(&l.category, "category", "", "Comma-separated categories to include")
+	fs.StringVar(&l.path, "path", "", "File path glob (e.g. 'src/*', '*.ts')")
+	fs.StringVar(&l.issueID, "issue", "", "Beads issue ID")
+	fs.StringVar(&l.since, "since", "", "First seen at or after (date, RFC3339 or age like 7d)")
+	fs.StringVar(&l.until, "until", "", "First seen before (date, RFC3339 or age like 7d)")
+	fs.StringVar(&l.seenSince, "seen-since", "", "Last seen at or after (date, RFC3339 or age like 7d)")
+	fs.StringVar(&l.seenUntil, "seen-until", "", "Last seen before (date, RFC3339 or age like 7d)")
+	fs.StringVar(&l.sort, "sort", "-last_seen", "Sort key, '-' prefix for descending: "+strings.Join(db.SortKeys(), ", "))
+	fs.IntVar(&l.limit, "limit", 50, "Maximum findings to list (0 = all)")
+	fs.IntVar(&l.offset, "offset", 0, "Skip this many findings (for paging)")
+	fs.StringVar(&l.format, "format", "table", "Output format: table, json")
+	fs.BoolVar(&l.fullHashes, "full", false, "Print full fingerprints")
+}
+
+func (l *listCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung list [flags]
+
+List tracked findings, filtered, sorted and paged.
+
+Flags:
+  --db-path PATH      Path to tracking database (default: .strung.db)
+  --project NAME      Only findings of project NAME (default: all projects)
+  --status STATUS     open, resolved, suppressed or all (default: open)
+  --severity LIST     Comma-separated severities (e.g. critical,warning)
+  --category LIST     Comma-separated categories
+  --path GLOB         File path glob; '*' also matches '/' (e.g. 'src/*')
+  --issue ID          Beads issue ID
+  --since WHEN        First seen at or after WHEN
+  --until WHEN        First seen before WHEN
+  --seen-since WHEN   Last seen at or after WHEN
+  --seen-until WHEN   Last seen before WHEN
+  --sort KEY          %s; prefix '-' to reverse (default: -last_seen)
+  --limit N           Maximum findings to list (default: 50, 0 = all)
+  --offset N          Skip the first N findings
+  --format FORMAT     table or json (default: table)
+  --full              Print full fingerprints
+
+WHEN is a date (2026-01-31), an RFC3339 time, or an age such as 12h or 7d.
+
+Examples:
+  # Open critical findings under src/
+  strung list --severity=critical --path='src/*'
+
+  # Findings resolved in the last week
+  strung list --status=resolved --seen-since=7d
+
+  # Second page of the oldest findings
+  strung list --sort=first_seen --limit=20 --offset=20
+`, strings.Join(db.SortKeys(), ", "))
+}
+
+func (l *listCmd) run() int {
+	q, err := l.query(time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitUsageError
+	}
+	if l.format != "table" && l.format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q (use table or json)\n", l.format)
+		return ExitUsageError
+	}
+
+	database, err := openExistingDB(l.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+	l.scopeProject(database)
+
+	findings, err := database.Query(q)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	total, err := database.Count(q)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+
+	if l.format == "json" {
+		out := make([]findingJSON, 0, len(findings))
+		for _, f := range findings {
+			out = append(out, newFindingJSON(f))
+		}
+		if err := writeJSON(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitInputError
+		}
+	} else {
+		l.writeTable(findings)
+	}
+
+	if len(findings) < total {
+		fmt.Fprintf(os.Stderr, "Showing %d-%d of %d findings\n", l.offset+1, l.offset+len(findings), total)
+	} else if total == 0 {
+		fmt.Fprintf(os.Stderr, "No matching findings\n")
+	}
+	return ExitSuccess
+}
+
+// query converts the flags into a db.FindingQuery, resolving relative times
+// against now
+func (l *listCmd) query(now time.Time) (db.FindingQuery, error) {
+	q := db.FindingQuery{
+		Severities: splitList(l.severity),
+		Categories: splitList(l.category),
+		Path:       l.path,
+		IssueID:    l.issueID,
+		Sort:       l.sort,
+		Limit:      l.limit,
+		Offset:     l.offset,
+	}
+
+	switch l.status {
+	case "all", "":
+	case db.StatusOpen, db.StatusResolved, db.StatusSuppressed:
+		q.Status = l.status
+	default:
+		return q, fmt.Errorf("invalid status %q (use open, resolved, suppressed or all)", l.status)
+	}
+
+	if l.limit < 0 || l.offset < 0 {
+		return q, fmt.Errorf("--limit and --offset must not be negative")
+	}
+
+	times := []struct {
+		flag  string
+		value string
+		dest  *time.Time
+	}{
+		{"since", l.since, &q.FirstSeenAfter},
+		{"until", l.until, &q.FirstSeenBefore},
+		{"seen-since", l.seenSince, &q.LastSeenAfter},
+		{"seen-until", l.seenUntil, &q.LastSeenBefore},
+	}
+	for _, t := range times {
+		if t.value == "" {
+			continue
+		}
+		parsed, err := parseWhen(t.value, now)
+		if err != nil {
+			return q, fmt.Errorf("--%s: %w", t.flag, err)
+		}
+		*t.dest = parsed
+	}
+
+	return q, nil
+}
+
+func (l *listCmd) writeTable(findings []*db.Finding) {
+	tw := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "FINGERPRINT\tISSUE\tSEVERITY\tSTATUS\tLOCATION\tCATEGORY\tMESSAGE")
+	for _, f := range findings {
+		fp := f.Fingerprint
+		if !l.fullHashes && len(fp) > 12 {
+			fp = fp[:12]
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s:%d\t%s\t%s\n",
+			fp, f.IssueID, f.Severity, findingStatus(f), f.File, f.Line, f.Category, oneLine(f.Message, 60))
+	}
+	tw.Flush()
+}
+
+// parseWhen accepts a date, an RFC3339 timestamp or an age ("12h", "7d")
+// measured back from now
+func parseWhen(s string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t, nil
+	}
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid time %q (use YYYY-MM-DD, RFC3339 or an age like 7d)", s)
+}
+
+// splitList splits a comma-separated flag value, dropping empty entries
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
+
+// oneLine collapses whitespace and truncates s to max runes
+func oneLine(s string, max int) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > max {
+		return string(r[:max-1]) + "…"
+	}
+	return s
+}
+
+func findingStatus(f *db.Finding) string {
+	switch {
+	case f.ResolvedAt != nil:
+		return db.StatusResolved
+	case f.Suppressed():
+		return db.StatusSuppressed
+	}
+	return db.StatusOpen
+}
+
+// findingJSON is the machine-readable form of a tracked finding
+type findingJSON struct {
+	Fingerprint        string     `json:"fingerprint"`
+	FingerprintVersion int        `json:"fingerprint_version"`
+	IssueID            string     `json:"issue_id"`
+	Status             string     `json:"status"`
+	File               string     `json:"file"`
+	Line               int        `json:"line"`
+	Column             int        `json:"column,omitempty"`
+	Severity           string     `json:"severity"`
+	Category           string     `json:"category"`
+	Message            string     `json:"message"`
+	Suggestion         string     `json:"suggestion,omitempty"`
+	CodeSnippet        string     `json:"code_snippet,omitempty"`
+	Tool               string     `json:"tool,omitempty"`
+	RuleID             string     `json:"rule_id,omitempty"`
+	FirstSeen          time.Time  `json:"first_seen"`
+	LastSeen           time.Time  `json:"last_seen"`
+	ResolvedAt         *time.Time `json:"resolved_at,omitempty"`
+	SuppressedAt       *time.Time `json:"suppressed_at,omitempty"`
+	IssueStatus        string     `json:"issue_status,omitempty"`
+	LastScanID         string     `json:"last_scan_id,omitempty"`
+	Project            string     `json:"project,omitempty"`
+	Branch             string     `json:"branch,omitempty"`
+}
+
+func newFindingJSON(f *db.Finding) findingJSON {
+	version := f.FingerprintVersion
+	if version == 0 {
+		version = db.FingerprintVersion
+	}
+	return findingJSON{
+		Fingerprint:        f.Fingerprint,
+		FingerprintVersion: version,
+		IssueID:            f.IssueID,
+		Status:             findingStatus(f),
+		File:               f.File,
+		Line:               f.Line,
+		Column:             f.Column,
+		Severity:           f.Severity,
+		Category:           f.Category,
+		Message:            f.Message,
+		Suggestion:         f.Suggestion,
+		CodeSnippet:        f.CodeSnippet,
+		Tool:               f.Tool,
+		RuleID:             f.RuleID,
+		FirstSeen:          f.FirstSeen,
+		LastSeen:           f.LastSeen,
+		ResolvedAt:         f.ResolvedAt,
+		SuppressedAt:       f.SuppressedAt,
+		IssueStatus:        f.IssueStatus,
+		LastScanID:         f.LastScanID,
+		Project:            f.Project,
+		Branch:             f.Branch,
+	}
+}
+
+// writeJSON prints v as indented JSON on stdout
+func writeJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+func TestParseWhen(t *testing.T) {
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		in   string
+		want time.Time
+	}{
+		{"7d", now.AddDate(0, 0, -7)},
+		{"36h", now.Add(-36 * time.Hour)},
+		{"2026-01-31T08:00:00Z", time.Date(2026, 1, 31, 8, 0, 0, 0, time.UTC)},
+		{"2026-01-31", time.Date(2026, 1, 31, 0, 0, 0, 0, time.Local)},
+	}
+	for _, tt := range tests {
+		got, err := parseWhen(tt.in, now)
+		if err != nil {
+			t.Fatalf("parseWhen(%q) failed: %v", tt.in, err)
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("parseWhen(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+
+	for _, bad := range []string{"yesterday", "-3d", "31/01/2026"} {
+		if _, err := parseWhen(bad, now); err == nil {
+			t.Errorf("parseWhen(%q) should fail", bad)
+		}
+	}
+}
+
+func TestListQuery(t *testing.T) {
+	now := time.Now()
+	l := &listCmd{status: "all", severity: "critical, warning,", since: "7d", sort: "file", limit: 10}
+
+	q, err := l.query(now)
+	if err != nil {
+		t.Fatalf("query failed: %v", err)
+	}
+	if q.Status != "" || len(q.Severities) != 2 || q.Severities[1] != "warning" {
+		t.Errorf("unexpected query: %+v", q)
+	}
+	if !q.FirstSeenAfter.Equal(now.AddDate(0, 0, -7)) {
+		t.Errorf("FirstSeenAfter = %v", q.FirstSeenAfter)
+	}
+
+	l.status = db.StatusResolved
+	if q, _ := l.query(now); q.Status != db.StatusResolved {
+		t.Errorf("Status = %q", q.Status)
+	}
+
+	l.status = "closed"
+	if _, err := l.query(now); err == nil {
+		t.Error("expected error for invalid status")
+	}
+}
@@ -7,12 +7,22 @@ import (
 	"log"
 	"os"
 
+	"github.com/TheEditor/strung/pkg/config"
 	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/taxonomy"
 	"github.com/TheEditor/strung/pkg/transform"
 )
 
 var versionStr = "0.2.0-dev"
 
+// Exit codes for commands other than sync, matching ExitSync*
+const (
+	ExitSuccess    = 0
+	ExitInputError = 1 // Unreadable input, unknown finding or unwritable output
+	ExitUsageError = 2
+	ExitDBError    = 3
+)
+
 func main() {
 	if len(os.Args) < 2 {
 		printUsage()
@@ -57,6 +67,78 @@ func main() {
 		fs.Parse(os.Args[2:])
 		os.Exit(recoverCmd.run())
 
+	case "status":
+		fs := flag.NewFlagSet("status", flag.ExitOnError)
+		statusCmd := newStatusCmd()
+		statusCmd.flags(fs)
+
+		if hasHelpArg(os.Args[2:]) {
+			statusCmd.usage()
+			os.Exit(0)
+		}
+
+		fs.Parse(os.Args[2:])
+		os.Exit(statusCmd.run())
+
+	case "list":
+		fs := flag.NewFlagSet("list", flag.ExitOnError)
+		listCmd := newListCmd()
+		listCmd.flags(fs)
+
+		if hasHelpArg(os.Args[2:]) {
+			listCmd.usage()
+			os.Exit(0)
+		}
+
+		fs.Parse(os.Args[2:])
+		os.Exit(listCmd.run())
+
+	case "show":
+		fs := flag.NewFlagSet("show", flag.ExitOnError)
+		showCmd := newShowCmd()
+		showCmd.flags(fs)
+
+		if hasHelpArg(os.Args[2:]) {
+			showCmd.usage()
+			os.Exit(0)
+		}
+
+		if err := showCmd.parse(fs, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			showCmd.usage()
+			os.Exit(2)
+		}
+		os.Exit(showCmd.run())
+
+	case "diff":
+		fs := flag.NewFlagSet("diff", flag.ExitOnError)
+		diffCmd := newDiffCmd()
+		diffCmd.flags(fs)
+
+		if hasHelpArg(os.Args[2:]) {
+			diffCmd.usage()
+			os.Exit(0)
+		}
+
+		if err := diffCmd.parse(fs, os.Args[2:]); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			diffCmd.usage()
+			os.Exit(2)
+		}
+		os.Exit(diffCmd.run())
+
+	case "report":
+		os.Exit(runReportCommand(os.Args[2:]))
+
+	case "branch":
+		os.Exit(runBranchCommand(os.Args[2:]))
+
+	case "db":
+		os.Exit(runDBCommand(os.Args[2:]))
+
+	case "fingerprint":
+		os.Exit(runFingerprintCommand(os.Args[2:]))
+
 	case "version", "--version", "-v":
 		fmt.Printf("strung v%s\n", versionStr)
 		os.Exit(0)
@@ -81,29 +163,75 @@ Commands:
   transform   One-way transform: UBS JSON → Beads JSONL (stdin → stdout)
   sync        Incremental sync with state tracking (bidirectional)
   recover     Check and recover database consistency
+  status      Summarize tracked findings and sync health
+  list        List tracked findings with filters
+  show        Show a finding's record, issue and history
+  diff        Compare two scan files without a database
+  report      Generate reports (trends, html)
+  branch      Manage branch views (list, merge)
+  db          Manage the tracking database (migrate)
+  fingerprint Manage finding fingerprints (migrate)
   version     Print version
   help        Show this help
 
 Transform Examples:
   ubs --format=json src/ | strung transform
   ubs --format=json src/ | strung transform --min-severity=critical
+  ubs --format=json src/ | strung transform --changed-from=origin/main...HEAD
 
 Sync Examples:
   ubs --format=json src/ | strung sync --db-path=.strung.db
   ubs --format=json src/ | strung sync --auto-close
+  ubs --format=json src/ | strung sync --branch=feature/login
+  strung branch merge --auto-close feature/login
+  ubs --format=json services/api | strung sync --project=api
 
 Recovery Examples:
   strung recover --db-path=.strung.db
   strung recover --db-path=.strung.db --fix
 
+Status Examples:
+  strung status
+  strung status --format=markdown
+  strung list --severity=critical --path='src/*'
+  strung show bd-42
+  strung report trends --period=month --format=html --output=trends.html
+  strung report html --input=scan.json --output=strung-report
+
+Diff Examples:
+  strung diff base.json head.json
+  strung diff --format=markdown --max-new=0 base.json head.json
+
+Database Examples:
+  strung db migrate --dry-run
+  strung fingerprint migrate --dry-run
+
 Run 'strung <command> --help' for command-specific help.
 `)
 }
 
+// isHelpArg reports whether arg requests help
+func isHelpArg(arg string) bool {
+	return arg == "-h" || arg == "--help" || arg == "-help" || arg == "help"
+}
+
+// hasHelpArg reports whether any flag argument requests help
+func hasHelpArg(args []string) bool {
+	for _, arg := range args {
+		if arg == "-h" || arg == "--help" || arg == "-help" {
+			return true
+		}
+	}
+	return false
+}
+
 func runTransform(args []string) {
 	fs := flag.NewFlagSet("transform", flag.ExitOnError)
 	minSeverity := fs.String("min-severity", "warning", "Minimum severity (critical, warning, info)")
 	verbose := fs.Bool("verbose", false, "Enable verbose output")
+	configPath := fs.String("config", "", "Path to config file (default: .strung.json if present)")
+	var review reviewOptions
+	review.reviewFlags(fs)
 	fs.Parse(args)
 
 	// Validate
@@ -112,6 +240,16 @@ func runTransform(args []string) {
 		fmt.Fprintf(os.Stderr, "Error: invalid severity %q\n", *minSeverity)
 		os.Exit(2)
 	}
+	filter, err := review.changeFilter(*verbose)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(2)
+	}
 
 	// Configure logging
 	log.SetOutput(os.Stderr)
@@ -133,12 +271,19 @@ func runTransform(args []string) {
 	findings := report.FilterBySeverity(*minSeverity)
 	log.Printf("After filter: %d findings", len(findings))
 
+	if filter != nil {
+		findings = filter.Apply(findings)
+		log.Printf("In changed %s: %d findings", filter.Scope, len(findings))
+	}
+
 	if len(findings) == 0 {
 		os.Exit(0)
 	}
 
 	// Transform
 	transformer := transform.NewTransformer()
+	transformer.Limits = configLimits(cfg)
+	transformer.Taxonomy = taxonomy.Default().With(cfg.Taxonomy)
 	issues := transformer.TransformAll(findings)
 
 	// Output
@@ -2,6 +2,7 @@ package main
 
 import (
 	"bytes"
+	"encoding/json"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -119,4 +120,55 @@ func TestCLIIntegration(t *testing.T) {
 			t.Error("Should fail on invalid severity")
 		}
 	})
+
+	t.Run("config limits", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "strung.json")
+		if err := os.WriteFile(configPath, []byte(`{"limits":{"title":12}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		input := `{"project":"/test","files_scanned":1,"findings":[{"file":"test.ts","line":42,"severity":"critical","category":"null-safety","message":"Test message"}],"summary":{"critical":1}}`
+
+		cmd := exec.Command(binPath, "transform", "--config", configPath)
+		cmd.Stdin = strings.NewReader(input)
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		var issue struct {
+			Title string `json:"title"`
+		}
+		if err := json.Unmarshal(output, &issue); err != nil {
+			t.Fatalf("Unmarshal failed: %v\n%s", err, output)
+		}
+		if n := len([]rune(issue.Title)); n > 12 {
+			t.Errorf("title %q has %d characters, want at most 12", issue.Title, n)
+		}
+	})
+
+	t.Run("config taxonomy", func(t *testing.T) {
+		configPath := filepath.Join(t.TempDir(), "strung.json")
+		if err := os.WriteFile(configPath, []byte(`{"taxonomy":{"null-safety":{"cwe":["CWE-690"]}}}`), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		input := `{"project":"/test","files_scanned":1,"findings":[{"file":"test.ts","line":42,"severity":"critical","category":"null-safety","message":"Test message"}],"summary":{"critical":1}}`
+
+		cmd := exec.Command(binPath, "transform", "--config", configPath)
+		cmd.Stdin = strings.NewReader(input)
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("Run failed: %v", err)
+		}
+
+		var issue struct {
+			Tags []string `json:"tags"`
+		}
+		if err := json.Unmarshal(output, &issue); err != nil {
+			t.Fatalf("Unmarshal failed: %v\n%s", err, output)
+		}
+		tags := strings.Join(issue.Tags, ",")
+		if !strings.Contains(tags, "cwe:690") || strings.Contains(tags, "cwe:476") {
+			t.Errorf("tags %v should use the configured taxonomy", issue.Tags)
+		}
+	})
 }
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"flag"
+	"path"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+// projectOptions holds the --project filter of commands that read the
+// tracking database
+type projectOptions struct {
+	project string
+}
+
+func (o *projectOptions) projectFlag(fs *flag.FlagSet) {
+	fs.StringVar(&o.project, "project", "", "Only include this project (default: all projects)")
+}
+
+// scopeProject limits the database to --project, if given
+func (o *projectOptions) scopeProject(database *db.TrackingDB) {
+	if o.project != "" {
+		database.SetProject(o.project)
+	}
+}
+
+// scopeScan limits the database to the project of a scan compared against
+// it: --project, else the default project
+func (o *projectOptions) scopeScan(database *db.TrackingDB, report *parser.UBSReport) {
+	database.SetProject(resolveProject(o.project, report))
+}
+
+// projectFromReport names the project after the scanned directory
+const projectFromReport = "auto"
+
+// resolveProject expands projectFromReport to the report's project
+func resolveProject(project string, report *parser.UBSReport) string {
+	if project == projectFromReport {
+		return reportProject(report)
+	}
+	return project
+}
+
+// reportProject derives a project name from a report. Reports carry the
+// absolute scanned path, so only its last element is used; checkouts in
+// differently named directories still get different projects.
+func reportProject(report *parser.UBSReport) string {
+	p := strings.TrimRight(strings.ReplaceAll(report.Project, "\\", "/"), "/")
+	if p == "" {
+		return ""
+	}
+	return path.Base(p)
+}
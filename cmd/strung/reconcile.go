@@ -0,0 +1,283 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/beads"
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+// Policies for issues closed in Beads while their finding persists
+const (
+	closedSuppress = "suppress" // Leave the issue closed and stop syncing it
+	closedReopen   = "reopen"   // Reopen the issue
+	closedComment  = "comment"  // Comment that the finding persists, then suppress
+)
+
+// issueStateBatch is the number of issues read per br call
+const issueStateBatch = 50
+
+// fingerprintLabel tags an issue with its finding, so a finding whose
+// database row was lost is relinked to its issue instead of filed again
+func fingerprintLabel(fingerprint string) string {
+	return "fp:" + fingerprint[:16]
+}
+
+// labelIssue adds the fingerprint label to a rendered issue
+func labelIssue(issue *beads.Issue, fingerprint string) *beads.Issue {
+	issue.Tags = append(issue.Tags, fingerprintLabel(fingerprint))
+	return issue
+}
+
+// reconcile reads the issues of tracked findings back from Beads and
+// applies what people did there: findings whose issue was closed by hand are
+// handled per --on-closed, reopened issues are synced again, and findings
+// whose issue is already closed are resolved. Issues sync must leave alone
+// are recorded in s.skip; if Beads can't be read, the stored issue state
+// decides. Returns false if a write failed.
+func (s *syncCmd) reconcile(database *db.TrackingDB, result *sync.DiffResult, scanID string, now time.Time) bool {
+	tracked := trackedIssues(database, result)
+	s.reconciled = &sync.Reconciliation{Skip: sync.StoredSkip(tracked)}
+	s.skip = s.reconciled.Skip
+	if len(tracked) == 0 {
+		return true
+	}
+
+	states, err := s.issueStates(tracked)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not read issues from Beads, skipping reconciliation: %v\n", firstLine(err.Error()))
+		return true
+	}
+
+	ok := true
+	for _, f := range tracked {
+		if state, read := states[f.IssueID]; read && state.Status != f.IssueStatus {
+			if err := database.SetIssueStatus(f.Fingerprint, state.Status); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR recording issue status: %v\n", err)
+				ok = false
+			}
+		}
+	}
+
+	rec := sync.Reconcile(result, states)
+	s.reconciled = rec
+	s.skip = rec.Skip
+
+	for _, f := range rec.Closed {
+		if !s.handleClosed(database, f, states[f.IssueID], scanID, now) {
+			ok = false
+		}
+	}
+
+	for _, f := range rec.Reopened {
+		if err := database.Unsuppress(f.Fingerprint); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR unsuppressing: %v\n", err)
+			ok = false
+			continue
+		}
+		ok = recordEvent(database, &db.Event{
+			Fingerprint: f.Fingerprint, ScanID: scanID, Event: db.EventUnsuppressed,
+			Severity: f.Severity, Line: f.Line, Detail: "reopened in Beads", CreatedAt: now,
+		}) && ok
+		fmt.Fprintf(os.Stderr, "Unsuppressed: %s (reopened in Beads)\n", f.IssueID)
+	}
+
+	for _, f := range rec.AlreadyClosed {
+		if err := database.MarkResolved(f.Fingerprint, now); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR marking resolved: %v\n", err)
+			ok = false
+			continue
+		}
+		ok = recordEvent(database, &db.Event{
+			Fingerprint: f.Fingerprint, ScanID: scanID, Event: db.EventResolved,
+			Severity: f.Severity, Line: f.Line, Detail: "closed in Beads", CreatedAt: now,
+		}) && ok
+		fmt.Fprintf(os.Stderr, "Resolved: %s (already closed in Beads)\n", f.IssueID)
+	}
+
+	for _, c := range rec.Conflicts {
+		fmt.Fprintf(os.Stderr, "Conflict: %s\n", c)
+	}
+	if stats := rec.Stats(); stats != "" {
+		fmt.Fprintf(os.Stderr, "Beads state: %s\n", stats)
+	}
+	return ok
+}
+
+// trackedIssues returns the tracked findings with an issue sync manages.
+// Issues of inherited findings belong to the base branch.
+func trackedIssues(database *db.TrackingDB, result *sync.DiffResult) []*db.Finding {
+	var tracked []*db.Finding
+	for _, f := range result.Tracked() {
+		if f.IssueID != "" && !database.Inherited(f) {
+			tracked = append(tracked, f)
+		}
+	}
+	return tracked
+}
+
+// handleClosed applies --on-closed to a finding whose issue was closed in
+// Beads while the finding persists. Returns false if a write failed.
+func (s *syncCmd) handleClosed(database *db.TrackingDB, f *db.Finding, state sync.IssueState, scanID string, now time.Time) bool {
+	reason := "closed in Beads"
+	if state.CloseReason != "" {
+		reason += ": " + oneLine(state.CloseReason, 60)
+	}
+
+	switch s.onClosed {
+	case closedReopen:
+		if err := s.backend.reopenIssue(f.IssueID); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR reopening %s: %v\n", f.IssueID, err)
+			return false
+		}
+		if err := database.SetIssueStatus(f.Fingerprint, beads.StatusOpen); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR recording issue status: %v\n", err)
+			return false
+		}
+		delete(s.skip, f.Fingerprint)
+		fmt.Fprintf(os.Stderr, "Reopened: %s (%s, finding still present)\n", f.IssueID, reason)
+		return recordEvent(database, &db.Event{
+			Fingerprint: f.Fingerprint, ScanID: scanID, Event: db.EventReopened,
+			Severity: f.Severity, Line: f.Line, Detail: reason, CreatedAt: now,
+		})
+
+	case closedComment:
+		if err := s.postComment(database, sync.PersistsComment(f), scanID, now); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR commenting on %s: %v\n", f.IssueID, err)
+			return false
+		}
+	}
+
+	if err := database.Suppress(f.Fingerprint, now); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR suppressing: %v\n", err)
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Suppressed: %s (%s)\n", f.IssueID, reason)
+	return recordEvent(database, &db.Event{
+		Fingerprint: f.Fingerprint, ScanID: scanID, Event: db.EventSuppressed,
+		Severity: f.Severity, Line: f.Line, Detail: reason, CreatedAt: now,
+	})
+}
+
+// issueStates reads the issues of findings from Beads in batches. br fails
+// a batch when any issue is missing, so failed batches are retried one
+// issue at a time; issues br reports as not found get a zero state.
+func (s *syncCmd) issueStates(findings []*db.Finding) (map[string]sync.IssueState, error) {
+	var ids []string
+	seen := make(map[string]bool)
+	for _, f := range findings {
+		if !seen[f.IssueID] {
+			seen[f.IssueID] = true
+			ids = append(ids, f.IssueID)
+		}
+	}
+
+	states := make(map[string]sync.IssueState, len(ids))
+	for start := 0; start < len(ids); start += issueStateBatch {
+		batch := ids[start:min(start+issueStateBatch, len(ids))]
+		issues, err := s.backend.showIssues(batch)
+		if err == nil {
+			for _, issue := range issues {
+				states[issue.ID] = sync.IssueState{Status: issue.Status, CloseReason: issue.CloseReason}
+			}
+			continue
+		}
+
+		for _, id := range batch {
+			issue, err := s.backend.showIssue(id)
+			if err != nil {
+				if !strings.Contains(strings.ToLower(err.Error()), "not found") {
+					return nil, err
+				}
+				states[id] = sync.IssueState{}
+				continue
+			}
+			states[issue.ID] = sync.IssueState{Status: issue.Status, CloseReason: issue.CloseReason}
+		}
+	}
+
+	return states, nil
+}
+
+// relink links new findings that have no database row to an issue already
+// labelled with their fingerprint, e.g. after the database was lost, instead
+// of filing a duplicate. Relinked findings are removed from result.New.
+// Returns false if a write failed.
+func (s *syncCmd) relink(database *db.TrackingDB, result *sync.DiffResult, scanID string, now time.Time) bool {
+	if len(result.New) == 0 {
+		return true
+	}
+
+	fps := make([]string, len(result.New))
+	untracked := 0
+	for i, f := range result.New {
+		fps[i] = database.Fingerprint(f.File, f.Category, f.Message, f.CodeSnippet, f.SourceContext, f.Line)
+		if row, err := database.Get(fps[i]); err == nil && row == nil {
+			untracked++
+		}
+	}
+	if untracked == 0 {
+		return true
+	}
+
+	// Every issue strung files carries the "ubs" label
+	issues, err := s.backend.listIssues("ubs")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not list issues from Beads, skipping relink: %v\n", firstLine(err.Error()))
+		return true
+	}
+	byLabel := make(map[string]BeadsIssueInfo)
+	for _, issue := range issues {
+		for _, label := range issue.Labels {
+			if strings.HasPrefix(label, "fp:") {
+				byLabel[label] = issue
+			}
+		}
+	}
+
+	ok := true
+	remaining := result.New[:0]
+	for i, finding := range result.New {
+		fp := fps[i]
+		issue, found := byLabel[fingerprintLabel(fp)]
+		if row, err := database.Get(fp); !found || err != nil || row != nil {
+			remaining = append(remaining, finding)
+			continue
+		}
+
+		hash, err := s.issueHash(finding, fp, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR transforming finding: %v\n", err)
+			ok = false
+			remaining = append(remaining, finding)
+			continue
+		}
+		row := trackedFinding(fp, issue.ID, finding, hash, now, now, scanID)
+		if err := database.Store(row); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR storing finding: %v (issue: %s)\n", err, issue.ID)
+			ok = false
+			continue
+		}
+		if err := database.SetIssueStatus(fp, issue.Status); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR recording issue status: %v\n", err)
+			ok = false
+		}
+		ok = recordEvent(database, &db.Event{
+			Fingerprint: fp, ScanID: scanID, Event: db.EventNew,
+			Severity: finding.Severity, Line: finding.Line, Detail: "relinked " + issue.ID, CreatedAt: now,
+		}) && ok
+		fmt.Fprintf(os.Stderr, "Relinked: %s:%d → %s\n", finding.File, finding.Line, issue.ID)
+
+		if issue.Status == beads.StatusClosed {
+			row.IssueStatus = issue.Status
+			ok = s.handleClosed(database, row, sync.IssueState{Status: issue.Status, CloseReason: issue.CloseReason},
+				scanID, now) && ok
+		}
+	}
+	result.New = remaining
+	return ok
+}
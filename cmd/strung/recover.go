@@ -108,6 +108,24 @@ func (r *recoverCmd) run() int {
 		issues = append(issues, fmt.Sprintf("Found %d duplicate fingerprints", duplicates))
 	}
 
+	// Check stored payloads still re-derive their fingerprints
+	drifted, missingPayload := 0, 0
+	for _, f := range findings {
+		if !f.HasPayload() {
+			missingPayload++
+			continue
+		}
+		if f.RecomputeFingerprint() != f.Fingerprint {
+			drifted++
+		}
+	}
+	if drifted > 0 {
+		issues = append(issues, fmt.Sprintf("Found %d findings whose stored payload no longer matches their fingerprint", drifted))
+	}
+	if missingPayload > 0 {
+		fmt.Fprintf(os.Stderr, "\n%d findings have no stored payload (backfilled on next sync)\n", missingPayload)
+	}
+
 	// Report issues
 	if len(issues) > 0 {
 		fmt.Fprintf(os.Stderr, "\nIssues found:\n")
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/report"
+)
+
+func reportUsage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung report <command> [flags]
+
+Generate reports from the tracking database history.
+
+Commands:
+  trends      Open findings over time, introduced vs fixed, time to resolve
+  html        Static HTML site of the current scan and tracked findings
+
+Run 'strung report <command> --help' for command-specific help.
+`)
+}
+
+// runReportCommand dispatches "strung report" subcommands
+func runReportCommand(args []string) int {
+	if len(args) == 0 || isHelpArg(args[0]) {
+		reportUsage()
+		return ExitSuccess
+	}
+
+	switch args[0] {
+	case "trends":
+		fs := flag.NewFlagSet("report trends", flag.ExitOnError)
+		cmd := newReportTrendsCmd()
+		cmd.flags(fs)
+
+		if hasHelpArg(args[1:]) {
+			cmd.usage()
+			return ExitSuccess
+		}
+
+		fs.Parse(args[1:])
+		return cmd.run()
+
+	case "html":
+		fs := flag.NewFlagSet("report html", flag.ExitOnError)
+		cmd := newReportHTMLCmd()
+		cmd.flags(fs)
+
+		if hasHelpArg(args[1:]) {
+			cmd.usage()
+			return ExitSuccess
+		}
+
+		fs.Parse(args[1:])
+		return cmd.run()
+
+	default:
+		fmt.Fprintf(os.Stderr, "Unknown report command: %s\n", args[0])
+		reportUsage()
+		return ExitUsageError
+	}
+}
+
+type reportTrendsCmd struct {
+	projectOptions // --project
+
+	dbPath string
+	period string
+	since  string
+	format string
+	output string
+}
+
+func newReportTrendsCmd() *reportTrendsCmd {
+	return &reportTrendsCmd{}
+}
+
+func (r *reportTrendsCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	r.projectFlag(fs)
+	fs.StringVar(&r.period, "period", report.PeriodWeek, "Period length: day, week, month")
+	fs.StringVar(&r.since, "since", "", "Report start (date, RFC3339 or age like 90d; default: 12 periods)")
+	fs.StringVar(&r.format, "format", "markdown", "Output format: "+strings.Join(report.TrendFormats, ", "))
+	fs.StringVar(&r.output, "output", "", "Write the report to this file instead of stdout")
+}
+
+func (r *reportTrendsCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung report trends [flags]
+
+Report open findings over time by severity, findings introduced vs fixed per
+period, mean and median time to resolve per category, and the age of open
+findings.
+
+Flags:
+  --db-path PATH   Path to tracking database (default: .strung.db)
+  --project NAME   Only report project NAME (default: all projects)
+  --period PERIOD  day, week or month (default: week)
+  --since WHEN     Report start: date, RFC3339 or age like 90d (default: 12 periods)
+  --format FORMAT  csv, json, markdown (ASCII charts) or html (SVG charts)
+                   (default: markdown)
+  --output FILE    Write to FILE instead of stdout
+
+Examples:
+  # Weekly summary for a PR or wiki page
+  strung report trends
+
+  # Monthly HTML report for the last year
+  strung report trends --period=month --since=365d --format=html --output=trends.html
+
+  # Spreadsheet export
+  strung report trends --format=csv > trends.csv
+`)
+}
+
+func (r *reportTrendsCmd) run() int {
+	now := time.Now()
+	opts := report.TrendOptions{Period: r.period}
+	if r.since != "" {
+		since, err := parseWhen(r.since, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --since: %v\n", err)
+			return ExitUsageError
+		}
+		opts.Since = since
+	}
+	if !validFormat(r.format, report.TrendFormats) {
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q (use %s)\n", r.format, strings.Join(report.TrendFormats, ", "))
+		return ExitUsageError
+	}
+
+	database, err := openExistingDB(r.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+	r.scopeProject(database)
+
+	trends, err := report.BuildTrends(database, now, opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitUsageError
+	}
+
+	if err := writeOutput(r.output, func(w io.Writer) error { return trends.Write(w, r.format) }); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitInputError
+	}
+	return ExitSuccess
+}
+
+// writeOutput runs write against path, or stdout when path is empty
+func writeOutput(path string, write func(io.Writer) error) error {
+	if path == "" {
+		return write(os.Stdout)
+	}
+
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create output: %w", err)
+	}
+	if err := write(f); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+func validFormat(format string, formats []string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
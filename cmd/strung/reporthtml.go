@@ -0,0 +1,193 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/git"
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/report"
+	"github.com/TheEditor/strung/pkg/source"
+	"github.com/TheEditor/strung/pkg/sync"
+	"github.com/TheEditor/strung/pkg/transform"
+)
+
+type reportHTMLCmd struct {
+	linkOptions    // --repo-url, --repo-branch, --forge, --commit
+	projectOptions // --project
+
+	dbPath      string
+	input       string
+	outDir      string
+	title       string
+	issueURL    string
+	minSeverity string
+	sourceRoot  string
+	contextLen  int
+	threshold   float64
+	verbose     bool
+}
+
+func newReportHTMLCmd() *reportHTMLCmd {
+	return &reportHTMLCmd{}
+}
+
+func (r *reportHTMLCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&r.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	r.projectFlag(fs)
+	fs.StringVar(&r.input, "input", "", "UBS JSON of the current scan ('-' for stdin; default: tracked state only)")
+	fs.StringVar(&r.outDir, "output", "strung-report", "Directory to write the site to")
+	fs.StringVar(&r.title, "title", "", "Report title (default: strung report)")
+	fs.StringVar(&r.issueURL, "issue-url", "", "Issue link template, {id} is replaced with the Beads issue ID")
+	fs.StringVar(&r.minSeverity, "min-severity", "warning", "Minimum severity of the current scan (critical, warning, info)")
+	r.linkFlags(fs)
+	fs.StringVar(&r.sourceRoot, "source-root", "", "Show source context read from files under this directory")
+	fs.IntVar(&r.contextLen, "context-lines", 3, "Lines of code context shown around findings (with --source-root)")
+	fs.Float64Var(&r.threshold, "match-threshold", sync.DefaultMatchThreshold, "Similarity for re-matching moved findings (0 disables)")
+	fs.BoolVar(&r.verbose, "verbose", false, "Enable verbose output")
+}
+
+func (r *reportHTMLCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung report html [flags]
+
+Render a self-contained static HTML site: a summary, a filterable finding
+table, one page per file with code snippets, links to Beads issues and
+repository permalinks, and (with --input) the changes of the current scan
+against the tracking database. The database is not modified.
+
+Flags:
+  --db-path PATH        Path to tracking database (default: .strung.db)
+  --project NAME        Only include project NAME (default: all projects; with
+                        --input, the scan's project)
+  --input FILE          UBS JSON of the current scan, '-' for stdin
+                        (default: render tracked findings only)
+  --output DIR          Directory to write the site to (default: strung-report)
+  --title TEXT          Report title (default: strung report)
+  --issue-url TEMPLATE  Issue link, {id} is replaced with the issue ID
+                        (e.g. https://tracker.example.com/issues/{id})
+  --min-severity LEVEL  Minimum severity of the current scan (default: warning)
+  --repo-url URL        Repository URL for file links (default: origin remote)
+  --repo-branch NAME    Branch for file links (default: current branch, else main)
+  --forge NAME          Link format (default: auto)
+  --commit SHA          Commit for permalinks (default: HEAD)
+  --source-root DIR     Show source context read from files under DIR
+  --context-lines N     Lines of context around findings (default: 3)
+  --match-threshold F   Similarity for re-matching moved findings (default: %.1f)
+  --verbose             Enable verbose output
+
+Examples:
+  # Report for CI artifacts before syncing
+  ubs --format=json src/ > scan.json
+  strung report html --input=scan.json --output=public/strung
+  strung sync < scan.json
+
+  # Tracked state only
+  strung report html --issue-url='https://tracker.example.com/issues/{id}'
+`, sync.DefaultMatchThreshold)
+}
+
+func (r *reportHTMLCmd) run() int {
+	validSeverities := map[string]bool{"critical": true, "warning": true, "info": true}
+	if !validSeverities[r.minSeverity] {
+		fmt.Fprintf(os.Stderr, "Error: invalid severity %q (use: critical, warning, info)\n", r.minSeverity)
+		return ExitUsageError
+	}
+	if r.contextLen < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --context-lines must not be negative\n")
+		return ExitUsageError
+	}
+	if r.threshold < 0 || r.threshold > 1 {
+		fmt.Fprintf(os.Stderr, "Error: --match-threshold must be between 0 and 1\n")
+		return ExitUsageError
+	}
+
+	database, err := openExistingDB(r.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+
+	repo, err := git.Open(".")
+	if err != nil {
+		repo = nil
+	}
+	r.applyGitDefaults(repo, r.verbose)
+	if r.links, err = transform.LinkBuilderFor(r.forge, r.repoURL); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitUsageError
+	}
+
+	transformer := transform.NewTransformerWithConfig(&transform.TransformConfig{
+		RepoURL:    r.repoURL,
+		RepoBranch: r.repoBranch,
+		Commit:     r.commit,
+		PathPrefix: r.pathPrefix,
+		Links:      r.links,
+	})
+	opts := report.SiteOptions{
+		Title:        r.title,
+		Commit:       r.commit,
+		Permalink:    transformer.Permalink,
+		IssueURL:     r.issueURL,
+		ContextLines: r.contextLen,
+	}
+	if r.sourceRoot != "" {
+		opts.Source = source.NewReader(r.sourceRoot)
+	}
+	r.scopeProject(database)
+
+	if r.input != "" {
+		scan, err := readScan(r.input)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitInputError
+		}
+		r.scopeScan(database, scan)
+		findings := scan.FilterBySeverity(r.minSeverity)
+		if opts.Source != nil {
+			loadSourceContext(opts.Source, findings)
+		}
+
+		differ := sync.NewDiffer(database)
+		differ.MatchThreshold = r.threshold
+		if r.threshold > 0 {
+			differ.Renames = detectRenames(database, repo, r.verbose)
+		}
+		if opts.Diff, err = differ.Diff(findings); err != nil {
+			fmt.Fprintf(os.Stderr, "Error computing diff: %v\n", err)
+			return ExitDBError
+		}
+	}
+
+	site, err := report.BuildSite(database, time.Now(), opts)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	if err := site.WriteSite(r.outDir); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitInputError
+	}
+
+	fmt.Fprintf(os.Stderr, "Wrote %d findings in %d files to %s\n", len(site.Findings), len(site.Files),
+		filepath.Join(r.outDir, "index.html"))
+	return ExitSuccess
+}
+
+// readScan parses UBS JSON from a file, or stdin for "-"
+func readScan(path string) (*parser.UBSReport, error) {
+	if path == "-" {
+		return parser.ParseUBS(os.Stdin)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open scan: %w", err)
+	}
+	defer f.Close()
+	return parser.ParseUBS(f)
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/git"
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/patch"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+// reviewOptions holds the flags that limit findings to the changes of a
+// pull request
+type reviewOptions struct {
+	changedFrom   string
+	patchFile     string
+	changedScope  string
+	changedRadius int
+}
+
+func (o *reviewOptions) reviewFlags(fs *flag.FlagSet) {
+	fs.StringVar(&o.changedFrom, "changed-from", "", "Only report findings changed since this git revision or range (e.g. origin/main...HEAD)")
+	fs.StringVar(&o.patchFile, "patch", "", "Only report findings changed by this unified diff file")
+	fs.StringVar(&o.changedScope, "changed-scope", patch.ScopeLines, "With --changed-from/--patch: lines or files")
+	fs.IntVar(&o.changedRadius, "changed-radius", 0, "With --changed-scope=lines: also keep findings this many lines from a change")
+}
+
+// changeFilter loads the diff named by --changed-from or --patch. Returns
+// nil when review mode is off.
+func (o *reviewOptions) changeFilter(verbose bool) (*patch.Filter, error) {
+	if o.changedFrom == "" && o.patchFile == "" {
+		return nil, nil
+	}
+	if o.changedFrom != "" && o.patchFile != "" {
+		return nil, fmt.Errorf("--changed-from and --patch are mutually exclusive")
+	}
+	if o.changedScope != patch.ScopeLines && o.changedScope != patch.ScopeFiles {
+		return nil, fmt.Errorf("invalid --changed-scope %q (use: lines, files)", o.changedScope)
+	}
+	if o.changedRadius < 0 {
+		return nil, fmt.Errorf("--changed-radius must not be negative")
+	}
+
+	var (
+		changes *patch.Changes
+		err     error
+	)
+	if o.changedFrom != "" {
+		changes, err = gitChanges(o.changedFrom)
+	} else {
+		changes, err = patchChanges(o.patchFile)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "Review mode: %d changed files (scope=%s, radius=%d)\n",
+			len(changes.Files()), o.changedScope, o.changedRadius)
+	}
+	return &patch.Filter{Changes: changes, Scope: o.changedScope, Radius: o.changedRadius}, nil
+}
+
+func gitChanges(rev string) (*patch.Changes, error) {
+	repo, err := git.Open(".")
+	if err != nil {
+		return nil, fmt.Errorf("--changed-from needs a git checkout: %w", err)
+	}
+	diff, err := repo.Diff(rev)
+	if err != nil {
+		return nil, fmt.Errorf("diff %s: %w", rev, err)
+	}
+	prefix, err := repo.Prefix()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := patch.Parse(strings.NewReader(diff))
+	if err != nil {
+		return nil, err
+	}
+	// The diff is relative to the current directory, like scanner paths
+	changes.Resolve(path.Join(repo.Root, prefix), "")
+	return changes, nil
+}
+
+func patchChanges(path string) (*patch.Changes, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open patch: %w", err)
+	}
+	defer f.Close()
+	changes, err := patch.Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	// Patches are relative to the repository root. Outside a checkout,
+	// scanner paths can only match by suffix.
+	if repo, err := git.Open("."); err == nil {
+		if prefix, err := repo.Prefix(); err == nil {
+			changes.Resolve(repo.Root, prefix)
+		}
+	}
+	return changes, nil
+}
+
+// filterDiff keeps the classified findings within the changes. Fixed
+// findings only have base-side line numbers, so they are kept when their
+// file is part of the diff.
+func filterDiff(d *sync.DiffResult, filter *patch.Filter) {
+	keep := func(f parser.UBSFinding) bool { return filter.Keep(f.File, f.Line) }
+
+	d.New = filter.Apply(d.New)
+	d.Changed = filterChanges(d.Changed, keep)
+	d.Unchanged = filterChanges(d.Unchanged, keep)
+
+	moved := d.Moved[:0]
+	for _, m := range d.Moved {
+		if keep(m.Current) {
+			moved = append(moved, m)
+		}
+	}
+	d.Moved = moved
+
+	resolved := d.Resolved[:0]
+	for _, r := range d.Resolved {
+		if filter.Changes.HasFile(r.File) {
+			resolved = append(resolved, r)
+		}
+	}
+	d.Resolved = resolved
+}
+
+func filterChanges(records []sync.ChangeRecord, keep func(parser.UBSFinding) bool) []sync.ChangeRecord {
+	kept := records[:0]
+	for _, c := range records {
+		if keep(c.Current) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,240 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/config"
+)
+
+type showCmd struct {
+	projectOptions // --project
+
+	dbPath     string
+	configPath string
+	format     string
+	offline    bool
+	ref        string
+}
+
+func newShowCmd() *showCmd {
+	return &showCmd{}
+}
+
+func (s *showCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&s.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	fs.StringVar(&s.configPath, "config", "", "Path to config file (default: .strung.json if present)")
+	s.projectFlag(fs)
+	fs.StringVar(&s.format, "format", "text", "Output format: text, json")
+	fs.BoolVar(&s.offline, "offline", false, "Do not query br for the linked issue")
+}
+
+func (s *showCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung show [flags] <fingerprint|issue-id>
+
+Print a tracked finding's full record, its linked Beads issue and its event
+history. Fingerprints may be abbreviated to any unique prefix.
+
+Flags:
+  --db-path PATH   Path to tracking database (default: .strung.db)
+  --config PATH    Path to config file (default: .strung.json if present)
+  --project NAME   Only match findings of project NAME (default: all projects)
+  --format FORMAT  text or json (default: text)
+  --offline        Do not query br for the linked issue
+
+Examples:
+  strung show bd-42
+  strung show 3f9a2c1b7e04
+  strung show --format=json bd-42
+`)
+}
+
+// parse reads flags and the finding reference, allowing flags on either
+// side of it
+func (s *showCmd) parse(fs *flag.FlagSet, args []string) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("missing fingerprint or issue ID")
+	}
+	s.ref = fs.Arg(0)
+	if err := fs.Parse(fs.Args()[1:]); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(fs.Args(), " "))
+	}
+	return nil
+}
+
+// showJSON is the machine-readable form of strung show
+type showJSON struct {
+	Finding    findingJSON     `json:"finding"`
+	Issue      *BeadsIssueInfo `json:"issue,omitempty"`
+	IssueError string          `json:"issue_error,omitempty"`
+	Events     []eventJSON     `json:"events"`
+}
+
+type eventJSON struct {
+	Event     string    `json:"event"`
+	ScanID    string    `json:"scan_id,omitempty"`
+	Severity  string    `json:"severity,omitempty"`
+	Line      int       `json:"line,omitempty"`
+	Detail    string    `json:"detail,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func (s *showCmd) run() int {
+	if s.format != "text" && s.format != "json" {
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q (use text or json)\n", s.format)
+		return ExitUsageError
+	}
+
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitUsageError
+	}
+
+	database, err := openExistingDB(s.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+	s.scopeProject(database)
+
+	f, err := database.Lookup(s.ref)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitInputError
+	}
+	if f == nil {
+		fmt.Fprintf(os.Stderr, "Error: no finding matches %q\n", s.ref)
+		return ExitInputError
+	}
+
+	events, err := database.GetEvents(f.Fingerprint)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+
+	out := showJSON{Finding: newFindingJSON(f), Events: make([]eventJSON, 0, len(events))}
+	for _, e := range events {
+		out.Events = append(out.Events, eventJSON{
+			Event:     e.Event,
+			ScanID:    e.ScanID,
+			Severity:  e.Severity,
+			Line:      e.Line,
+			Detail:    e.Detail,
+			CreatedAt: e.CreatedAt,
+		})
+	}
+
+	// The issue is best effort: the record is still useful without br
+	if !s.offline && f.IssueID != "" {
+		backend := newBeadsBackend(cfg, f.Project)
+		if err := backend.check(); err != nil {
+			out.IssueError = "br CLI not available"
+		} else if issue, err := backend.showIssue(f.IssueID); err != nil {
+			out.IssueError = firstLine(err.Error())
+		} else {
+			out.Issue = issue
+		}
+	}
+
+	if s.format == "json" {
+		if err := writeJSON(out); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitInputError
+		}
+		return ExitSuccess
+	}
+
+	writeShowText(os.Stdout, out)
+	return ExitSuccess
+}
+
+func writeShowText(w io.Writer, out showJSON) {
+	f := out.Finding
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(tw, "%s:\t%s\n", name, value)
+		}
+	}
+	field("Fingerprint", fmt.Sprintf("%s (v%d)", f.Fingerprint, f.FingerprintVersion))
+	field("Status", f.Status)
+	if f.SuppressedAt != nil {
+		field("Suppressed", formatTime(*f.SuppressedAt))
+	}
+	field("Project", f.Project)
+	field("Branch", f.Branch)
+	field("Severity", f.Severity)
+	field("Category", f.Category)
+	location := fmt.Sprintf("%s:%d", f.File, f.Line)
+	if f.Column > 0 {
+		location += fmt.Sprintf(":%d", f.Column)
+	}
+	field("Location", location)
+	field("Message", f.Message)
+	field("Suggestion", f.Suggestion)
+	field("Tool", strings.TrimSpace(f.Tool+" "+f.RuleID))
+	field("First seen", formatTime(f.FirstSeen))
+	field("Last seen", formatTime(f.LastSeen))
+	if f.ResolvedAt != nil {
+		field("Resolved", formatTime(*f.ResolvedAt))
+	}
+	field("Last scan", f.LastScanID)
+
+	issue := f.IssueID
+	switch {
+	case out.Issue != nil:
+		issue = fmt.Sprintf("%s [%s] %s", out.Issue.ID, out.Issue.Status, out.Issue.Title)
+		if out.Issue.Assignee != "" {
+			issue += " (" + out.Issue.Assignee + ")"
+		}
+	case out.IssueError != "":
+		issue += " (" + out.IssueError + ")"
+	}
+	field("Issue", issue)
+	tw.Flush()
+
+	if f.CodeSnippet != "" {
+		fmt.Fprintf(w, "\nCode:\n")
+		for _, line := range strings.Split(strings.TrimRight(f.CodeSnippet, "\n"), "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+
+	fmt.Fprintf(w, "\nHistory (%d events):\n", len(out.Events))
+	tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, e := range out.Events {
+		line := ""
+		if e.Line > 0 {
+			line = fmt.Sprintf("line %d", e.Line)
+		}
+		fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\t%s\n", formatTime(e.CreatedAt), e.Event, e.Severity, line, e.Detail)
+	}
+	tw.Flush()
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Local().Format("2006-01-02 15:04:05")
+}
+
+func firstLine(s string) string {
+	line, _, _ := strings.Cut(s, "\n")
+	return line
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/report"
+)
+
+type statusCmd struct {
+	projectOptions // --project
+
+	dbPath string
+	format string
+	top    int
+}
+
+func newStatusCmd() *statusCmd {
+	return &statusCmd{}
+}
+
+func (s *statusCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&s.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	s.projectFlag(fs)
+	fs.StringVar(&s.format, "format", "table", "Output format: table, json, markdown")
+	fs.IntVar(&s.top, "top", 10, "Number of directories and files to list (0 = all)")
+}
+
+func (s *statusCmd) usage() {
+	fmt.Fprintf(os.Stderr, `Usage: strung status [flags]
+
+Summarize the tracking database: open/resolved findings by severity,
+category, directory and age, the last sync, pending operations and the
+files with the most open findings. A database with several projects is
+summarized across all of them, with a per-project breakdown.
+
+Flags:
+  --db-path PATH   Path to tracking database (default: .strung.db)
+  --project NAME   Only summarize project NAME (default: all projects)
+  --format FORMAT  Output format: table, json, markdown (default: table)
+  --top N          Directories and files to list (default: 10, 0 = all)
+
+Examples:
+  # Dashboard in the terminal
+  strung status
+
+  # Post as a PR comment
+  strung status --format=markdown | gh pr comment --body-file -
+`)
+}
+
+func (s *statusCmd) run() int {
+	switch s.format {
+	case "table", "json", "markdown":
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid format %q (use table, json or markdown)\n", s.format)
+		return ExitUsageError
+	}
+
+	database, err := openExistingDB(s.dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitDBError
+	}
+	defer database.Close()
+	s.scopeProject(database)
+
+	st, err := report.BuildStatus(database, time.Now(), s.top)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading database: %v\n", err)
+		return ExitDBError
+	}
+
+	if err := st.Write(os.Stdout, s.format); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitInputError
+	}
+	return ExitSuccess
+}
+
+// openExistingDB opens a tracking database read-only. Unlike db.Open it
+// fails for a missing file instead of creating an empty database, so a
+// mistyped --db-path is reported, and never migrates the schema.
+func openExistingDB(path string) (*db.TrackingDB, error) {
+	if _, err := os.Stat(path); err != nil {
+		return nil, err
+	}
+	database, err := db.OpenReadOnly(path)
+	if errors.Is(err, db.ErrSchemaOutdated) {
+		return nil, fmt.Errorf("%w (run strung db migrate --db-path=%s)", err, path)
+	}
+	return database, err
+}
@@ -1,14 +1,24 @@
 package main
 
 import (
+	"bytes"
+	"crypto/sha256"
 	"flag"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 	"time"
 
+	"github.com/TheEditor/strung/pkg/beads"
+	"github.com/TheEditor/strung/pkg/codeowners"
+	"github.com/TheEditor/strung/pkg/config"
 	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/git"
 	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/source"
 	"github.com/TheEditor/strung/pkg/sync"
+	"github.com/TheEditor/strung/pkg/taxonomy"
 	"github.com/TheEditor/strung/pkg/transform"
 )
 
@@ -21,13 +31,34 @@ const (
 )
 
 type syncCmd struct {
+	linkOptions // --repo-url, --repo-branch, --forge, --commit
+
+	configPath  string
 	dbPath      string
+	project     string
 	autoClose   bool
+	onClosed    string
+	noComments  bool
 	dryRun      bool
 	minSeverity string
-	repoURL     string
-	repoBranch  string
+	cfg         *config.Config
+	taxonomy    taxonomy.Taxonomy
+	owners      *codeowners.Ruleset
+	blame       bool
+	blamer      *git.Blamer
+	sourceRoot  string
+	contextLen  int
+	source      *source.Reader
+	keepScans   int
+	keepDays    int
+	threshold   float64
+	branch      string
+	baseBranch  string
 	verbose     bool
+
+	backend    *beadsBackend
+	reconciled *sync.Reconciliation
+	skip       map[string]bool // Fingerprints whose issues must not be written
 }
 
 func newSyncCmd() *syncCmd {
@@ -35,12 +66,23 @@ func newSyncCmd() *syncCmd {
 }
 
 func (s *syncCmd) flags(fs *flag.FlagSet) {
+	fs.StringVar(&s.configPath, "config", "", "Path to config file (default: .strung.json if present)")
 	fs.StringVar(&s.dbPath, "db-path", ".strung.db", "Path to tracking database")
+	fs.StringVar(&s.project, "project", "", "Project to track findings under (default: config, else none; auto: the scanned directory's name)")
 	fs.BoolVar(&s.autoClose, "auto-close", false, "Automatically close resolved issues")
+	fs.StringVar(&s.onClosed, "on-closed", closedSuppress, "Issues closed in Beads while the finding persists: suppress, reopen, comment")
+	fs.BoolVar(&s.noComments, "no-comments", false, "Do not comment on issues when findings change, regress or are closed")
 	fs.BoolVar(&s.dryRun, "dry-run", false, "Show actions without executing")
 	fs.StringVar(&s.minSeverity, "min-severity", "warning", "Minimum severity (critical, warning, info)")
-	fs.StringVar(&s.repoURL, "repo-url", "", "Repository URL for file links (e.g., https://github.com/user/repo)")
-	fs.StringVar(&s.repoBranch, "repo-branch", "main", "Repository branch for file links")
+	s.linkFlags(fs)
+	fs.IntVar(&s.keepScans, "keep-scans", 0, "Keep only the newest N scans in history (0 = unlimited)")
+	fs.IntVar(&s.keepDays, "keep-days", 0, "Drop scan history older than N days (0 = unlimited)")
+	fs.StringVar(&s.sourceRoot, "source-root", "", "Read code context for findings without a snippet from files under this directory")
+	fs.IntVar(&s.contextLen, "context-lines", 3, "Lines of code context shown around findings (with --source-root)")
+	fs.BoolVar(&s.blame, "blame", false, "Record who last changed flagged lines (git blame)")
+	fs.Float64Var(&s.threshold, "match-threshold", sync.DefaultMatchThreshold, "Similarity for re-matching moved findings (0 disables)")
+	fs.StringVar(&s.branch, "branch", "", "Track the scan in this branch's view (default: the base branch)")
+	fs.StringVar(&s.baseBranch, "base-branch", "main", "Branch whose view is the shared base")
 	fs.BoolVar(&s.verbose, "verbose", false, "Enable verbose output")
 }
 
@@ -48,14 +90,35 @@ func (s *syncCmd) usage() {
 	fmt.Fprintf(os.Stderr, `Usage: strung sync [flags]
 
 Read UBS JSON from stdin, incrementally sync findings to Beads issues.
+Issue state is read back from Beads: findings whose issue was closed by hand
+(e.g. as won't-fix) are not updated or filed again, and findings whose issue
+is already closed are resolved.
 
 Flags:
+  --config PATH         Path to config file (default: .strung.json if present)
   --db-path PATH        Path to tracking database (default: .strung.db)
+  --project NAME        Project to track findings under (default: config "project",
+                        else none); "auto" names it after the scanned directory
   --auto-close          Automatically close resolved issues
+  --on-closed POLICY    Issues closed in Beads while the finding persists: suppress
+                        (leave closed), reopen, or comment then suppress (default: suppress)
+  --no-comments         Do not comment on issues when findings change severity, move,
+                        get a new message, regress, or are about to be auto-closed
   --dry-run             Show actions without executing
   --min-severity LEVEL  Minimum severity: critical, warning, info (default: warning)
-  --repo-url URL        Repository URL for file links
-  --repo-branch BRANCH  Repository branch (default: main)
+  --repo-url URL        Repository URL for file links (default: from git remote "origin")
+  --repo-branch BRANCH  Repository branch (default: current branch, else main)
+  --forge NAME          Link format: auto, azure, bitbucket, gitea, github, gitlab,
+                        sourcehut (default: auto, detected from the repo URL host)
+  --commit SHA          Git commit the scan was taken at (default: HEAD); pins file links
+  --keep-scans N        Keep only the newest N scans in history (default: unlimited)
+  --keep-days N         Drop scan history older than N days (default: unlimited)
+  --source-root DIR     Read code context for findings without a snippet under DIR
+  --context-lines N     Lines of context around findings with --source-root (default: 3)
+  --blame               Record who last changed flagged lines (git blame)
+  --match-threshold N   Similarity (0-1) for re-matching moved findings (default: 0.7, 0 disables)
+  --branch NAME         Track the scan in NAME's view (default: the base branch)
+  --base-branch NAME    Branch whose view is the shared base (default: main)
   --verbose             Enable verbose output
 
 Examples:
@@ -68,28 +131,45 @@ Examples:
   # Dry run preview
   ubs --format=json src/ | strung sync --dry-run
 
-  # With GitHub links
+  # With GitHub links (detected automatically inside a git checkout)
   ubs --format=json src/ | strung sync --repo-url=https://github.com/user/repo
 
+  # Feature branch: reuse main's issues, leave them open until merge
+  ubs --format=json src/ | strung sync --branch=feature/login
+
 See docs/SYNC.md for complete documentation.
 `)
 }
 
 func (s *syncCmd) run() int {
-	// Verify br CLI available (unless dry-run)
-	if !s.dryRun {
-		if err := checkBeadsCLI(); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-			return ExitSyncError
-		}
-	}
-
 	// Validate severity
 	validSeverities := map[string]bool{"critical": true, "warning": true, "info": true}
 	if !validSeverities[s.minSeverity] {
 		fmt.Fprintf(os.Stderr, "Error: invalid severity %q (use: critical, warning, info)\n", s.minSeverity)
 		return ExitSyncUsageError
 	}
+	if s.contextLen < 0 {
+		fmt.Fprintf(os.Stderr, "Error: --context-lines must not be negative\n")
+		return ExitSyncUsageError
+	}
+	if s.threshold < 0 || s.threshold > 1 {
+		fmt.Fprintf(os.Stderr, "Error: --match-threshold must be between 0 and 1\n")
+		return ExitSyncUsageError
+	}
+	switch s.onClosed {
+	case closedSuppress, closedReopen, closedComment:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: invalid --on-closed %q (use: suppress, reopen, comment)\n", s.onClosed)
+		return ExitSyncUsageError
+	}
+
+	cfg, err := config.Load(s.configPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitSyncUsageError
+	}
+	s.cfg = cfg
+	s.taxonomy = taxonomy.Default().With(cfg.Taxonomy)
 
 	// Open/create tracking DB
 	database, err := db.Open(s.dbPath)
@@ -99,12 +179,50 @@ func (s *syncCmd) run() int {
 	}
 	defer database.Close()
 
+	if s.branch != s.baseBranch {
+		database.SetBranch(s.branch)
+	}
 	if s.verbose {
 		fmt.Fprintf(os.Stderr, "Using database: %s\n", s.dbPath)
+		if database.Branch() != "" {
+			fmt.Fprintf(os.Stderr, "Branch view: %s (base: %s)\n", database.Branch(), s.baseBranch)
+		}
+	}
+
+	// Fill link/commit defaults from the local checkout (no network access)
+	repo, err := git.Open(".")
+	if err != nil {
+		repo = nil
+		if s.verbose {
+			fmt.Fprintf(os.Stderr, "Git detection skipped: %v\n", err)
+		}
+	}
+	s.applyGitDefaults(repo, s.verbose)
+
+	if s.links, err = transform.LinkBuilderFor(s.forge, s.repoURL); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitSyncUsageError
+	}
+
+	if err := s.loadOwners(repo); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitSyncInputError
+	}
+	if s.blame || s.cfg.Blame.Enabled {
+		if repo != nil {
+			s.blamer = git.NewBlamer(repo)
+		} else {
+			fmt.Fprintf(os.Stderr, "Warning: blame needs a git checkout, skipping\n")
+		}
 	}
 
-	// Parse UBS JSON from stdin
-	report, err := parser.ParseUBS(os.Stdin)
+	// Parse UBS JSON from stdin (keeping the raw bytes for the scan digest)
+	input, err := io.ReadAll(os.Stdin)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: read stdin: %v\n", err)
+		return ExitSyncInputError
+	}
+	report, err := parser.ParseUBS(bytes.NewReader(input))
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		return ExitSyncInputError
@@ -114,134 +232,646 @@ func (s *syncCmd) run() int {
 		fmt.Fprintf(os.Stderr, "Parsed %d findings from %s\n", len(report.Findings), report.Project)
 	}
 
+	// Scope the database and issue tracker to the project
+	project := s.projectName(report)
+	if !s.useProject(database, project) {
+		return ExitSyncError
+	}
+	s.backend = newBeadsBackend(s.cfg, project)
+	if s.verbose && project != "" {
+		fmt.Fprintf(os.Stderr, "Project: %s\n", project)
+	}
+
+	// Verify br CLI available (unless dry-run)
+	if !s.dryRun {
+		if err := s.backend.check(); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return ExitSyncError
+		}
+	}
+
 	// Filter by severity
 	findings := report.FilterBySeverity(s.minSeverity)
 	if s.verbose {
 		fmt.Fprintf(os.Stderr, "After severity filter (%s): %d findings\n", s.minSeverity, len(findings))
 	}
 
+	// Read context for findings without a snippet so fingerprints use code
+	if s.sourceRoot != "" {
+		s.source = source.NewReader(s.sourceRoot)
+		loaded := loadSourceContext(s.source, findings)
+		if s.verbose {
+			fmt.Fprintf(os.Stderr, "Loaded source context for %d findings without a snippet from %s\n", loaded, s.sourceRoot)
+		}
+	}
+
 	// Compute diff
 	differ := sync.NewDiffer(database)
+	differ.MatchThreshold = s.threshold
+	if s.threshold > 0 {
+		differ.Renames = detectRenames(database, repo, s.verbose)
+	}
 	diffResult, err := differ.Diff(findings)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error computing diff: %v\n", err)
 		return ExitSyncError
 	}
+	if err := s.detectIssueDrift(database, diffResult); err != nil {
+		fmt.Fprintf(os.Stderr, "Error computing diff: %v\n", err)
+		return ExitSyncError
+	}
 
 	// Print summary
 	fmt.Fprintf(os.Stderr, "Sync summary: %s\n", diffResult.Stats())
+	if outdated := diffResult.OutdatedFingerprints(); outdated > 0 {
+		hint := "'strung fingerprint migrate'"
+		if s.sourceRoot != "" {
+			hint = "'strung fingerprint migrate --source-root=" + s.sourceRoot + "'"
+		}
+		fmt.Fprintf(os.Stderr, "Note: %d tracked findings use an older fingerprint (run %s)\n", outdated, hint)
+	}
+
+	now := time.Now()
+	scanID := db.NewScanID(now)
+
+	if s.dryRun {
+		if diffResult.IsEmpty() {
+			fmt.Fprintf(os.Stderr, "No changes to sync.\n")
+			return ExitSyncSuccess
+		}
+		// Dry runs don't read Beads; preview against the stored issue state
+		s.skip = sync.StoredSkip(trackedIssues(database, diffResult))
+		return s.executeActions(database, diffResult, scanID, now)
+	}
+
+	// Record the sync run in scan history
+	scan := &db.Scan{
+		ID:           scanID,
+		StartedAt:    now,
+		Project:      project,
+		FilesScanned: report.FilesScanned,
+		Findings:     len(findings),
+		Critical:     report.Summary.Critical,
+		Warning:      report.Summary.Warning,
+		Info:         report.Summary.Info,
+		GitCommit:    s.commit,
+		ToolVersion:  "strung " + versionStr,
+		InputDigest:  fmt.Sprintf("%x", sha256.Sum256(input)),
+		Branch:       database.Branch(),
+	}
+	if err := database.BeginScan(scan); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitSyncError
+	}
 
-	if diffResult.IsEmpty() {
-		fmt.Fprintf(os.Stderr, "No changes to sync.\n")
-		return ExitSyncSuccess
+	// Refresh last_seen/position of findings that need no issue action
+	exitCode := ExitSyncSuccess
+	if err := database.RecordObservations(diffResult.Observations(scanID, now)); err != nil {
+		fmt.Fprintf(os.Stderr, "Error recording observations: %v\n", err)
+		exitCode = ExitSyncError
+	} else if err := s.backfillPayloads(database, diffResult.Unchanged, scanID, now); err != nil {
+		fmt.Fprintf(os.Stderr, "Error backfilling finding payloads: %v\n", err)
+		exitCode = ExitSyncError
+	} else if s.verbose {
+		fmt.Fprintf(os.Stderr, "Refreshed %d unchanged findings (scan %s)\n", len(diffResult.Unchanged), scanID)
+	}
+
+	// Apply issue state changed in Beads since the last sync
+	if exitCode == ExitSyncSuccess {
+		if !s.reconcile(database, diffResult, scanID, now) || !s.relink(database, diffResult, scanID, now) {
+			exitCode = ExitSyncError
+		}
+	}
+
+	if exitCode == ExitSyncSuccess {
+		if diffResult.IsEmpty() {
+			fmt.Fprintf(os.Stderr, "No changes to sync.\n")
+		} else {
+			exitCode = s.executeActions(database, diffResult, scanID, now)
+		}
+	}
+
+	result := db.ScanSucceeded
+	if exitCode != ExitSyncSuccess {
+		result = db.ScanFailed
+	}
+	resolved := 0
+	if s.autoClose {
+		resolved = len(diffResult.Resolved)
+	}
+	if s.reconciled != nil {
+		resolved += len(s.reconciled.AlreadyClosed)
+	}
+	if err := database.FinishScan(scanID, result, len(diffResult.New), len(diffResult.Changed)+len(diffResult.Moved),
+		resolved, time.Now()); err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return ExitSyncError
+	}
+
+	// Apply history retention
+	var olderThan time.Time
+	if s.keepDays > 0 {
+		olderThan = now.AddDate(0, 0, -s.keepDays)
+	}
+	prunedScans, prunedEvents, err := database.PruneHistory(s.keepScans, olderThan)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error pruning history: %v\n", err)
+		return ExitSyncError
+	}
+	if s.verbose && prunedScans > 0 {
+		fmt.Fprintf(os.Stderr, "Pruned %d scans (%d occurrences) from history\n", prunedScans, prunedEvents)
 	}
 
-	// Execute actions
-	exitCode := s.executeActions(database, diffResult)
 	return exitCode
 }
 
-func (s *syncCmd) executeActions(database *db.TrackingDB, result *sync.DiffResult) int {
-	config := &transform.TransformConfig{
-		RepoURL:    s.repoURL,
-		RepoBranch: s.repoBranch,
-		ScanTime:   time.Now(),
+// projectName picks the project from --project, else the config file. The
+// report's path only names the project when asked to with "auto", as it
+// differs between checkouts sharing a database.
+func (s *syncCmd) projectName(report *parser.UBSReport) string {
+	project := s.project
+	if project == "" {
+		project = s.cfg.Project
 	}
-	transformer := transform.NewTransformerWithConfig(config)
-	now := time.Now()
+	return resolveProject(project, report)
+}
+
+// useProject scopes the database to project, adopting findings tracked
+// before projects. A dry run only reports the adoption and diffs against
+// the findings as they are, which is equivalent. Returns false on error.
+func (s *syncCmd) useProject(database *db.TrackingDB, project string) bool {
+	if s.dryRun {
+		adoptable, err := database.Adoptable(project)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			return false
+		}
+		if adoptable > 0 {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Project %s would adopt %d findings tracked before projects\n", project, adoptable)
+			database.SetProject("")
+			return true
+		}
+		database.SetProject(project)
+		return true
+	}
+
+	adopted, err := database.UseProject(project)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		return false
+	}
+	if adopted > 0 {
+		fmt.Fprintf(os.Stderr, "Project %s adopted %d findings tracked before projects\n", project, adopted)
+	}
+	return true
+}
+
+// loadSourceContext sets the source context of findings the scanner
+// reported without a snippet, returning how many were filled. Unreadable
+// files and blank windows are left alone.
+func loadSourceContext(reader *source.Reader, findings []parser.UBSFinding) int {
+	loaded := 0
+	for i := range findings {
+		f := &findings[i]
+		if f.CodeSnippet != "" {
+			continue
+		}
+		if f.SourceContext = readSourceContext(reader, f.File, f.Line); f.SourceContext != "" {
+			loaded++
+		}
+	}
+	return loaded
+}
+
+// readSourceContext returns the db.SourceContextLines window around a line,
+// or "" if it cannot be read or is blank
+func readSourceContext(reader *source.Reader, file string, line int) string {
+	ex, err := reader.Excerpt(file, line, 0, db.SourceContextLines)
+	if err != nil {
+		return ""
+	}
+	window := strings.Join(ex.Lines, "\n")
+	if strings.TrimSpace(window) == "" {
+		return ""
+	}
+	return window
+}
+
+// loadOwners reads CODEOWNERS from the configured file or the standard
+// locations under the repository root (the working directory outside git)
+func (s *syncCmd) loadOwners(repo *git.Repo) error {
+	if s.cfg.Owners.Disabled {
+		return nil
+	}
+
+	var err error
+	path := s.cfg.Owners.File
+	if path != "" {
+		s.owners, err = codeowners.LoadFile(path)
+	} else {
+		root := "."
+		if repo != nil {
+			root = repo.Root
+		}
+		s.owners, path, err = codeowners.Load(root)
+	}
+	if err != nil {
+		return fmt.Errorf("load CODEOWNERS: %w", err)
+	}
+
+	if s.verbose && s.owners != nil {
+		fmt.Fprintf(os.Stderr, "Assigning owners from %s\n", path)
+	}
+	return nil
+}
+
+// recordEvent appends a finding event, reporting failures without aborting
+func recordEvent(database *db.TrackingDB, ev *db.Event) bool {
+	if err := database.RecordEvent(ev); err != nil {
+		fmt.Fprintf(os.Stderr, "ERROR recording history: %v\n", err)
+		return false
+	}
+	return true
+}
+
+// transformerAt returns an enriching transformer whose "Detected" timestamp
+// is the given time, so re-rendered issues keep their original detection date
+func (s *syncCmd) transformerAt(detected time.Time) *transform.TransformerWithConfig {
+	cfg := s.transformConfig(detected)
+	cfg.RepoURL = s.repoURL
+	cfg.RepoBranch = s.repoBranch
+	cfg.Commit = s.commit
+	cfg.PathPrefix = s.pathPrefix
+	cfg.Links = s.links
+	if s.source != nil {
+		cfg.Source = s.source
+		cfg.ContextLines = s.contextLen
+	}
+	if s.blamer != nil {
+		cfg.Blame = s.blamer
+		cfg.BlameUsers = s.cfg.Blame.Users
+	}
+	return transform.NewTransformerWithConfig(cfg)
+}
+
+// transformConfig returns the rendering settings that are the same on every
+// run. File links, blame and source excerpts are left out, as they follow
+// the checkout's branch, commit and code.
+func (s *syncCmd) transformConfig(detected time.Time) *transform.TransformConfig {
+	cfg := &transform.TransformConfig{
+		ScanTime: detected,
+		Limits:   configLimits(s.cfg),
+		Taxonomy: s.taxonomy,
+	}
+	if s.owners != nil {
+		cfg.Owners = s.owners
+		cfg.OwnerAliases = s.cfg.Owners.Aliases
+	}
+	return cfg
+}
+
+// configLimits returns the issue text limits set in cfg
+func configLimits(cfg *config.Config) transform.Limits {
+	return transform.Limits{
+		Title:        cfg.Limits.Title,
+		Description:  cfg.Limits.Description,
+		SnippetLines: cfg.Limits.SnippetLines,
+	}
+}
+
+// issueHash returns the hash drift detection compares: the content hash of
+// the issue for f rendered with transformConfig, detected at firstSeen
+// (in UTC, as the database returns it)
+func (s *syncCmd) issueHash(f parser.UBSFinding, fp string, firstSeen time.Time) (string, error) {
+	issue, err := transform.NewTransformerWithConfig(s.transformConfig(firstSeen.UTC())).Transform(f)
+	if err != nil {
+		return "", err
+	}
+	return labelIssue(issue, fp).ContentHash(), nil
+}
+
+// renderIssue renders the labeled issue for a finding detected at the
+// given time, and its issueHash
+func (s *syncCmd) renderIssue(f parser.UBSFinding, fp string, detected time.Time) (*beads.Issue, string, error) {
+	issue, err := s.transformerAt(detected).Transform(f)
+	if err != nil {
+		return nil, "", err
+	}
+	hash, err := s.issueHash(f, fp, detected)
+	if err != nil {
+		return nil, "", err
+	}
+	return labelIssue(issue, fp), hash, nil
+}
+
+// detectIssueDrift moves unchanged findings whose issue would now render
+// differently (e.g. after a config or template change) to Changed, with an
+// "issue" field, so their issue is re-rendered. Findings without a stored
+// hash are left to backfillPayloads.
+func (s *syncCmd) detectIssueDrift(database *db.TrackingDB, result *sync.DiffResult) error {
+	unchanged := result.Unchanged[:0]
+	for _, u := range result.Unchanged {
+		if !u.Previous.HasPayload() || database.Inherited(u.Previous) {
+			unchanged = append(unchanged, u)
+			continue
+		}
+
+		hash, err := s.issueHash(u.Current, u.Previous.Fingerprint, u.Previous.FirstSeen)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", u.Previous.IssueID, err)
+		}
+		if hash == u.Previous.IssueHash {
+			unchanged = append(unchanged, u)
+			continue
+		}
+		u.Fields = append(u.Fields, sync.FieldChange{Field: "issue", Previous: shortHash(u.Previous.IssueHash), Current: shortHash(hash)})
+		result.Changed = append(result.Changed, u)
+	}
+	result.Unchanged = unchanged
+	return nil
+}
+
+func shortHash(hash string) string {
+	if len(hash) > 12 {
+		return hash[:12]
+	}
+	return hash
+}
+
+// describeFieldChanges formats field drift for progress output
+func describeFieldChanges(fields []sync.FieldChange) string {
+	parts := make([]string, len(fields))
+	for i, fc := range fields {
+		parts[i] = fc.String()
+	}
+	return strings.Join(parts, ", ")
+}
+
+// trackedFinding builds the DB row for a finding and the issueHash of the
+// issue filed for it
+func trackedFinding(fp, issueID string, f parser.UBSFinding, issueHash string,
+	firstSeen, now time.Time, scanID string) *db.Finding {
+	tool := f.Tool
+	if tool == "" {
+		tool = "ubs"
+	}
+
+	return &db.Finding{
+		Fingerprint: fp,
+		IssueID:     issueID,
+		File:        f.File,
+		Line:        f.Line,
+		Column:      f.Column,
+		Severity:    f.Severity,
+		Category:    f.Category,
+		Message:     f.Message,
+		FirstSeen:   firstSeen,
+		LastSeen:    now,
+		LastScanID:  scanID,
+		Suggestion:  f.Suggestion,
+		CodeSnippet: f.CodeSnippet,
+		Tool:        tool,
+		RuleID:      f.RuleID,
+		IssueHash:   issueHash,
+
+		SourceContext: f.SourceContext,
+	}
+}
+
+// keepKey makes a row stored under a tracked finding's fingerprint keep the
+// version and source context that fingerprint was computed from, so the
+// stored payload still reproduces it
+func keepKey(row, tracked *db.Finding) {
+	row.FingerprintVersion = tracked.FingerprintVersion
+	row.SourceContext = tracked.SourceContext
+}
+
+// backfillPayloads stores the full payload for unchanged findings tracked
+// before payload columns existed. No issue tracker action is taken, and a
+// branch view leaves inherited findings alone.
+func (s *syncCmd) backfillPayloads(database *db.TrackingDB, unchanged []sync.ChangeRecord, scanID string, now time.Time) error {
+	for _, u := range unchanged {
+		if u.Previous.HasPayload() || database.Inherited(u.Previous) {
+			continue
+		}
+
+		hash, err := s.issueHash(u.Current, u.Previous.Fingerprint, u.Previous.FirstSeen)
+		if err != nil {
+			return fmt.Errorf("render %s: %w", u.Previous.IssueID, err)
+		}
+
+		row := trackedFinding(u.Previous.Fingerprint, u.Previous.IssueID, u.Current, hash,
+			u.Previous.FirstSeen, now, scanID)
+		keepKey(row, u.Previous)
+		if err := database.Store(row); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *syncCmd) executeActions(database *db.TrackingDB, result *sync.DiffResult, scanID string, now time.Time) int {
 	hasErrors := false
 
 	// Create issues for new findings
 	for _, finding := range result.New {
-		issue, err := transformer.Transform(finding)
+		fp := database.Fingerprint(finding.File, finding.Category, finding.Message, finding.CodeSnippet,
+			finding.SourceContext, finding.Line)
+		issue, hash, err := s.renderIssue(finding, fp, now)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR transforming finding: %v\n", err)
 			hasErrors = true
 			continue
 		}
 
+		// A resolved row means the finding regressed
+		previous, err := database.Get(fp)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR reading finding: %v\n", err)
+			hasErrors = true
+			continue
+		}
+		regressed := previous != nil && previous.ResolvedAt != nil && previous.IssueID != ""
+
 		if s.dryRun {
 			fmt.Fprintf(os.Stderr, "[DRY RUN] Would create: %s\n", issue.Title)
+			if regressed && !s.noComments {
+				fmt.Fprintf(os.Stderr, "[DRY RUN] Would comment on the new issue: regression of %s\n", previous.IssueID)
+			}
 			continue
 		}
 
 		// Create via br CLI
-		issueID, err := createBeadsIssue(issue)
+		issueID, err := s.backend.createIssue(issue)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR creating issue: %v\n", err)
 			hasErrors = true
 			continue
 		}
+		if !s.backend.ownsIssue(issueID) {
+			fmt.Fprintf(os.Stderr, "Warning: issue %s lacks project prefix %q (check beads_dir)\n", issueID, s.backend.prefix)
+		}
 
 		// Record in DB
-		fp := db.ComputeFingerprint(finding.File, finding.Category, finding.Message, finding.CodeSnippet, finding.Line)
-		dbFinding := &db.Finding{
-			Fingerprint: fp,
-			IssueID:     issueID,
-			File:        finding.File,
-			Line:        finding.Line,
-			Severity:    finding.Severity,
-			Category:    finding.Category,
-			Message:     finding.Message,
-			FirstSeen:   now,
-			LastSeen:    now,
-		}
+		dbFinding := trackedFinding(fp, issueID, finding, hash, now, now, scanID)
 		if err := database.Store(dbFinding); err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR storing finding: %v (issue: %s)\n", err, issueID)
 			hasErrors = true
 			continue
 		}
 
+		if !recordEvent(database, &db.Event{
+			Fingerprint: fp, ScanID: scanID, Event: db.EventNew,
+			Severity: finding.Severity, Line: finding.Line, Detail: issueID, CreatedAt: now,
+		}) {
+			hasErrors = true
+		}
+
 		fmt.Fprintf(os.Stderr, "Created: %s → %s\n", issue.Title, issueID)
+
+		if regressed && !s.comment(database, []sync.IssueComment{sync.RegressionComment(issueID, previous, finding)}, scanID, now) {
+			hasErrors = true
+		}
 	}
 
 	// Update changed findings
 	for _, change := range result.Changed {
 		if s.dryRun {
-			fmt.Fprintf(os.Stderr, "[DRY RUN] Would update: %s (severity %s → %s)\n",
-				change.Previous.IssueID, change.Previous.Severity, change.Current.Severity)
+			if s.skip[change.Previous.Fingerprint] {
+				fmt.Fprintf(os.Stderr, "[DRY RUN] Would record: %s (%s, issue left alone)\n",
+					change.Previous.IssueID, describeFieldChanges(change.Fields))
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would update: %s (%s)\n",
+				change.Previous.IssueID, describeFieldChanges(change.Fields))
+			s.comment(database, sync.ChangeComments(change), scanID, now)
+			continue
+		}
+
+		// Re-render the issue so title, description and labels match the scan
+		issue, hash, err := s.renderIssue(change.Current, change.Previous.Fingerprint, change.Previous.FirstSeen)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR transforming finding: %v\n", err)
+			hasErrors = true
+			continue
+		}
+
+		// Issues closed in Beads keep their content; only the DB follows
+		updateIssue := !s.skip[change.Previous.Fingerprint]
+		if updateIssue {
+			if err := s.backend.updateIssue(change.Previous.IssueID, issue); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR updating %s: %v\n", change.Previous.IssueID, err)
+				hasErrors = true
+				continue
+			}
+		}
+
+		// Update in DB (fingerprint is unchanged by definition of a match)
+		dbFinding := trackedFinding(change.Previous.Fingerprint, change.Previous.IssueID,
+			change.Current, hash, change.Previous.FirstSeen, now, scanID)
+		keepKey(dbFinding, change.Previous)
+		if err := database.Store(dbFinding); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR updating DB: %v\n", err)
+			hasErrors = true
+		}
+
+		if !recordEvent(database, &db.Event{
+			Fingerprint: change.Previous.Fingerprint, ScanID: scanID, Event: db.EventChanged,
+			Severity: change.Current.Severity, Line: change.Current.Line,
+			Detail: describeFieldChanges(change.Fields), CreatedAt: now,
+		}) {
+			hasErrors = true
+		}
+
+		if updateIssue {
+			fmt.Fprintf(os.Stderr, "Updated: %s (%s)\n", change.Previous.IssueID, describeFieldChanges(change.Fields))
+			if !s.comment(database, sync.ChangeComments(change), scanID, now) {
+				hasErrors = true
+			}
+		} else if s.verbose {
+			fmt.Fprintf(os.Stderr, "Recorded: %s (%s, issue left alone)\n", change.Previous.IssueID, describeFieldChanges(change.Fields))
+		}
+	}
+
+	// Re-point moved findings at their new location, keeping the same issue
+	for _, move := range result.Moved {
+		detail := fmt.Sprintf("%s:%d → %s:%d (score %.2f)", move.Previous.File, move.Previous.Line,
+			move.Current.File, move.Current.Line, move.Score)
+
+		if s.dryRun {
+			if s.skip[move.Previous.Fingerprint] {
+				detail += ", issue left alone"
+			}
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would move: %s (%s)\n", move.Previous.IssueID, detail)
+			s.comment(database, sync.MoveComments(move), scanID, now)
+			continue
+		}
+
+		issue, hash, err := s.renderIssue(move.Current, move.Fingerprint, move.Previous.FirstSeen)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR transforming finding: %v\n", err)
+			hasErrors = true
 			continue
 		}
 
-		// Update priority in Beads
-		newPriority := transformer.SeverityToPriority(change.Current.Severity)
-		if err := updateBeadsPriority(change.Previous.IssueID, newPriority); err != nil {
-			fmt.Fprintf(os.Stderr, "ERROR updating %s: %v\n", change.Previous.IssueID, err)
+		// Re-key before touching the issue so a failed re-key leaves both
+		// sides as they were; the event history follows the finding
+		if err := database.RekeyFinding(move.Previous.Fingerprint, move.Fingerprint, db.FingerprintVersion); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR re-keying %s: %v\n", move.Previous.IssueID, err)
 			hasErrors = true
 			continue
 		}
 
-		// Update in DB
-		fp := db.ComputeFingerprint(change.Current.File, change.Current.Category,
-			change.Current.Message, change.Current.CodeSnippet, change.Current.Line)
-		dbFinding := &db.Finding{
-			Fingerprint: fp,
-			IssueID:     change.Previous.IssueID,
-			File:        change.Current.File,
-			Line:        change.Current.Line,
-			Severity:    change.Current.Severity,
-			Category:    change.Current.Category,
-			Message:     change.Current.Message,
-			FirstSeen:   change.Previous.FirstSeen,
-			LastSeen:    now,
+		// On failure the row keeps its old location under the new key, so the
+		// next sync reports it as changed and retries the update
+		if !s.skip[move.Previous.Fingerprint] {
+			if err := s.backend.updateIssue(move.Previous.IssueID, issue); err != nil {
+				fmt.Fprintf(os.Stderr, "ERROR updating %s: %v\n", move.Previous.IssueID, err)
+				hasErrors = true
+				continue
+			}
 		}
+
+		dbFinding := trackedFinding(move.Fingerprint, move.Previous.IssueID, move.Current, hash,
+			move.Previous.FirstSeen, now, scanID)
 		if err := database.Store(dbFinding); err != nil {
 			fmt.Fprintf(os.Stderr, "ERROR updating DB: %v\n", err)
 			hasErrors = true
 		}
 
-		fmt.Fprintf(os.Stderr, "Updated: %s (priority %d)\n", change.Previous.IssueID, newPriority)
+		if !recordEvent(database, &db.Event{
+			Fingerprint: move.Fingerprint, ScanID: scanID, Event: db.EventMoved,
+			Severity: move.Current.Severity, Line: move.Current.Line, Detail: detail, CreatedAt: now,
+		}) {
+			hasErrors = true
+		}
+
+		fmt.Fprintf(os.Stderr, "Moved: %s (%s)\n", move.Previous.IssueID, detail)
+
+		if !s.skip[move.Previous.Fingerprint] &&
+			!s.comment(database, sync.MoveComments(move), scanID, now) {
+			hasErrors = true
+		}
 	}
 
 	// Handle resolved findings
 	if s.autoClose {
 		for _, resolved := range result.Resolved {
+			if s.skip[resolved.Fingerprint] {
+				continue
+			}
 			if s.dryRun {
 				fmt.Fprintf(os.Stderr, "[DRY RUN] Would close: %s\n", resolved.IssueID)
+				s.comment(database, []sync.IssueComment{sync.ClosingComment(resolved)}, scanID, now)
 				continue
 			}
 
-			if err := closeBeadsIssue(resolved.IssueID); err != nil {
+			// Explain the close before it happens; a failed comment doesn't block it
+			if !s.comment(database, []sync.IssueComment{sync.ClosingComment(resolved)}, scanID, now) {
+				hasErrors = true
+			}
+
+			if err := s.backend.closeIssue(resolved.IssueID); err != nil {
 				fmt.Fprintf(os.Stderr, "ERROR closing %s: %v\n", resolved.IssueID, err)
 				hasErrors = true
 				continue
@@ -252,12 +882,37 @@ func (s *syncCmd) executeActions(database *db.TrackingDB, result *sync.DiffResul
 				hasErrors = true
 			}
 
+			if !recordEvent(database, &db.Event{
+				Fingerprint: resolved.Fingerprint, ScanID: scanID, Event: db.EventResolved,
+				Severity: resolved.Severity, Line: resolved.Line, Detail: resolved.IssueID, CreatedAt: now,
+			}) {
+				hasErrors = true
+			}
+
 			fmt.Fprintf(os.Stderr, "Closed: %s\n", resolved.IssueID)
 		}
 	} else if len(result.Resolved) > 0 {
 		fmt.Fprintf(os.Stderr, "Note: %d resolved findings (use --auto-close to close)\n", len(result.Resolved))
 	}
 
+	// Inherited findings fixed on the branch stay open until it is merged
+	for _, fixed := range result.FixedOnBranch {
+		if s.dryRun {
+			fmt.Fprintf(os.Stderr, "[DRY RUN] Would mark fixed on %s: %s\n", database.Branch(), fixed.IssueID)
+			continue
+		}
+
+		if err := database.MarkResolved(fixed.Fingerprint, now); err != nil {
+			fmt.Fprintf(os.Stderr, "ERROR marking fixed on branch: %v\n", err)
+			hasErrors = true
+			continue
+		}
+
+		if s.verbose {
+			fmt.Fprintf(os.Stderr, "Fixed on %s: %s\n", database.Branch(), fixed.IssueID)
+		}
+	}
+
 	if hasErrors {
 		return ExitSyncError
 	}
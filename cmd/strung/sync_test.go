@@ -10,6 +10,9 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
 )
 
 func TestSync_DryRun(t *testing.T) {
@@ -42,6 +45,134 @@ func TestSync_DryRun(t *testing.T) {
 	// (Actually it is created but empty - that's fine)
 }
 
+func TestSync_DryRunLeavesDB(t *testing.T) {
+	binPath := buildBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// A database tracked before projects, which --project would adopt
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	now := time.Now()
+	if err := database.Store(&db.Finding{
+		Fingerprint: db.ComputeFingerprint("old.ts", "null-safety", "Old message", "", 1),
+		IssueID:     "bd-1", File: "old.ts", Line: 1, Severity: "critical", Category: "null-safety",
+		Message: "Old message", FirstSeen: now, LastSeen: now,
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	database.Close()
+
+	before, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	input := `{"project":"/test","files_scanned":1,"findings":[
+		{"file":"test.ts","line":42,"severity":"critical","category":"null-safety","message":"Test message"}
+	],"summary":{"critical":1}}`
+
+	cmd := exec.Command(binPath, "sync", "--dry-run", "--auto-close", "--project", "api", "--db-path", dbPath)
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Sync failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	output := stderr.String()
+	if !strings.Contains(output, "would adopt 1 findings") || !strings.Contains(output, "Would close: bd-1") {
+		t.Errorf("dry run should preview adoption against the adopted findings:\n%s", output)
+	}
+
+	after, err := os.ReadFile(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(before, after) {
+		t.Error("dry run modified the database")
+	}
+}
+
+func TestSync_IssueDrift(t *testing.T) {
+	binPath := buildBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Tracked with a hash of the issue as some other rendering filed it
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	now := time.Now()
+	if err := database.Store(&db.Finding{
+		Fingerprint: db.ComputeFingerprint("test.ts", "null-safety", "Test message", "", 42),
+		IssueID:     "bd-1", File: "test.ts", Line: 42, Severity: "critical", Category: "null-safety",
+		Message: "Test message", FirstSeen: now, LastSeen: now, Tool: "ubs", IssueHash: "0123456789abcdef",
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	database.Close()
+
+	input := `{"project":"/test","files_scanned":1,"findings":[
+		{"file":"test.ts","line":42,"severity":"critical","category":"null-safety","message":"Test message"}
+	],"summary":{"critical":1}}`
+
+	cmd := exec.Command(binPath, "sync", "--dry-run", "--db-path", dbPath)
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Sync failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	output := stderr.String()
+	if !strings.Contains(output, "Changed: 1") || !strings.Contains(output, "Would update: bd-1 (issue 0123456789ab →") {
+		t.Errorf("issue drift should be reported as changed:\n%s", output)
+	}
+}
+
+func TestSync_DryRunSkipsSuppressed(t *testing.T) {
+	binPath := buildBinary(t)
+	dbPath := filepath.Join(t.TempDir(), "test.db")
+
+	// Closed as won't-fix in Beads on an earlier sync
+	database, err := db.Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	now := time.Now()
+	fp := db.ComputeFingerprint("test.ts", "null-safety", "Test message", "", 42)
+	if err := database.Store(&db.Finding{
+		Fingerprint: fp, IssueID: "bd-1", File: "test.ts", Line: 42, Severity: "warning",
+		Category: "null-safety", Message: "Test message", FirstSeen: now, LastSeen: now,
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := database.Suppress(fp, now); err != nil {
+		t.Fatalf("Suppress failed: %v", err)
+	}
+	database.Close()
+
+	input := `{"project":"/test","files_scanned":1,"findings":[
+		{"file":"test.ts","line":42,"severity":"critical","category":"null-safety","message":"Test message"}
+	],"summary":{"critical":1}}`
+
+	cmd := exec.Command(binPath, "sync", "--dry-run", "--db-path", dbPath)
+	cmd.Stdin = strings.NewReader(input)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		t.Fatalf("Sync failed: %v\nstderr: %s", err, stderr.String())
+	}
+
+	output := stderr.String()
+	if strings.Contains(output, "Would update") || strings.Contains(output, "Would comment") ||
+		!strings.Contains(output, "Would record: bd-1") {
+		t.Errorf("dry run should leave the suppressed issue alone:\n%s", output)
+	}
+}
+
 func TestSync_EmptyFindings(t *testing.T) {
 	binPath := buildBinary(t)
 	dbPath := filepath.Join(t.TempDir(), "test.db")
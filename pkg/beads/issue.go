@@ -4,7 +4,10 @@
 package beads
 
 import (
+	"crypto/sha256"
 	"encoding/json"
+	"fmt"
+	"strings"
 	"time"
 )
 
@@ -59,6 +62,21 @@ func (i *Issue) ToJSONL() (string, error) {
 	return string(data), nil
 }
 
+// ContentHash returns a SHA256 over the rendered issue content (title, type,
+// priority, text fields, assignee and tags). IDs, status and timestamps are
+// excluded so the hash only changes when what a reader sees changes.
+func (i *Issue) ContentHash() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00%d\x00%s\x00%s\x00%s\x00",
+		i.Title, i.Type, i.Priority, i.Description, i.Design, i.Acceptance)
+	if i.Assignee != nil {
+		h.Write([]byte(*i.Assignee))
+	}
+	h.Write([]byte{0})
+	h.Write([]byte(strings.Join(i.Tags, ",")))
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
 // NewIssue creates a new issue with sensible defaults
 func NewIssue(title string) *Issue {
 	now := time.Now()
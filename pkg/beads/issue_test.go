@@ -90,3 +90,30 @@ func TestIssueToJSONL_OmitsEmptyFields(t *testing.T) {
 		t.Error("Empty description should be omitted")
 	}
 }
+
+func TestIssueContentHash(t *testing.T) {
+	a := NewIssue("UBS: x in a.ts:1")
+	a.Description = "desc"
+	a.Tags = []string{"ubs", "x"}
+
+	b := NewIssue("UBS: x in a.ts:1")
+	b.Description = "desc"
+	b.Tags = []string{"ubs", "x"}
+	later := time.Now().Add(time.Hour)
+	b.UpdatedAt = &later
+
+	if a.ContentHash() != b.ContentHash() {
+		t.Error("Timestamps should not affect content hash")
+	}
+
+	b.Description = "changed"
+	if a.ContentHash() == b.ContentHash() {
+		t.Error("Description change should affect content hash")
+	}
+
+	owner := "alice"
+	a.Assignee = &owner
+	if a.ContentHash() == NewIssue("UBS: x in a.ts:1").ContentHash() {
+		t.Error("Assignee should affect content hash")
+	}
+}
@@ -0,0 +1,239 @@
+// Package codeowners parses CODEOWNERS files (GitHub and GitLab syntax) and
+// maps repository paths to their owners.
+package codeowners
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// Locations are the paths searched for a CODEOWNERS file, relative to the
+// repository root, in the order GitHub and GitLab check them
+var Locations = []string{
+	".github/CODEOWNERS",
+	"CODEOWNERS",
+	"docs/CODEOWNERS",
+	".gitlab/CODEOWNERS",
+}
+
+// Rule is one pattern line
+type Rule struct {
+	Pattern string
+	Owners  []string
+	Line    int
+
+	re *regexp.Regexp
+}
+
+// Section groups rules. Files without GitLab [Section] headers have a
+// single unnamed section.
+type Section struct {
+	Name          string
+	DefaultOwners []string // GitLab: owners for entries that list none
+	Rules         []*Rule
+}
+
+// Ruleset is a parsed CODEOWNERS file
+type Ruleset struct {
+	Sections []*Section
+}
+
+// Load finds and parses the CODEOWNERS file under root. Returns a nil
+// ruleset and empty path when the repository has none.
+func Load(root string) (*Ruleset, string, error) {
+	for _, loc := range Locations {
+		path := filepath.Join(root, filepath.FromSlash(loc))
+		rs, err := LoadFile(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		return rs, path, err
+	}
+	return nil, "", nil
+}
+
+// LoadFile parses the CODEOWNERS file at path
+func LoadFile(path string) (*Ruleset, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	rs, err := Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return rs, nil
+}
+
+// sectionHeader matches GitLab headers: [Name], ^[Optional], [Name][2] @owners
+var sectionHeader = regexp.MustCompile(`^\^?\[([^\]]+)\](?:\[\d+\])?\s*(.*)$`)
+
+// Parse reads CODEOWNERS syntax
+func Parse(r io.Reader) (*Ruleset, error) {
+	current := &Section{}
+	rs := &Ruleset{Sections: []*Section{current}}
+
+	scanner := bufio.NewScanner(r)
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+
+		if m := sectionHeader.FindStringSubmatch(line); m != nil {
+			current = &Section{Name: m[1], DefaultOwners: strings.Fields(m[2])}
+			rs.Sections = append(rs.Sections, current)
+			continue
+		}
+
+		fields := splitFields(line)
+		re, err := compilePattern(fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid pattern %q: %w", lineNo, fields[0], err)
+		}
+		current.Rules = append(current.Rules, &Rule{
+			Pattern: fields[0],
+			Owners:  fields[1:],
+			Line:    lineNo,
+			re:      re,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return rs, nil
+}
+
+// Owners returns the owners of a repository-relative path. Within a section
+// the last matching rule wins; GitLab sections each contribute their match.
+func (rs *Ruleset) Owners(path string) []string {
+	if rs == nil {
+		return nil
+	}
+	path = strings.TrimPrefix(filepath.ToSlash(path), "./")
+	path = strings.TrimPrefix(path, "/")
+
+	var owners []string
+	seen := make(map[string]bool)
+	for _, sec := range rs.Sections {
+		var match *Rule
+		for _, rule := range sec.Rules {
+			if rule.re.MatchString(path) {
+				match = rule
+			}
+		}
+		if match == nil {
+			continue
+		}
+
+		ruleOwners := match.Owners
+		if len(ruleOwners) == 0 {
+			ruleOwners = sec.DefaultOwners
+		}
+		for _, o := range ruleOwners {
+			if !seen[o] {
+				seen[o] = true
+				owners = append(owners, o)
+			}
+		}
+	}
+	return owners
+}
+
+// stripComment removes an unescaped # comment
+func stripComment(line string) string {
+	for i := 0; i < len(line); i++ {
+		switch line[i] {
+		case '\\':
+			i++
+		case '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// splitFields splits on whitespace, honoring backslash-escaped spaces in
+// the pattern, and unescapes "\#" and "\ "
+func splitFields(line string) []string {
+	var fields []string
+	var cur strings.Builder
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case c == '\\' && i+1 < len(line):
+			i++
+			cur.WriteByte(line[i])
+		case c == ' ' || c == '\t':
+			if cur.Len() > 0 {
+				fields = append(fields, cur.String())
+				cur.Reset()
+			}
+		default:
+			cur.WriteByte(c)
+		}
+	}
+	if cur.Len() > 0 {
+		fields = append(fields, cur.String())
+	}
+	return fields
+}
+
+// compilePattern converts a gitignore-style pattern to a regexp:
+// a leading or inner "/" anchors to the root, a trailing "/" matches only
+// directory contents, "*" and "?" stay within a path segment, and "**"
+// spans segments. A pattern naming a directory also matches its contents,
+// except that an anchored trailing wildcard ("docs/*") is not recursive.
+func compilePattern(pattern string) (*regexp.Regexp, error) {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	trimmed := strings.Trim(pattern, "/")
+	anchored := strings.HasPrefix(pattern, "/") || strings.Contains(trimmed, "/")
+
+	var re strings.Builder
+	re.WriteString("^")
+	if !anchored {
+		re.WriteString("(?:.*/)?")
+	}
+
+	for i := 0; i < len(trimmed); i++ {
+		c := trimmed[i]
+		switch {
+		case strings.HasPrefix(trimmed[i:], "**/"):
+			re.WriteString("(?:.*/)?")
+			i += 2
+		case strings.HasPrefix(trimmed[i:], "**"):
+			re.WriteString(".*")
+			i++
+		case c == '*':
+			re.WriteString("[^/]*")
+		case c == '?':
+			re.WriteString("[^/]")
+		default:
+			re.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+
+	lastSegment := trimmed[strings.LastIndex(trimmed, "/")+1:]
+	switch {
+	case dirOnly:
+		re.WriteString("/.*$")
+	case anchored && strings.ContainsAny(lastSegment, "*?"):
+		// "docs/*" owns docs/a.md but not docs/sub/b.md
+		re.WriteString("$")
+	default:
+		re.WriteString("(?:/.*)?$")
+	}
+
+	return regexp.Compile(re.String())
+}
@@ -0,0 +1,105 @@
+package codeowners
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestRuleset_OwnersGitHub(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`
+# Default owners
+*                   @org/everyone
+*.go                @org/gophers
+/docs/              @org/docs
+apps/               @org/apps
+/scripts/*          @org/ops
+**/logs             @org/logging
+/vendor/            # unowned
+src/my\ file.ts     @alice  # escaped space
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"README.md", []string{"@org/everyone"}},
+		{"pkg/db/tracking.go", []string{"@org/gophers"}},
+		{"docs/guide/setup.md", []string{"@org/docs"}},
+		{"docs", []string{"@org/everyone"}},
+		{"web/apps/main.ts", []string{"@org/apps"}},
+		{"scripts/deploy.sh", []string{"@org/ops"}},
+		{"scripts/ci/run.sh", []string{"@org/everyone"}},
+		{"deep/logs/app.log", []string{"@org/logging"}},
+		{"vendor/lib/x.go", nil},
+		{"./src/my file.ts", []string{"@alice"}},
+	}
+
+	for _, tt := range tests {
+		if got := rs.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestRuleset_OwnersGitLabSections(t *testing.T) {
+	rs, err := Parse(strings.NewReader(`
+*.ts @frontend
+
+[Database] @dba
+db/
+db/legacy/ @legacy-team
+
+^[Docs][2] @writers
+*.md
+`))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{"web/app.ts", []string{"@frontend"}},
+		{"db/schema.sql", []string{"@dba"}},
+		{"db/legacy/old.ts", []string{"@frontend", "@legacy-team"}},
+		{"db/README.md", []string{"@dba", "@writers"}},
+		{"main.go", nil},
+	}
+
+	for _, tt := range tests {
+		if got := rs.Owners(tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Owners(%q) = %v, want %v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestLoad(t *testing.T) {
+	root := t.TempDir()
+
+	rs, path, err := Load(root)
+	if err != nil || rs != nil || path != "" {
+		t.Fatalf("Expected no CODEOWNERS, got %v %q %v", rs, path, err)
+	}
+
+	os.MkdirAll(filepath.Join(root, ".github"), 0o755)
+	os.WriteFile(filepath.Join(root, "CODEOWNERS"), []byte("* @root\n"), 0o644)
+	os.WriteFile(filepath.Join(root, ".github", "CODEOWNERS"), []byte("* @github\n"), 0o644)
+
+	rs, path, err = Load(root)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if filepath.Base(filepath.Dir(path)) != ".github" {
+		t.Errorf("Expected .github/CODEOWNERS to take precedence, got %s", path)
+	}
+	if got := rs.Owners("a.go"); !reflect.DeepEqual(got, []string{"@github"}) {
+		t.Errorf("Owners = %v", got)
+	}
+}
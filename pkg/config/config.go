@@ -0,0 +1,95 @@
+// Package config loads the optional per-project strung configuration file.
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/TheEditor/strung/pkg/taxonomy"
+)
+
+// DefaultPath is the configuration file used when none is given
+const DefaultPath = ".strung.json"
+
+// Config is the project configuration. Every field is optional.
+type Config struct {
+	// Project names the project findings are tracked under when sync has no
+	// --project flag; "auto" uses the scanned directory's name. Default: none.
+	Project string `json:"project,omitempty"`
+
+	// Projects holds per-project settings, by project name
+	Projects map[string]Project `json:"projects,omitempty"`
+
+	Owners Owners `json:"owners"`
+	Blame  Blame  `json:"blame"`
+	Limits Limits `json:"limits"`
+
+	// Taxonomy adds or replaces CWE/OWASP classifications by category
+	Taxonomy map[string]taxonomy.Entry `json:"taxonomy"`
+}
+
+// Owners controls issue assignment from CODEOWNERS
+type Owners struct {
+	Disabled bool   `json:"disabled,omitempty"` // Skip CODEOWNERS lookup entirely
+	File     string `json:"file,omitempty"`     // CODEOWNERS path; "" searches the standard locations
+
+	// Aliases maps CODEOWNERS owners (e.g. "@org/backend") to Beads users
+	Aliases map[string]string `json:"aliases,omitempty"`
+}
+
+// Load reads the configuration file at path. An empty path loads
+// DefaultPath if it exists and otherwise returns an empty configuration.
+func Load(path string) (*Config, error) {
+	explicit := path != ""
+	if !explicit {
+		path = DefaultPath
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !explicit && errors.Is(err, os.ErrNotExist) {
+			return &Config{}, nil
+		}
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+
+	cfg := &Config{}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(cfg); err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+
+	return cfg, nil
+}
+
+// Project configures the issue tracker backend of one project
+type Project struct {
+	// BeadsDir is the directory br runs in, selecting the project's Beads
+	// workspace; "" uses the working directory
+	BeadsDir string `json:"beads_dir,omitempty"`
+
+	// IssuePrefix is the ID prefix of the workspace's issues (e.g. "api" for
+	// api-12). Issues created with another prefix are reported.
+	IssuePrefix string `json:"issue_prefix,omitempty"`
+}
+
+// Blame controls git blame enrichment
+type Blame struct {
+	Enabled bool `json:"enabled,omitempty"` // Same as sync --blame
+
+	// Users maps commit author emails to Beads users. Files without code
+	// owners are assigned to the last author when they are listed here.
+	Users map[string]string `json:"users,omitempty"`
+}
+
+// Limits caps generated issue text. Zero uses the built-in default and a
+// negative value removes the limit.
+type Limits struct {
+	Title        int `json:"title,omitempty"`         // Characters
+	Description  int `json:"description,omitempty"`   // Characters
+	SnippetLines int `json:"snippet_lines,omitempty"` // Lines
+}
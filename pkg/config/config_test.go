@@ -0,0 +1,65 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoad_DefaultMissing(t *testing.T) {
+	wd, _ := os.Getwd()
+	defer os.Chdir(wd)
+	os.Chdir(t.TempDir())
+
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Missing default config should not fail: %v", err)
+	}
+	if cfg.Owners.Disabled || len(cfg.Owners.Aliases) != 0 {
+		t.Errorf("Expected empty config, got %+v", cfg)
+	}
+}
+
+func TestLoad_ExplicitMissing(t *testing.T) {
+	if _, err := Load(filepath.Join(t.TempDir(), "nope.json")); err == nil {
+		t.Error("Expected error for missing explicit config")
+	}
+}
+
+func TestLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strung.json")
+	os.WriteFile(path, []byte(`{"owners": {"aliases": {"@org/backend": "alice"}}}`), 0o644)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Owners.Aliases["@org/backend"] != "alice" {
+		t.Errorf("Alias not loaded: %+v", cfg.Owners)
+	}
+}
+
+func TestLoad_UnknownField(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strung.json")
+	os.WriteFile(path, []byte(`{"ownres": {}}`), 0o644)
+
+	if _, err := Load(path); err == nil {
+		t.Error("Expected error for misspelled field")
+	}
+}
+
+func TestLoad_Projects(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "strung.json")
+	os.WriteFile(path, []byte(`{"project": "api", "projects": {"web": {"beads_dir": "../web", "issue_prefix": "web"}}}`), 0o644)
+
+	cfg, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load failed: %v", err)
+	}
+	if cfg.Project != "api" {
+		t.Errorf("Project = %q", cfg.Project)
+	}
+	if p := cfg.Projects["web"]; p.BeadsDir != "../web" || p.IssuePrefix != "web" {
+		t.Errorf("Projects[web] = %+v", p)
+	}
+}
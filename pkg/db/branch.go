@@ -0,0 +1,207 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// SetBranch scopes the database to a branch view. The base branch ("", the
+// default) tracks every finding it sees. A branch inherits the base
+// branch's findings without modifying them: findings first seen on the
+// branch belong to it, and inherited findings missing from its scans are
+// resolved for the branch only, until MergeBranch promotes its state.
+func (t *TrackingDB) SetBranch(branch string) {
+	t.branch = branch
+}
+
+// Branch returns the branch view; "" is the base branch
+func (t *TrackingDB) Branch() string {
+	return t.branch
+}
+
+// InView reports whether a tracked finding belongs to the current view:
+// the base branch's findings, plus the branch's own
+func (t *TrackingDB) InView(f *Finding) bool {
+	return f.Branch == "" || f.Branch == t.branch
+}
+
+// Inherited reports whether the current branch view inherited f from the
+// base branch (or shares it with another branch) rather than owning it
+func (t *TrackingDB) Inherited(f *Finding) bool {
+	return t.branch != "" && f.Branch != t.branch
+}
+
+// resolveOnBranch records that an inherited finding is fixed on the current
+// branch, keeping the earliest resolution time
+func (t *TrackingDB) resolveOnBranch(fingerprint string, resolvedAt time.Time) error {
+	_, err := t.db.Exec(`
+		INSERT OR IGNORE INTO branch_resolutions (branch, fingerprint, resolved_at)
+		VALUES (?, ?, ?)
+	`, t.branch, fingerprint, dbTime(resolvedAt))
+	if err != nil {
+		return fmt.Errorf("resolve %s on branch %s: %w", fingerprint[:12], t.branch, err)
+	}
+	return nil
+}
+
+// BranchInfo summarizes one branch's view
+type BranchInfo struct {
+	Name     string
+	Open     int        // Unresolved findings first seen on the branch
+	Resolved int        // Branch findings since resolved
+	Fixed    int        // Inherited findings resolved on the branch
+	LastScan *time.Time // Start of the branch's latest sync
+}
+
+// Branches lists every branch of the project view with tracked findings or
+// branch resolutions, sorted by name. Merged branches and the base branch
+// are not included.
+func (t *TrackingDB) Branches() ([]BranchInfo, error) {
+	byName := make(map[string]*BranchInfo)
+	get := func(name string) *BranchInfo {
+		b, ok := byName[name]
+		if !ok {
+			b = &BranchInfo{Name: name}
+			byName[name] = b
+		}
+		return b
+	}
+
+	project, args := t.projectCond("project")
+	rows, err := t.db.Query(`
+		SELECT branch, SUM(resolved_at IS NULL), SUM(resolved_at IS NOT NULL)
+		FROM findings WHERE branch != '' AND `+project+` GROUP BY branch
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count branch findings: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var open, resolved int
+		if err := rows.Scan(&name, &open, &resolved); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan branch counts: %w", err)
+		}
+		b := get(name)
+		b.Open, b.Resolved = open, resolved
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	project, args = t.projectCond("f.project")
+	rows, err = t.db.Query(`
+		SELECT r.branch, COUNT(*) FROM branch_resolutions r
+		JOIN findings f ON f.fingerprint = r.fingerprint AND f.resolved_at IS NULL
+		WHERE `+project+`
+		GROUP BY r.branch
+	`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("count branch resolutions: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var fixed int
+		if err := rows.Scan(&name, &fixed); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan branch resolutions: %w", err)
+		}
+		get(name).Fixed = fixed
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	project, args = t.projectCond("COALESCE(project, '')")
+	rows, err = t.db.Query(`SELECT branch, started_at FROM scans WHERE COALESCE(branch, '') != '' AND `+project, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get branch scans: %w", err)
+	}
+	for rows.Next() {
+		var name string
+		var started time.Time
+		if err := rows.Scan(&name, &started); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("scan branch scans: %w", err)
+		}
+		if b, ok := byName[name]; ok && (b.LastScan == nil || started.After(*b.LastScan)) {
+			b.LastScan = &started
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	branches := make([]BranchInfo, 0, len(byName))
+	for _, b := range byName {
+		branches = append(branches, *b)
+	}
+	sort.Slice(branches, func(i, j int) bool { return branches[i].Name < branches[j].Name })
+	return branches, nil
+}
+
+// MergeResult describes the branch state promoted by MergeBranch
+type MergeResult struct {
+	Branch   string
+	Promoted []*Finding // Branch findings now owned by the base branch
+	Fixed    []*Finding // Inherited findings the branch resolved; still open on the base
+}
+
+// MergeBranch promotes a merged branch's state into the base branch of the
+// project view: its findings move to the base branch, and its resolutions
+// of inherited findings are returned as Fixed and cleared. Fixed findings stay open so
+// the caller can close their issues and mark them resolved, exactly as a
+// base branch sync would.
+func (t *TrackingDB) MergeBranch(branch string, at time.Time) (*MergeResult, error) {
+	if branch == "" {
+		return nil, fmt.Errorf("merge: branch name required")
+	}
+
+	project, projectArgs := t.projectCond("project")
+	args := append([]any{branch}, projectArgs...)
+
+	promoted, err := t.queryFindings(`SELECT `+findingColumns+` FROM findings WHERE branch = ? AND `+project, args...)
+	if err != nil {
+		return nil, err
+	}
+	fixed, err := t.queryFindings(`
+		SELECT `+findingColumns+` FROM findings
+		WHERE branch = '' AND resolved_at IS NULL
+		  AND fingerprint IN (SELECT fingerprint FROM branch_resolutions WHERE branch = ?)
+		  AND `+project, args...)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin merge: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`UPDATE findings SET branch = '' WHERE branch = ? AND `+project, args...); err != nil {
+		return nil, fmt.Errorf("promote branch %s: %w", branch, err)
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM branch_resolutions
+		WHERE branch = ? AND fingerprint IN (SELECT fingerprint FROM findings WHERE `+project+`)
+	`, args...); err != nil {
+		return nil, fmt.Errorf("clear branch %s resolutions: %w", branch, err)
+	}
+	for _, f := range promoted {
+		if _, err := tx.Exec(insertEvent, f.Fingerprint, nil, EventMerged, f.Severity, f.Line, branch, dbTime(at)); err != nil {
+			return nil, fmt.Errorf("record merged event %s: %w", f.Fingerprint[:12], err)
+		}
+		f.Branch = ""
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit merge: %w", err)
+	}
+
+	return &MergeResult{Branch: branch, Promoted: promoted, Fixed: fixed}, nil
+}
@@ -0,0 +1,140 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackingDB_BranchView(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	base := &Finding{
+		Fingerprint: ComputeFingerprint("a.go", "security", "base", "", 1),
+		IssueID:     "bd-1", File: "a.go", Line: 1, Severity: "critical", Category: "security", Message: "base",
+		FirstSeen: now, LastSeen: now,
+	}
+	if err := db.Store(base); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	db.SetBranch("feature")
+	own := &Finding{
+		Fingerprint: ComputeFingerprint("b.go", "security", "branch", "", 2),
+		IssueID:     "bd-2", File: "b.go", Line: 2, Severity: "warning", Category: "security", Message: "branch",
+		FirstSeen: now, LastSeen: now,
+	}
+	if err := db.Store(own); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := db.BeginScan(&Scan{ID: "s1", StartedAt: now, Branch: "feature"}); err != nil {
+		t.Fatalf("BeginScan failed: %v", err)
+	}
+
+	stored, _ := db.Get(own.Fingerprint)
+	if stored.Branch != "feature" || db.Inherited(stored) {
+		t.Errorf("branch finding: Branch = %q, Inherited = %v", stored.Branch, db.Inherited(stored))
+	}
+	inherited, _ := db.Get(base.Fingerprint)
+	if !db.InView(inherited) || !db.Inherited(inherited) {
+		t.Errorf("base finding: InView = %v, Inherited = %v", db.InView(inherited), db.Inherited(inherited))
+	}
+
+	// Resolving an inherited finding leaves it open on the base branch
+	if err := db.MarkResolved(base.Fingerprint, now); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+	if f, _ := db.Get(base.Fingerprint); f.ResolvedAt != nil {
+		t.Error("inherited finding resolved on the base branch")
+	}
+
+	branches, err := db.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 1 {
+		t.Fatalf("Branches = %+v, want 1", branches)
+	}
+	b := branches[0]
+	if b.Name != "feature" || b.Open != 1 || b.Resolved != 0 || b.Fixed != 1 {
+		t.Errorf("Branches()[0] = %+v", b)
+	}
+	if b.LastScan == nil || !b.LastScan.Equal(now) {
+		t.Errorf("LastScan = %v, want %v", b.LastScan, now)
+	}
+
+	// Seeing it again on the branch reopens it there
+	if err := db.RecordObservations([]Observation{{Fingerprint: base.Fingerprint, Line: 5, SeenAt: now}}); err != nil {
+		t.Fatalf("RecordObservations failed: %v", err)
+	}
+	if f, _ := db.Get(base.Fingerprint); f.Line != 1 || f.Branch != "" {
+		t.Errorf("branch observation modified base finding: line %d, branch %q", f.Line, f.Branch)
+	}
+	if branches, _ := db.Branches(); branches[0].Fixed != 0 {
+		t.Errorf("Fixed = %d after reopening, want 0", branches[0].Fixed)
+	}
+}
+
+func TestTrackingDB_MergeBranch(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	base := &Finding{
+		Fingerprint: ComputeFingerprint("a.go", "security", "base", "", 1),
+		IssueID:     "bd-1", File: "a.go", Line: 1, Severity: "critical", Category: "security", Message: "base",
+		FirstSeen: now, LastSeen: now,
+	}
+	if err := db.Store(base); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	db.SetBranch("feature")
+	own := &Finding{
+		Fingerprint: ComputeFingerprint("b.go", "security", "branch", "", 2),
+		IssueID:     "bd-2", File: "b.go", Line: 2, Severity: "warning", Category: "security", Message: "branch",
+		FirstSeen: now, LastSeen: now,
+	}
+	if err := db.Store(own); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := db.MarkResolved(base.Fingerprint, now); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+
+	db.SetBranch("")
+	if _, err := db.MergeBranch("", now); err == nil {
+		t.Error("MergeBranch accepted an empty branch name")
+	}
+
+	result, err := db.MergeBranch("feature", now)
+	if err != nil {
+		t.Fatalf("MergeBranch failed: %v", err)
+	}
+	if len(result.Promoted) != 1 || result.Promoted[0].IssueID != "bd-2" {
+		t.Errorf("Promoted = %v, want bd-2", result.Promoted)
+	}
+	if len(result.Fixed) != 1 || result.Fixed[0].IssueID != "bd-1" {
+		t.Errorf("Fixed = %v, want bd-1", result.Fixed)
+	}
+
+	if f, _ := db.Get(own.Fingerprint); f.Branch != "" {
+		t.Errorf("promoted finding still on branch %q", f.Branch)
+	}
+	events, err := db.GetEvents(own.Fingerprint)
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Event != EventMerged || events[0].Detail != "feature" {
+		t.Errorf("events = %+v, want one merged event", events)
+	}
+
+	branches, err := db.Branches()
+	if err != nil {
+		t.Fatalf("Branches failed: %v", err)
+	}
+	if len(branches) != 0 {
+		t.Errorf("Branches after merge = %+v, want none", branches)
+	}
+}
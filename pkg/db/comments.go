@@ -0,0 +1,50 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Comment records a comment posted on an issue. Digest identifies the
+// comment's content, so the same comment is not posted twice in a row.
+type Comment struct {
+	IssueID     string
+	Digest      string
+	Fingerprint string
+	Kind        string
+	ScanID      string
+	CreatedAt   time.Time
+}
+
+// CommentPosted reports whether the last comment of kind posted on issueID
+// in the project view has digest. Earlier comments don't count, so a value
+// flipping back and forth is commented on each time it changes.
+func (t *TrackingDB) CommentPosted(issueID, kind, digest string) (bool, error) {
+	var last string
+	err := t.db.QueryRow(`
+		SELECT digest FROM issue_comments WHERE project = ? AND issue_id = ? AND kind = ?
+		ORDER BY created_at DESC, rowid DESC LIMIT 1
+	`, t.project, issueID, kind).Scan(&last)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("check comment on %s: %w", issueID, err)
+	}
+	return last == digest, nil
+}
+
+// RecordComment records a posted comment under the project view. Posting a
+// comment again replaces the earlier record, making it the issue's latest
+// comment of its kind.
+func (t *TrackingDB) RecordComment(c *Comment) error {
+	_, err := t.db.Exec(`
+		INSERT OR REPLACE INTO issue_comments (project, issue_id, digest, fingerprint, kind, scan_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`, t.project, c.IssueID, c.Digest, c.Fingerprint, c.Kind, nullString(c.ScanID), dbTime(c.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("record comment on %s: %w", c.IssueID, err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackingDB_Comments(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	c := &Comment{IssueID: "bd-1", Digest: "abc", Fingerprint: "fp-1", Kind: "severity", ScanID: "scan-1", CreatedAt: now}
+
+	if posted, err := db.CommentPosted("bd-1", "severity", "abc"); err != nil || posted {
+		t.Fatalf("CommentPosted before record = %v, %v", posted, err)
+	}
+	if err := db.RecordComment(c); err != nil {
+		t.Fatalf("RecordComment failed: %v", err)
+	}
+	// Recording the same comment again is a no-op
+	if err := db.RecordComment(c); err != nil {
+		t.Fatalf("RecordComment again failed: %v", err)
+	}
+
+	if posted, _ := db.CommentPosted("bd-1", "severity", "abc"); !posted {
+		t.Error("comment not recorded")
+	}
+	if posted, _ := db.CommentPosted("bd-2", "severity", "abc"); posted {
+		t.Error("comment recorded on the wrong issue")
+	}
+}
+
+func TestTrackingDB_CommentsFlapping(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	post := func(digest, kind string, at time.Time) {
+		t.Helper()
+		posted, err := db.CommentPosted("bd-1", kind, digest)
+		if err != nil || posted {
+			t.Fatalf("CommentPosted(%s, %s) = %v, %v, want false", kind, digest, posted, err)
+		}
+		c := &Comment{IssueID: "bd-1", Digest: digest, Fingerprint: "fp-1", Kind: kind, CreatedAt: at}
+		if err := db.RecordComment(c); err != nil {
+			t.Fatalf("RecordComment failed: %v", err)
+		}
+	}
+
+	// warning -> critical -> warning -> critical comments each time
+	post("to-critical", "severity", now)
+	post("to-warning", "severity", now.Add(time.Minute))
+	post("to-critical", "severity", now.Add(2*time.Minute))
+
+	if posted, _ := db.CommentPosted("bd-1", "severity", "to-critical"); !posted {
+		t.Error("repeat of the last comment not deduplicated")
+	}
+	// A comment of another kind doesn't make the last severity comment stale
+	post("moved", "moved", now.Add(3*time.Minute))
+	if posted, _ := db.CommentPosted("bd-1", "severity", "to-critical"); !posted {
+		t.Error("comment of another kind broke deduplication")
+	}
+}
+
+func TestTrackingDB_CommentsProjects(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Projects with separate backends both have a bd-1
+	now := time.Now().Truncate(time.Second)
+	db.SetProject("api")
+	if err := db.RecordComment(&Comment{IssueID: "bd-1", Digest: "abc", Fingerprint: "fp-api",
+		Kind: "severity", CreatedAt: now}); err != nil {
+		t.Fatalf("RecordComment failed: %v", err)
+	}
+
+	db.SetProject("web")
+	if posted, _ := db.CommentPosted("bd-1", "severity", "abc"); posted {
+		t.Error("comment on api's bd-1 suppressed web's")
+	}
+	if err := db.RecordComment(&Comment{IssueID: "bd-1", Digest: "def", Fingerprint: "fp-web",
+		Kind: "severity", CreatedAt: now.Add(time.Minute)}); err != nil {
+		t.Fatalf("RecordComment failed: %v", err)
+	}
+
+	db.SetProject("api")
+	if posted, _ := db.CommentPosted("bd-1", "severity", "abc"); !posted {
+		t.Error("comment on web's bd-1 replaced api's last comment")
+	}
+}
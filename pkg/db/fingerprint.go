@@ -0,0 +1,304 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Fingerprint algorithm versions. Any change to what feeds the hash must add
+// a new version so existing rows keep matching until they are re-keyed with
+// "strung fingerprint migrate".
+const (
+	// FingerprintV1 hashes file, category, message and normalized code
+	// context (or line when no snippet is available) exactly as reported.
+	FingerprintV1 = 1
+
+	// FingerprintV2 is V1 over normalized inputs: forward-slash paths without
+	// a leading "./" and whitespace-collapsed messages. Already-normalized
+	// findings hash identically under V1 and V2.
+	FingerprintV2 = 2
+
+	// FingerprintV3 is V2 that, for findings reported without a snippet,
+	// hashes the source context read from disk (sync --source-root) instead
+	// of the line. Without source context it hashes identically to V2.
+	FingerprintV3 = 3
+
+	// FingerprintVersion is the algorithm used for newly tracked findings
+	FingerprintVersion = FingerprintV3
+)
+
+// SourceContextLines is the number of lines on each side of the flagged line
+// read as source context. It is part of FingerprintV3, so it is fixed rather
+// than following the displayed context.
+const SourceContextLines = 2
+
+// ErrUnknownFingerprintVersion is returned for unsupported algorithm versions
+var ErrUnknownFingerprintVersion = errors.New("unknown fingerprint version")
+
+// ComputeFingerprintVersion generates a fingerprint with a specific algorithm
+// version. Version 0 means FingerprintVersion.
+func ComputeFingerprintVersion(version int, file, category, message, codeSnippet string, line int) (string, error) {
+	return ComputeFingerprintContext(version, file, category, message, codeSnippet, "", line)
+}
+
+// ComputeFingerprintContext is ComputeFingerprintVersion for a finding with
+// source context read from disk. Versions before FingerprintV3 ignore it.
+func ComputeFingerprintContext(version int, file, category, message, codeSnippet, sourceContext string,
+	line int) (string, error) {
+	switch version {
+	case 0, FingerprintV3:
+		if codeSnippet == "" {
+			codeSnippet = sourceContext
+		}
+		file = normalizePath(file)
+		message = strings.Join(strings.Fields(message), " ")
+	case FingerprintV2:
+		file = normalizePath(file)
+		message = strings.Join(strings.Fields(message), " ")
+	case FingerprintV1:
+	default:
+		return "", fmt.Errorf("%w: %d", ErrUnknownFingerprintVersion, version)
+	}
+
+	h := sha256.New()
+
+	if codeSnippet != "" {
+		// Use code context for stability
+		context := normalizeCodeContext(codeSnippet)
+		fmt.Fprintf(h, "%s:%s:%s:%s", file, category, message, context)
+	} else {
+		// Fallback to line-based fingerprint
+		fmt.Fprintf(h, "%s:%d:%s:%s", file, line, category, message)
+	}
+
+	return fmt.Sprintf("%x", h.Sum(nil)), nil
+}
+
+// normalizePath makes scanner paths comparable across platforms
+func normalizePath(p string) string {
+	p = strings.ReplaceAll(p, "\\", "/")
+	p = path.Clean(p)
+	return strings.TrimPrefix(p, "./")
+}
+
+// FingerprintVersions returns the distinct algorithm versions used by the
+// given findings plus FingerprintVersion, newest first
+func FingerprintVersions(findings []*Finding) []int {
+	seen := map[int]bool{FingerprintVersion: true}
+	for _, f := range findings {
+		if f.FingerprintVersion != 0 {
+			seen[f.FingerprintVersion] = true
+		}
+	}
+
+	versions := make([]int, 0, len(seen))
+	for v := range seen {
+		versions = append(versions, v)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(versions)))
+	return versions
+}
+
+// Fingerprint re-key outcomes
+const (
+	RekeyMigrated     = "migrated"     // Re-keyed to a new fingerprint
+	RekeyUnchanged    = "unchanged"    // Same fingerprint, only the version was bumped
+	RekeyUnverifiable = "unverifiable" // Stored payload does not reproduce the current fingerprint
+	RekeyConflict     = "conflict"     // New fingerprint already belongs to another finding
+)
+
+// Rekey describes the planned or applied re-keying of one finding
+type Rekey struct {
+	IssueID     string
+	File        string
+	Line        int
+	OldFP       string
+	NewFP       string
+	FromVersion int
+	Outcome     string
+
+	SourceContext string // Context loaded for the new fingerprint, if any
+}
+
+// ContextLoader reads the source context around a line, or returns "" when
+// the file cannot provide any
+type ContextLoader func(file string, line int) string
+
+// FingerprintMigration summarizes a fingerprint algorithm migration
+type FingerprintMigration struct {
+	Target int
+	Rekeys []Rekey
+}
+
+// Count returns the number of rekeys with the given outcome
+func (m *FingerprintMigration) Count(outcome string) int {
+	n := 0
+	for _, r := range m.Rekeys {
+		if r.Outcome == outcome {
+			n++
+		}
+	}
+	return n
+}
+
+// MigrateFingerprints re-keys every finding not already at the target
+// algorithm version, recomputing fingerprints from the stored payload. Issue
+// IDs, first_seen and event/operation history move with the finding. Rows
+// whose payload does not reproduce their current fingerprint, or whose new
+// fingerprint collides with another row, are left untouched. With dryRun the
+// plan is returned without writing.
+//
+// With load, findings without a snippet or stored source context are keyed
+// on the context it reads (FingerprintV3 and later), including findings
+// already at the target version.
+func (t *TrackingDB) MigrateFingerprints(target int, load ContextLoader, dryRun bool) (*FingerprintMigration, error) {
+	// Version 0 stands for the current algorithm and is never stored
+	if target < FingerprintV1 || target > FingerprintVersion {
+		return nil, fmt.Errorf("%w: %d", ErrUnknownFingerprintVersion, target)
+	}
+
+	findings, err := t.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	taken := make(map[string]bool, len(findings))
+	for _, f := range findings {
+		taken[f.Fingerprint] = true
+	}
+
+	result := &FingerprintMigration{Target: target}
+	for _, f := range findings {
+		sourceContext := f.SourceContext
+		if load != nil && target >= FingerprintV3 && f.CodeSnippet == "" && sourceContext == "" {
+			sourceContext = load(f.File, f.Line)
+		}
+		if f.FingerprintVersion == target && sourceContext == f.SourceContext {
+			continue
+		}
+
+		r := Rekey{IssueID: f.IssueID, File: f.File, Line: f.Line, OldFP: f.Fingerprint, FromVersion: f.FingerprintVersion}
+		if sourceContext != f.SourceContext {
+			r.SourceContext = sourceContext
+		}
+
+		if f.RecomputeFingerprint() != f.Fingerprint {
+			r.Outcome = RekeyUnverifiable
+			result.Rekeys = append(result.Rekeys, r)
+			continue
+		}
+
+		r.NewFP, _ = ComputeFingerprintContext(target, f.File, f.Category, f.Message, f.CodeSnippet, sourceContext, f.Line)
+		r.NewFP = ProjectFingerprint(f.Project, r.NewFP)
+		switch {
+		case r.NewFP == r.OldFP:
+			r.Outcome = RekeyUnchanged
+		case taken[r.NewFP]:
+			r.Outcome = RekeyConflict
+		default:
+			r.Outcome = RekeyMigrated
+			taken[r.NewFP] = true
+			delete(taken, r.OldFP)
+		}
+		result.Rekeys = append(result.Rekeys, r)
+	}
+
+	if dryRun {
+		return result, nil
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return nil, fmt.Errorf("begin fingerprint migration: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, r := range result.Rekeys {
+		switch r.Outcome {
+		case RekeyUnchanged:
+			if _, err := tx.Exec(`UPDATE findings SET fp_version = ? WHERE fingerprint = ?`, target, r.OldFP); err != nil {
+				return nil, fmt.Errorf("update version %s: %w", r.OldFP[:12], err)
+			}
+		case RekeyMigrated:
+			if err := rekeyTx(tx, r.OldFP, r.NewFP, target); err != nil {
+				return nil, err
+			}
+			if r.SourceContext == "" {
+				continue
+			}
+			if _, err := tx.Exec(`UPDATE findings SET source_context = ? WHERE fingerprint = ?`,
+				r.SourceContext, r.NewFP); err != nil {
+				return nil, fmt.Errorf("store source context %s: %w", r.NewFP[:12], err)
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit fingerprint migration: %w", err)
+	}
+
+	return result, nil
+}
+
+// ErrFingerprintExists is returned when re-keying onto a tracked fingerprint
+var ErrFingerprintExists = errors.New("fingerprint already tracked")
+
+// RekeyFinding moves a tracked finding to a new fingerprint, keeping its
+// issue ID, first_seen and event/operation history
+func (t *TrackingDB) RekeyFinding(oldFP, newFP string, version int) error {
+	if oldFP == newFP {
+		return nil
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin re-key: %w", err)
+	}
+	defer tx.Rollback()
+
+	var count int
+	if err := tx.QueryRow(`SELECT COUNT(*) FROM findings WHERE fingerprint = ?`, newFP).Scan(&count); err != nil {
+		return fmt.Errorf("check fingerprint %s: %w", newFP[:12], err)
+	}
+	if count > 0 {
+		return fmt.Errorf("re-key %s → %s: %w", oldFP[:12], newFP[:12], ErrFingerprintExists)
+	}
+
+	if err := rekeyTx(tx, oldFP, newFP, version); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit re-key: %w", err)
+	}
+	return nil
+}
+
+// rekeyTx rewrites a fingerprint across findings and history tables
+func rekeyTx(tx *sql.Tx, oldFP, newFP string, version int) error {
+	res, err := tx.Exec(`UPDATE findings SET fingerprint = ?, fp_version = ? WHERE fingerprint = ?`,
+		newFP, version, oldFP)
+	if err != nil {
+		return fmt.Errorf("re-key %s: %w", oldFP[:12], err)
+	}
+	if rows, _ := res.RowsAffected(); rows == 0 {
+		return fmt.Errorf("finding %s not found", oldFP[:12])
+	}
+
+	for _, stmt := range []string{
+		`UPDATE finding_events SET fingerprint = ? WHERE fingerprint = ?`,
+		`UPDATE operation_log SET fingerprint = ? WHERE fingerprint = ?`,
+		`UPDATE branch_resolutions SET fingerprint = ? WHERE fingerprint = ?`,
+		`UPDATE issue_comments SET fingerprint = ? WHERE fingerprint = ?`,
+	} {
+		if _, err := tx.Exec(stmt, newFP, oldFP); err != nil {
+			return fmt.Errorf("re-key history %s: %w", oldFP[:12], err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,219 @@
+package db
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestComputeFingerprintVersion(t *testing.T) {
+	// Already-normalized input hashes identically under every version
+	v1, _ := ComputeFingerprintVersion(FingerprintV1, "src/a.ts", "x", "msg", "code", 1)
+	v2, _ := ComputeFingerprintVersion(FingerprintV2, "src/a.ts", "x", "msg", "code", 1)
+	if v1 != v2 {
+		t.Error("Normalized input should hash the same under V1 and V2")
+	}
+	if ComputeFingerprint("src/a.ts", "x", "msg", "code", 1) != v2 {
+		t.Error("ComputeFingerprint should use the latest version")
+	}
+
+	// V2 normalizes paths and message whitespace
+	raw1, _ := ComputeFingerprintVersion(FingerprintV1, "./src\\a.ts", "x", "msg  here", "", 1)
+	raw2, _ := ComputeFingerprintVersion(FingerprintV2, "./src\\a.ts", "x", "msg  here", "", 1)
+	clean, _ := ComputeFingerprintVersion(FingerprintV2, "src/a.ts", "x", "msg here", "", 1)
+	if raw1 == raw2 {
+		t.Error("V1 should hash raw paths")
+	}
+	if raw2 != clean {
+		t.Error("V2 should normalize path separators and message whitespace")
+	}
+
+	// V3 keys snippet-less findings on source context, V2 ignores it
+	ctx2, _ := ComputeFingerprintContext(FingerprintV2, "a.ts", "x", "msg", "", "if (a) {\n}\n", 1)
+	ctx3, _ := ComputeFingerprintContext(FingerprintV3, "a.ts", "x", "msg", "", "if (a) {\n}\n", 1)
+	moved3, _ := ComputeFingerprintContext(FingerprintV3, "a.ts", "x", "msg", "", "if (a) {\n}\n", 9)
+	lineOnly3, _ := ComputeFingerprintVersion(FingerprintV3, "a.ts", "x", "msg", "", 1)
+	if ctx2 != lineOnly3 || ctx3 == lineOnly3 {
+		t.Error("Only V3 should hash source context")
+	}
+	if ctx3 != moved3 {
+		t.Error("V3 with source context should not depend on the line")
+	}
+
+	if _, err := ComputeFingerprintVersion(99, "a", "b", "c", "", 1); !errors.Is(err, ErrUnknownFingerprintVersion) {
+		t.Errorf("Expected ErrUnknownFingerprintVersion, got %v", err)
+	}
+}
+
+func TestFingerprintVersions(t *testing.T) {
+	versions := FingerprintVersions([]*Finding{
+		{FingerprintVersion: FingerprintV1},
+		{FingerprintVersion: FingerprintV2},
+		{FingerprintVersion: FingerprintV1},
+	})
+	if len(versions) != 3 || versions[0] != FingerprintVersion || versions[1] != FingerprintV2 || versions[2] != FingerprintV1 {
+		t.Errorf("Unexpected versions: %v", versions)
+	}
+}
+
+func TestTrackingDB_MigrateFingerprints(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	store := func(fp, issueID, file string, line int) {
+		t.Helper()
+		if err := db.Store(&Finding{
+			Fingerprint: fp, IssueID: issueID, File: file, Line: line,
+			Severity: "warning", Category: "x", Message: "msg",
+			FirstSeen: now, LastSeen: now, FingerprintVersion: FingerprintV1,
+		}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	// Re-keyed: raw path hashes differently under V2
+	oldFP, _ := ComputeFingerprintVersion(FingerprintV1, "./a.ts", "x", "msg", "", 1)
+	store(oldFP, "t1", "./a.ts", 1)
+	db.RecordEvent(&Event{Fingerprint: oldFP, ScanID: "s1", Event: EventNew, CreatedAt: now})
+
+	// Unchanged: clean path hashes the same
+	sameFP, _ := ComputeFingerprintVersion(FingerprintV1, "b.ts", "x", "msg", "", 2)
+	store(sameFP, "t2", "b.ts", 2)
+
+	// Unverifiable: fingerprint cannot be reproduced from the payload
+	store("not-a-real-fingerprint", "t3", "c.ts", 3)
+
+	for _, bad := range []int{0, -1, FingerprintVersion + 1} {
+		if _, err := db.MigrateFingerprints(bad, nil, true); !errors.Is(err, ErrUnknownFingerprintVersion) {
+			t.Errorf("MigrateFingerprints(%d) = %v, want ErrUnknownFingerprintVersion", bad, err)
+		}
+	}
+
+	plan, err := db.MigrateFingerprints(FingerprintV2, nil, true)
+	if err != nil {
+		t.Fatalf("Dry run failed: %v", err)
+	}
+	if plan.Count(RekeyMigrated) != 1 || plan.Count(RekeyUnchanged) != 1 || plan.Count(RekeyUnverifiable) != 1 {
+		t.Fatalf("Unexpected plan: %+v", plan.Rekeys)
+	}
+	if f, _ := db.Get(oldFP); f == nil {
+		t.Fatal("Dry run must not re-key")
+	}
+
+	if _, err := db.MigrateFingerprints(FingerprintV2, nil, false); err != nil {
+		t.Fatalf("MigrateFingerprints failed: %v", err)
+	}
+
+	newFP, _ := ComputeFingerprintVersion(FingerprintV2, "./a.ts", "x", "msg", "", 1)
+	f, _ := db.Get(newFP)
+	if f == nil || f.IssueID != "t1" || f.FingerprintVersion != FingerprintV2 {
+		t.Fatalf("Finding not re-keyed: %+v", f)
+	}
+	if old, _ := db.Get(oldFP); old != nil {
+		t.Error("Old fingerprint should be gone")
+	}
+	if events, _ := db.GetEvents(newFP); len(events) != 1 {
+		t.Errorf("History should move with the finding, got %d events", len(events))
+	}
+
+	if f, _ := db.Get(sameFP); f.FingerprintVersion != FingerprintV2 {
+		t.Errorf("Unchanged row should be bumped to V2, got %d", f.FingerprintVersion)
+	}
+	if f, _ := db.Get("not-a-real-fingerprint"); f.FingerprintVersion != FingerprintV1 {
+		t.Error("Unverifiable row should be left alone")
+	}
+}
+
+func TestTrackingDB_MigrateFingerprintsSourceContext(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	lineFP, _ := ComputeFingerprintVersion(FingerprintV2, "a.ts", "x", "msg", "", 1)
+	db.Store(&Finding{Fingerprint: lineFP, IssueID: "t1", File: "a.ts", Line: 1,
+		Severity: "warning", Category: "x", Message: "msg",
+		FirstSeen: now, LastSeen: now, FingerprintVersion: FingerprintV2})
+
+	// Without a loader the row keeps its line-based key
+	result, err := db.MigrateFingerprints(FingerprintV3, nil, false)
+	if err != nil || result.Count(RekeyUnchanged) != 1 {
+		t.Fatalf("MigrateFingerprints = %+v, %v", result, err)
+	}
+
+	// With one, it is re-keyed on the context, even though already at V3
+	load := func(file string, line int) string { return "if (a) {\n  b()\n}" }
+	if result, err = db.MigrateFingerprints(FingerprintV3, load, false); err != nil || result.Count(RekeyMigrated) != 1 {
+		t.Fatalf("MigrateFingerprints with context = %+v, %v", result, err)
+	}
+	ctxFP, _ := ComputeFingerprintContext(FingerprintV3, "a.ts", "x", "msg", "", load("a.ts", 1), 1)
+	f, _ := db.Get(ctxFP)
+	if f == nil || f.IssueID != "t1" || f.SourceContext != load("a.ts", 1) || f.RecomputeFingerprint() != ctxFP {
+		t.Fatalf("Finding not re-keyed on context: %+v", f)
+	}
+
+	// Nothing left to do
+	if result, _ = db.MigrateFingerprints(FingerprintV3, load, false); len(result.Rekeys) != 0 {
+		t.Errorf("Second migration = %+v", result.Rekeys)
+	}
+}
+
+func TestTrackingDB_MigrateFingerprintsConflict(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	oldFP, _ := ComputeFingerprintVersion(FingerprintV1, "./a.ts", "x", "msg", "", 1)
+	newFP, _ := ComputeFingerprintVersion(FingerprintV2, "./a.ts", "x", "msg", "", 1)
+
+	db.Store(&Finding{Fingerprint: oldFP, IssueID: "t1", File: "./a.ts", Line: 1,
+		Severity: "warning", Category: "x", Message: "msg",
+		FirstSeen: now, LastSeen: now, FingerprintVersion: FingerprintV1})
+	db.Store(&Finding{Fingerprint: newFP, IssueID: "t2", File: "a.ts", Line: 1,
+		Severity: "warning", Category: "x", Message: "msg",
+		FirstSeen: now, LastSeen: now})
+
+	result, err := db.MigrateFingerprints(FingerprintV2, nil, false)
+	if err != nil {
+		t.Fatalf("MigrateFingerprints failed: %v", err)
+	}
+	if result.Count(RekeyConflict) != 1 {
+		t.Errorf("Expected 1 conflict, got %+v", result.Rekeys)
+	}
+	if f, _ := db.Get(oldFP); f == nil || f.IssueID != "t1" {
+		t.Error("Conflicting row should be left alone")
+	}
+}
+
+func TestTrackingDB_RekeyFinding(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	oldFP := ComputeFingerprint("old.ts", "x", "msg", "", 1)
+	newFP := ComputeFingerprint("new.ts", "x", "msg", "", 1)
+	otherFP := ComputeFingerprint("other.ts", "x", "msg", "", 1)
+
+	db.Store(&Finding{Fingerprint: oldFP, IssueID: "t1", File: "old.ts", Line: 1,
+		Severity: "warning", Category: "x", Message: "msg", FirstSeen: now, LastSeen: now})
+	db.Store(&Finding{Fingerprint: otherFP, IssueID: "t2", File: "other.ts", Line: 1,
+		Severity: "warning", Category: "x", Message: "msg", FirstSeen: now, LastSeen: now})
+	db.RecordEvent(&Event{Fingerprint: oldFP, Event: EventNew, CreatedAt: now})
+
+	if err := db.RekeyFinding(oldFP, newFP, FingerprintVersion); err != nil {
+		t.Fatalf("RekeyFinding failed: %v", err)
+	}
+	if f, _ := db.Get(newFP); f == nil || f.IssueID != "t1" {
+		t.Fatal("Finding should be reachable under the new fingerprint")
+	}
+	if f, _ := db.Get(oldFP); f != nil {
+		t.Error("Old fingerprint should be gone")
+	}
+	if events, _ := db.GetEvents(newFP); len(events) != 1 {
+		t.Errorf("Events should follow the finding, got %d", len(events))
+	}
+
+	if err := db.RekeyFinding(newFP, otherFP, FingerprintVersion); !errors.Is(err, ErrFingerprintExists) {
+		t.Errorf("Expected ErrFingerprintExists, got %v", err)
+	}
+}
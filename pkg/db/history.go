@@ -0,0 +1,300 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// Scan results
+const (
+	ScanRunning   = "running"
+	ScanSucceeded = "success"
+	ScanFailed    = "failed"
+)
+
+// Finding event types. "seen" records an occurrence; the others are state
+// transitions and are kept when history is pruned.
+const (
+	EventNew          = "new"
+	EventSeen         = "seen"
+	EventChanged      = "changed"
+	EventResolved     = "resolved"
+	EventMoved        = "moved"
+	EventMerged       = "merged"       // Branch finding promoted to the base branch
+	EventSuppressed   = "suppressed"   // Issue closed in Beads while the finding persists
+	EventUnsuppressed = "unsuppressed" // Issue of a suppressed finding reopened in Beads
+	EventReopened     = "reopened"     // Closed issue reopened because the finding persists
+)
+
+// Scan represents one sync run and the scanner report it consumed
+type Scan struct {
+	ID           string
+	StartedAt    time.Time
+	FinishedAt   *time.Time
+	Project      string
+	FilesScanned int
+	Findings     int // Findings considered after severity filtering
+	Critical     int // Summary counts as reported by the scanner
+	Warning      int
+	Info         int
+	GitCommit    string
+	ToolVersion  string
+	InputDigest  string // SHA256 of the raw scanner JSON
+	New          int
+	Changed      int
+	Resolved     int
+	Result       string // "running", "success", "failed"
+	Branch       string // Branch view the scan was synced to; "" is the base branch
+}
+
+// Event records a finding occurrence or state transition within a scan
+type Event struct {
+	ID          int64
+	Fingerprint string
+	ScanID      string
+	Event       string // "new", "seen", "changed", "resolved", "moved", "merged", "suppressed", ...
+	Severity    string
+	Line        int
+	Detail      string
+	CreatedAt   time.Time
+}
+
+const insertEvent = `
+	INSERT INTO finding_events (fingerprint, scan_id, event, severity, line, detail, created_at)
+	VALUES (?, ?, ?, ?, ?, ?, ?)
+`
+
+const scanColumns = `id, started_at, finished_at, project, files_scanned, findings,
+	critical, warning, info, git_commit, tool_version, input_digest,
+	new_count, changed_count, resolved_count, result, branch`
+
+// BeginScan records the start of a sync run
+func (t *TrackingDB) BeginScan(s *Scan) error {
+	if s.Result == "" {
+		s.Result = ScanRunning
+	}
+
+	query := `
+		INSERT INTO scans (id, started_at, project, files_scanned, findings, critical, warning, info,
+			git_commit, tool_version, input_digest, result, branch)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+
+	_, err := t.db.Exec(query,
+		s.ID, dbTime(s.StartedAt), nullString(s.Project), s.FilesScanned, s.Findings,
+		s.Critical, s.Warning, s.Info,
+		nullString(s.GitCommit), nullString(s.ToolVersion), nullString(s.InputDigest), s.Result,
+		nullString(s.Branch))
+	if err != nil {
+		return fmt.Errorf("begin scan %s: %w", s.ID, err)
+	}
+
+	return nil
+}
+
+// FinishScan records the outcome and action counts of a sync run
+func (t *TrackingDB) FinishScan(id, result string, newCount, changed, resolved int, at time.Time) error {
+	query := `
+		UPDATE scans SET finished_at = ?, result = ?, new_count = ?, changed_count = ?, resolved_count = ?
+		WHERE id = ?
+	`
+
+	res, err := t.db.Exec(query, dbTime(at), result, newCount, changed, resolved, id)
+	if err != nil {
+		return fmt.Errorf("finish scan %s: %w", id, err)
+	}
+
+	rows, _ := res.RowsAffected()
+	if rows == 0 {
+		return fmt.Errorf("scan %s not found", id)
+	}
+
+	return nil
+}
+
+// GetScans returns the most recent scans of the project view, newest first.
+// limit <= 0 returns all.
+func (t *TrackingDB) GetScans(limit int) ([]*Scan, error) {
+	cond, args := t.projectCond("COALESCE(project, '')")
+	query := `SELECT ` + scanColumns + ` FROM scans WHERE ` + cond + ` ORDER BY started_at DESC`
+	if limit > 0 {
+		query += fmt.Sprintf(" LIMIT %d", limit)
+	}
+
+	rows, err := t.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get scans: %w", err)
+	}
+	defer rows.Close()
+
+	var scans []*Scan
+	for rows.Next() {
+		s, err := scanScan(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scan scan row: %w", err)
+		}
+		scans = append(scans, s)
+	}
+
+	return scans, rows.Err()
+}
+
+// LatestScan returns the most recent scan, or nil if none recorded
+func (t *TrackingDB) LatestScan() (*Scan, error) {
+	scans, err := t.GetScans(1)
+	if err != nil || len(scans) == 0 {
+		return nil, err
+	}
+	return scans[0], nil
+}
+
+func scanScan(row rowScanner) (*Scan, error) {
+	var s Scan
+	var finishedAt sql.NullTime
+	var project, commit, version, digest, branch sql.NullString
+
+	err := row.Scan(&s.ID, &s.StartedAt, &finishedAt, &project, &s.FilesScanned, &s.Findings,
+		&s.Critical, &s.Warning, &s.Info, &commit, &version, &digest,
+		&s.New, &s.Changed, &s.Resolved, &s.Result, &branch)
+	if err != nil {
+		return nil, err
+	}
+
+	if finishedAt.Valid {
+		s.FinishedAt = &finishedAt.Time
+	}
+	s.Project = project.String
+	s.GitCommit = commit.String
+	s.ToolVersion = version.String
+	s.InputDigest = digest.String
+	s.Branch = branch.String
+
+	return &s, nil
+}
+
+// RecordEvent appends a finding event
+func (t *TrackingDB) RecordEvent(e *Event) error {
+	var line any
+	if e.Line > 0 {
+		line = e.Line
+	}
+
+	_, err := t.db.Exec(insertEvent,
+		e.Fingerprint, nullString(e.ScanID), e.Event, nullString(e.Severity), line,
+		nullString(e.Detail), dbTime(e.CreatedAt))
+	if err != nil {
+		return fmt.Errorf("record %s event: %w", e.Event, err)
+	}
+
+	return nil
+}
+
+const eventColumns = `id, fingerprint, scan_id, event, severity, line, detail, created_at`
+
+// GetEvents returns the event timeline for a finding, oldest first
+func (t *TrackingDB) GetEvents(fingerprint string) ([]*Event, error) {
+	return t.queryEvents(`
+		SELECT `+eventColumns+` FROM finding_events
+		WHERE fingerprint = ?
+		ORDER BY created_at ASC, id ASC
+	`, fingerprint)
+}
+
+// TransitionEvents returns the state transition events of the project
+// view's findings, oldest first. "seen" occurrences are not included.
+func (t *TrackingDB) TransitionEvents() ([]*Event, error) {
+	cond, args := t.projectCond("project")
+	return t.queryEvents(`
+		SELECT `+eventColumns+` FROM finding_events
+		WHERE event != ? AND fingerprint IN (SELECT fingerprint FROM findings WHERE `+cond+`)
+		ORDER BY created_at ASC, id ASC
+	`, append([]any{EventSeen}, args...)...)
+}
+
+func (t *TrackingDB) queryEvents(query string, args ...any) ([]*Event, error) {
+	rows, err := t.db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("get events: %w", err)
+	}
+	defer rows.Close()
+
+	var events []*Event
+	for rows.Next() {
+		var e Event
+		var scanID, severity, detail sql.NullString
+		var line sql.NullInt64
+
+		if err := rows.Scan(&e.ID, &e.Fingerprint, &scanID, &e.Event, &severity, &line,
+			&detail, &e.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan event: %w", err)
+		}
+
+		e.ScanID = scanID.String
+		e.Severity = severity.String
+		e.Detail = detail.String
+		e.Line = int(line.Int64)
+
+		events = append(events, &e)
+	}
+
+	return events, rows.Err()
+}
+
+// PruneHistory deletes scans of the project view beyond the newest
+// keepScans and scans started before olderThan, together with their "seen"
+// occurrences. State transition events are retained so first-seen and
+// resolution history survives. keepScans <= 0 and a zero olderThan disable
+// the respective limit.
+func (t *TrackingDB) PruneHistory(keepScans int, olderThan time.Time) (scans, events int64, err error) {
+	if keepScans <= 0 && olderThan.IsZero() {
+		return 0, 0, nil
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return 0, 0, fmt.Errorf("begin prune: %w", err)
+	}
+	defer tx.Rollback()
+
+	project, projectArgs := t.projectCond("COALESCE(project, '')")
+
+	var conds []string
+	var args []any
+	if keepScans > 0 {
+		conds = append(conds, `id NOT IN (SELECT id FROM scans WHERE `+project+` ORDER BY started_at DESC LIMIT ?)`)
+		args = append(append(args, projectArgs...), keepScans)
+	}
+	if !olderThan.IsZero() {
+		conds = append(conds, `started_at < ?`)
+		args = append(args, dbTime(olderThan))
+	}
+
+	where := conds[0]
+	if len(conds) > 1 {
+		where = "(" + conds[0] + ") OR (" + conds[1] + ")"
+	}
+	where = project + " AND (" + where + ")"
+	args = append(append([]any{}, projectArgs...), args...)
+
+	res, err := tx.Exec(`
+		DELETE FROM finding_events
+		WHERE event = ? AND scan_id IN (SELECT id FROM scans WHERE `+where+`)
+	`, append([]any{EventSeen}, args...)...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("prune events: %w", err)
+	}
+	events, _ = res.RowsAffected()
+
+	res, err = tx.Exec(`DELETE FROM scans WHERE `+where, args...)
+	if err != nil {
+		return 0, 0, fmt.Errorf("prune scans: %w", err)
+	}
+	scans, _ = res.RowsAffected()
+
+	if err := tx.Commit(); err != nil {
+		return 0, 0, fmt.Errorf("commit prune: %w", err)
+	}
+
+	return scans, events, nil
+}
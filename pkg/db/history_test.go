@@ -0,0 +1,126 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackingDB_ScanLifecycle(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+
+	scan := &Scan{
+		ID: "scan-1", StartedAt: now, Project: "/test",
+		FilesScanned: 3, Findings: 2, Critical: 1, Warning: 1,
+		GitCommit: "abc123", ToolVersion: "strung test", InputDigest: "deadbeef",
+	}
+	if err := db.BeginScan(scan); err != nil {
+		t.Fatalf("BeginScan failed: %v", err)
+	}
+
+	latest, err := db.LatestScan()
+	if err != nil {
+		t.Fatalf("LatestScan failed: %v", err)
+	}
+	if latest == nil || latest.Result != ScanRunning || latest.FinishedAt != nil {
+		t.Fatalf("Expected running scan, got %+v", latest)
+	}
+
+	if err := db.FinishScan("scan-1", ScanSucceeded, 2, 0, 0, now.Add(time.Second)); err != nil {
+		t.Fatalf("FinishScan failed: %v", err)
+	}
+
+	latest, _ = db.LatestScan()
+	if latest.Result != ScanSucceeded || latest.New != 2 || latest.FinishedAt == nil {
+		t.Errorf("Scan not finished: %+v", latest)
+	}
+	if latest.GitCommit != "abc123" || latest.InputDigest != "deadbeef" || latest.Critical != 1 {
+		t.Errorf("Scan metadata lost: %+v", latest)
+	}
+
+	if err := db.FinishScan("missing", ScanFailed, 0, 0, 0, now); err == nil {
+		t.Error("FinishScan should fail for unknown scan")
+	}
+}
+
+func TestTrackingDB_EventTimeline(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	db.Store(&Finding{
+		Fingerprint: "fp-timeline-1", IssueID: "t1",
+		File: "a.ts", Line: 1, Severity: "warning", Category: "x", Message: "m",
+		FirstSeen: now, LastSeen: now,
+	})
+
+	db.RecordEvent(&Event{Fingerprint: "fp-timeline-1", ScanID: "s1", Event: EventNew,
+		Severity: "warning", Line: 1, CreatedAt: now})
+	db.RecordObservations([]Observation{
+		{Fingerprint: "fp-timeline-1", Line: 2, Severity: "warning", ScanID: "s2", SeenAt: now.Add(time.Hour)},
+	})
+	db.RecordEvent(&Event{Fingerprint: "fp-timeline-1", ScanID: "s3", Event: EventChanged,
+		Severity: "critical", Line: 2, Detail: "severity warning → critical", CreatedAt: now.Add(2 * time.Hour)})
+
+	events, err := db.GetEvents("fp-timeline-1")
+	if err != nil {
+		t.Fatalf("GetEvents failed: %v", err)
+	}
+
+	want := []string{EventNew, EventSeen, EventChanged}
+	if len(events) != len(want) {
+		t.Fatalf("Expected %d events, got %d", len(want), len(events))
+	}
+	for i, e := range events {
+		if e.Event != want[i] {
+			t.Errorf("Event %d: got %s, want %s", i, e.Event, want[i])
+		}
+	}
+	if events[1].ScanID != "s2" || events[1].Line != 2 {
+		t.Errorf("Seen event wrong: %+v", events[1])
+	}
+}
+
+func TestTrackingDB_PruneHistory(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	base := time.Now().Add(-72 * time.Hour)
+	for i, id := range []string{"s1", "s2", "s3"} {
+		at := base.Add(time.Duration(i) * 24 * time.Hour)
+		db.BeginScan(&Scan{ID: id, StartedAt: at})
+		db.RecordEvent(&Event{Fingerprint: "fp", ScanID: id, Event: EventSeen, CreatedAt: at})
+	}
+	db.RecordEvent(&Event{Fingerprint: "fp", ScanID: "s1", Event: EventNew, CreatedAt: base})
+
+	scans, events, err := db.PruneHistory(2, time.Time{})
+	if err != nil {
+		t.Fatalf("PruneHistory failed: %v", err)
+	}
+	if scans != 1 || events != 1 {
+		t.Errorf("Expected 1 scan and 1 event pruned, got %d and %d", scans, events)
+	}
+
+	remaining, _ := db.GetScans(0)
+	if len(remaining) != 2 || remaining[0].ID != "s3" {
+		t.Errorf("Wrong scans kept: %d", len(remaining))
+	}
+
+	// The "new" transition survives even though its scan was pruned
+	timeline, _ := db.GetEvents("fp")
+	if len(timeline) != 3 || timeline[0].Event != EventNew {
+		t.Errorf("Transition events should be retained, got %d events", len(timeline))
+	}
+
+	// Age-based retention
+	scans, _, _ = db.PruneHistory(0, base.Add(12*time.Hour))
+	if scans != 0 {
+		t.Errorf("No remaining scan is older than the cutoff, pruned %d", scans)
+	}
+	scans, _, _ = db.PruneHistory(0, base.Add(36*time.Hour))
+	if scans != 1 {
+		t.Errorf("Expected 1 scan pruned by age, got %d", scans)
+	}
+}
@@ -0,0 +1,47 @@
+package db
+
+import (
+	"fmt"
+	"time"
+)
+
+// Suppressed reports whether the finding's issue was closed in Beads while
+// the finding persists, so sync leaves the issue alone
+func (f *Finding) Suppressed() bool {
+	return f.SuppressedAt != nil
+}
+
+// SetIssueStatus records the status last read for a finding's issue
+func (t *TrackingDB) SetIssueStatus(fingerprint, status string) error {
+	if _, err := t.db.Exec(`UPDATE findings SET issue_status = ? WHERE fingerprint = ?`,
+		nullString(status), fingerprint); err != nil {
+		return fmt.Errorf("set issue status %s: %w", fingerprint[:12], err)
+	}
+	return nil
+}
+
+// Suppress marks a finding as acknowledged: its issue was closed by hand,
+// so sync neither updates nor re-files it while the finding persists
+func (t *TrackingDB) Suppress(fingerprint string, at time.Time) error {
+	return t.setSuppressed(fingerprint, &at)
+}
+
+// Unsuppress clears a suppression, e.g. after the issue was reopened
+func (t *TrackingDB) Unsuppress(fingerprint string) error {
+	return t.setSuppressed(fingerprint, nil)
+}
+
+func (t *TrackingDB) setSuppressed(fingerprint string, at *time.Time) error {
+	var value any
+	if at != nil {
+		value = dbTime(*at)
+	}
+	result, err := t.db.Exec(`UPDATE findings SET suppressed_at = ? WHERE fingerprint = ?`, value, fingerprint)
+	if err != nil {
+		return fmt.Errorf("suppress %s: %w", fingerprint[:12], err)
+	}
+	if rows, _ := result.RowsAffected(); rows == 0 {
+		return fmt.Errorf("finding %s not found", fingerprint[:12])
+	}
+	return nil
+}
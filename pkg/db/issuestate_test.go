@@ -0,0 +1,74 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTrackingDB_Suppress(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	f := &Finding{
+		Fingerprint: ComputeFingerprint("a.go", "security", "msg", "", 1),
+		IssueID:     "bd-1", File: "a.go", Line: 1, Severity: "warning", Category: "security", Message: "msg",
+		FirstSeen: now, LastSeen: now,
+	}
+	if err := db.Store(f); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	if err := db.SetIssueStatus(f.Fingerprint, "closed"); err != nil {
+		t.Fatalf("SetIssueStatus failed: %v", err)
+	}
+	if err := db.Suppress(f.Fingerprint, now); err != nil {
+		t.Fatalf("Suppress failed: %v", err)
+	}
+
+	// Suppressed findings stay unresolved but leave the open list
+	got, _ := db.Get(f.Fingerprint)
+	if !got.Suppressed() || got.IssueStatus != "closed" || got.ResolvedAt != nil {
+		t.Errorf("suppressed finding = %+v", got)
+	}
+	if unresolved, _ := db.GetUnresolved(); len(unresolved) != 1 {
+		t.Errorf("GetUnresolved = %d findings, want 1", len(unresolved))
+	}
+	if n, _ := db.Count(FindingQuery{Status: StatusOpen}); n != 0 {
+		t.Errorf("open count = %d, want 0", n)
+	}
+	if n, _ := db.Count(FindingQuery{Status: StatusSuppressed}); n != 1 {
+		t.Errorf("suppressed count = %d, want 1", n)
+	}
+
+	// Updates keep the suppression; a regression after resolving clears it
+	if err := db.Store(f); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if got, _ := db.Get(f.Fingerprint); !got.Suppressed() {
+		t.Error("Store cleared suppression of an open finding")
+	}
+	if err := db.MarkResolved(f.Fingerprint, now); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+	if err := db.Store(f); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if got, _ := db.Get(f.Fingerprint); got.Suppressed() || got.ResolvedAt != nil {
+		t.Errorf("regressed finding = %+v, want open and unsuppressed", got)
+	}
+
+	if err := db.Suppress(f.Fingerprint, now); err != nil {
+		t.Fatalf("Suppress failed: %v", err)
+	}
+	if err := db.Unsuppress(f.Fingerprint); err != nil {
+		t.Fatalf("Unsuppress failed: %v", err)
+	}
+	if got, _ := db.Get(f.Fingerprint); got.Suppressed() {
+		t.Error("Unsuppress left finding suppressed")
+	}
+
+	if err := db.Suppress("0000000000000000", now); err == nil {
+		t.Error("Suppress of unknown finding should fail")
+	}
+}
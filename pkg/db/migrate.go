@@ -0,0 +1,432 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// ErrSchemaTooNew is returned when a database was written by a newer strung
+// than the running binary understands
+var ErrSchemaTooNew = errors.New("database schema is newer than this version of strung supports")
+
+// ErrSchemaOutdated is returned by OpenReadOnly for a database that needs
+// migrating first
+var ErrSchemaOutdated = errors.New("database schema is out of date")
+
+// migration upgrades the schema from version-1 to version.
+// Migrations must be idempotent: databases created before schema versioning
+// report version 0 but may already contain some of the tables.
+type migration struct {
+	version int
+	name    string
+	up      func(tx *sql.Tx) error
+}
+
+// migrations is the ordered schema history. Append only; never edit a
+// migration that has shipped.
+var migrations = []migration{
+	{1, "findings and operation log", execStatements(`
+		CREATE TABLE IF NOT EXISTS findings (
+			fingerprint TEXT PRIMARY KEY,
+			issue_id TEXT NOT NULL,
+			file TEXT NOT NULL,
+			line INTEGER NOT NULL,
+			severity TEXT NOT NULL,
+			category TEXT NOT NULL,
+			message TEXT NOT NULL,
+			first_seen TIMESTAMP NOT NULL,
+			last_seen TIMESTAMP NOT NULL,
+			resolved_at TIMESTAMP
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_findings_issue_id ON findings(issue_id);
+		CREATE INDEX IF NOT EXISTS idx_findings_file ON findings(file);
+		CREATE INDEX IF NOT EXISTS idx_findings_resolved ON findings(resolved_at);
+
+		CREATE TABLE IF NOT EXISTS operation_log (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			operation TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			issue_id TEXT,
+			status TEXT NOT NULL,
+			error TEXT,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_op_status ON operation_log(status);
+		CREATE INDEX IF NOT EXISTS idx_op_fingerprint ON operation_log(fingerprint);
+	`)},
+	{2, "finding column and last scan ID", addColumns("findings",
+		"col INTEGER NOT NULL DEFAULT 0",
+		"last_scan_id TEXT",
+	)},
+	{3, "scan history and finding events", execStatements(`
+		CREATE TABLE IF NOT EXISTS scans (
+			id TEXT PRIMARY KEY,
+			started_at TIMESTAMP NOT NULL,
+			finished_at TIMESTAMP,
+			project TEXT,
+			files_scanned INTEGER NOT NULL DEFAULT 0,
+			findings INTEGER NOT NULL DEFAULT 0,
+			critical INTEGER NOT NULL DEFAULT 0,
+			warning INTEGER NOT NULL DEFAULT 0,
+			info INTEGER NOT NULL DEFAULT 0,
+			git_commit TEXT,
+			tool_version TEXT,
+			input_digest TEXT,
+			new_count INTEGER NOT NULL DEFAULT 0,
+			changed_count INTEGER NOT NULL DEFAULT 0,
+			resolved_count INTEGER NOT NULL DEFAULT 0,
+			result TEXT NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_scans_started ON scans(started_at);
+
+		CREATE TABLE IF NOT EXISTS finding_events (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			fingerprint TEXT NOT NULL,
+			scan_id TEXT,
+			event TEXT NOT NULL,
+			severity TEXT,
+			line INTEGER,
+			detail TEXT,
+			created_at TIMESTAMP NOT NULL
+		);
+
+		CREATE INDEX IF NOT EXISTS idx_events_fingerprint ON finding_events(fingerprint);
+		CREATE INDEX IF NOT EXISTS idx_events_scan ON finding_events(scan_id);
+	`)},
+	{4, "full finding payload", addColumns("findings",
+		"suggestion TEXT",
+		"code_snippet TEXT",
+		"tool TEXT",
+		"rule_id TEXT",
+		"issue_hash TEXT",
+	)},
+	{5, "fingerprint algorithm version", addColumns("findings",
+		"fp_version INTEGER NOT NULL DEFAULT 1",
+	)},
+	{6, "branch tracking", func(tx *sql.Tx) error {
+		if err := addColumns("findings", "branch TEXT NOT NULL DEFAULT ''")(tx); err != nil {
+			return err
+		}
+		if err := addColumns("scans", "branch TEXT")(tx); err != nil {
+			return err
+		}
+		return execStatements(`
+			CREATE INDEX IF NOT EXISTS idx_findings_branch ON findings(branch);
+
+			CREATE TABLE IF NOT EXISTS branch_resolutions (
+				branch TEXT NOT NULL,
+				fingerprint TEXT NOT NULL,
+				resolved_at TIMESTAMP NOT NULL,
+				PRIMARY KEY (branch, fingerprint)
+			);
+		`)(tx)
+	}},
+	{7, "projects", func(tx *sql.Tx) error {
+		if err := addColumns("findings", "project TEXT NOT NULL DEFAULT ''")(tx); err != nil {
+			return err
+		}
+		if err := addColumns("operation_log", "project TEXT NOT NULL DEFAULT ''")(tx); err != nil {
+			return err
+		}
+		return execStatements(`CREATE INDEX IF NOT EXISTS idx_findings_project ON findings(project);`)(tx)
+	}},
+	{8, "issue state from Beads", addColumns("findings",
+		"issue_status TEXT",
+		"suppressed_at TIMESTAMP",
+	)},
+	{9, "issue comments", execStatements(`
+		CREATE TABLE IF NOT EXISTS issue_comments (
+			issue_id TEXT NOT NULL,
+			digest TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			scan_id TEXT,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (issue_id, digest)
+		);
+	`)},
+	{10, "finding source context", addColumns("findings", "source_context TEXT")},
+	{11, "UTC timestamps", utcTimestamps(
+		"findings.first_seen", "findings.last_seen", "findings.resolved_at", "findings.suppressed_at",
+		"operation_log.created_at", "scans.started_at", "scans.finished_at", "finding_events.created_at",
+		"branch_resolutions.resolved_at", "issue_comments.created_at",
+	)},
+	{12, "issue comments by project", commentProjects},
+}
+
+// SchemaVersion is the schema version this binary creates and understands
+func SchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// MigrationInfo describes a single schema migration
+type MigrationInfo struct {
+	Version int
+	Name    string
+}
+
+// MigrationPlan describes what Open would do to a database
+type MigrationPlan struct {
+	Path    string
+	Exists  bool // File exists and contains tracking tables
+	Current int
+	Target  int
+	Pending []MigrationInfo
+}
+
+// MigrationResult describes a migration applied by Open
+type MigrationResult struct {
+	From    int
+	To      int
+	Applied []MigrationInfo
+	Backup  string // Copy of the database taken before migrating ("" for new databases)
+}
+
+func execStatements(stmts string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		_, err := tx.Exec(stmts)
+		return err
+	}
+}
+
+// addColumns adds each "name definition" column unless it already exists
+func addColumns(table string, columns ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, col := range columns {
+			name := strings.Fields(col)[0]
+
+			exists, err := columnExists(tx, table, name)
+			if err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			if _, err := tx.Exec(fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s", table, col)); err != nil {
+				return fmt.Errorf("add column %s.%s: %w", table, name, err)
+			}
+		}
+		return nil
+	}
+}
+
+// commentProjects keys issue_comments by project as well, since projects
+// with separate backends may reuse issue IDs. Existing comments take the
+// project of their finding.
+func commentProjects(tx *sql.Tx) error {
+	exists, err := columnExists(tx, "issue_comments", "project")
+	if err != nil || exists {
+		return err
+	}
+	return execStatements(`
+		CREATE TABLE issue_comments_v12 (
+			project TEXT NOT NULL DEFAULT '',
+			issue_id TEXT NOT NULL,
+			digest TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			scan_id TEXT,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (project, issue_id, digest)
+		);
+		INSERT INTO issue_comments_v12 (project, issue_id, digest, fingerprint, kind, scan_id, created_at)
+			SELECT COALESCE((SELECT f.project FROM findings f WHERE f.fingerprint = c.fingerprint), ''),
+				issue_id, digest, fingerprint, kind, scan_id, created_at
+			FROM issue_comments c;
+		DROP TABLE issue_comments;
+		ALTER TABLE issue_comments_v12 RENAME TO issue_comments;
+	`)(tx)
+}
+
+// utcTimestamps rewrites "table.column" timestamps in the stored format
+// (see dbTime). Older versions stored each time with its local offset, so
+// timestamps did not compare or sort in time order.
+func utcTimestamps(columns ...string) func(tx *sql.Tx) error {
+	return func(tx *sql.Tx) error {
+		for _, col := range columns {
+			table, name, _ := strings.Cut(col, ".")
+
+			rows, err := tx.Query(fmt.Sprintf("SELECT rowid, %s FROM %s WHERE %s IS NOT NULL", name, table, name))
+			if err != nil {
+				return fmt.Errorf("read %s: %w", col, err)
+			}
+			values := map[int64]time.Time{}
+			for rows.Next() {
+				var id int64
+				var value any
+				if err := rows.Scan(&id, &value); err != nil {
+					rows.Close()
+					return fmt.Errorf("read %s: %w", col, err)
+				}
+				// The driver parses every format it wrote; leave anything else
+				if t, ok := value.(time.Time); ok {
+					values[id] = t
+				}
+			}
+			rows.Close()
+			if err := rows.Err(); err != nil {
+				return fmt.Errorf("read %s: %w", col, err)
+			}
+
+			for id, t := range values {
+				if _, err := tx.Exec(fmt.Sprintf("UPDATE %s SET %s = ? WHERE rowid = ?", table, name), dbTime(t), id); err != nil {
+					return fmt.Errorf("rewrite %s: %w", col, err)
+				}
+			}
+		}
+		return nil
+	}
+}
+
+func columnExists(tx *sql.Tx, table, column string) (bool, error) {
+	var count int
+	err := tx.QueryRow(`SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?`, table, column).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("inspect %s.%s: %w", table, column, err)
+	}
+	return count > 0, nil
+}
+
+// userVersion reads the schema version stored in the database header
+func userVersion(db *sql.DB) (int, error) {
+	var version int
+	if err := db.QueryRow("PRAGMA user_version").Scan(&version); err != nil {
+		return 0, fmt.Errorf("read schema version: %w", err)
+	}
+	return version, nil
+}
+
+// hasTables reports whether the database already holds tracking data
+func hasTables(db *sql.DB) (bool, error) {
+	var count int
+	err := db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'findings'`).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("inspect schema: %w", err)
+	}
+	return count > 0, nil
+}
+
+func pendingMigrations(current int) []migration {
+	var pending []migration
+	for _, m := range migrations {
+		if m.version > current {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// checkNotTooNew returns ErrSchemaTooNew if version is newer than this
+// binary's schema
+func checkNotTooNew(path string, version int) error {
+	if target := SchemaVersion(); version > target {
+		return fmt.Errorf("%s has schema version %d, this binary supports up to %d: %w",
+			path, version, target, ErrSchemaTooNew)
+	}
+	return nil
+}
+
+// migrate applies pending migrations, backing up existing databases first
+func migrate(db *sql.DB, path string) (*MigrationResult, error) {
+	current, err := userVersion(db)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkNotTooNew(path, current); err != nil {
+		return nil, err
+	}
+	target := SchemaVersion()
+	if current == target {
+		return nil, nil
+	}
+
+	result := &MigrationResult{From: current, To: target}
+
+	existing, err := hasTables(db)
+	if err != nil {
+		return nil, err
+	}
+	if existing && !isMemoryPath(path) {
+		result.Backup = fmt.Sprintf("%s.v%d-%s.bak", path, current, time.Now().Format("20060102T150405"))
+		if _, err := db.Exec("VACUUM INTO ?", result.Backup); err != nil {
+			return nil, fmt.Errorf("back up %s before migrating: %w", path, err)
+		}
+	}
+
+	for _, m := range pendingMigrations(current) {
+		if err := applyMigration(db, m); err != nil {
+			return nil, err
+		}
+		result.Applied = append(result.Applied, MigrationInfo{Version: m.version, Name: m.name})
+	}
+
+	return result, nil
+}
+
+func applyMigration(db *sql.DB, m migration) error {
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin migration %d: %w", m.version, err)
+	}
+	defer tx.Rollback()
+
+	if err := m.up(tx); err != nil {
+		return fmt.Errorf("migration %d (%s): %w", m.version, m.name, err)
+	}
+	if _, err := tx.Exec(fmt.Sprintf("PRAGMA user_version = %d", m.version)); err != nil {
+		return fmt.Errorf("set schema version %d: %w", m.version, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit migration %d: %w", m.version, err)
+	}
+	return nil
+}
+
+func isMemoryPath(path string) bool {
+	return path == ":memory:" || path == ""
+}
+
+// PlanMigrations reports the migrations Open would apply, without modifying
+// or creating the database
+func PlanMigrations(path string) (*MigrationPlan, error) {
+	plan := &MigrationPlan{Path: path, Target: SchemaVersion()}
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		for _, m := range migrations {
+			plan.Pending = append(plan.Pending, MigrationInfo{Version: m.version, Name: m.name})
+		}
+		return plan, nil
+	}
+
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	defer db.Close()
+
+	if plan.Current, err = userVersion(db); err != nil {
+		return nil, err
+	}
+	if plan.Exists, err = hasTables(db); err != nil {
+		return nil, err
+	}
+	if plan.Current > plan.Target {
+		return plan, fmt.Errorf("%s has schema version %d, this binary supports up to %d: %w",
+			path, plan.Current, plan.Target, ErrSchemaTooNew)
+	}
+
+	for _, m := range pendingMigrations(plan.Current) {
+		plan.Pending = append(plan.Pending, MigrationInfo{Version: m.version, Name: m.name})
+	}
+	return plan, nil
+}
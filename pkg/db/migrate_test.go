@@ -0,0 +1,271 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// legacySchema is the findings table as created before schema versioning
+const legacySchema = `CREATE TABLE findings (
+	fingerprint TEXT PRIMARY KEY, issue_id TEXT NOT NULL, file TEXT NOT NULL,
+	line INTEGER NOT NULL, severity TEXT NOT NULL, category TEXT NOT NULL,
+	message TEXT NOT NULL, first_seen TIMESTAMP NOT NULL, last_seen TIMESTAMP NOT NULL,
+	resolved_at TIMESTAMP)`
+
+func createLegacyDB(t *testing.T, path string) {
+	t.Helper()
+	legacy, err := sql.Open("sqlite", path)
+	if err != nil {
+		t.Fatalf("open legacy: %v", err)
+	}
+	defer legacy.Close()
+
+	if _, err := legacy.Exec(legacySchema); err != nil {
+		t.Fatalf("create legacy schema: %v", err)
+	}
+	_, err = legacy.Exec(`INSERT INTO findings VALUES ('fp-legacy-1', 't1', 'a.ts', 1, 'warning', 'x', 'm', ?, ?, NULL)`,
+		time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("insert legacy row: %v", err)
+	}
+}
+
+func TestOpen_NewDatabaseAtLatestVersion(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	version, err := userVersion(db.db)
+	if err != nil {
+		t.Fatalf("userVersion failed: %v", err)
+	}
+	if version != SchemaVersion() {
+		t.Errorf("New database at version %d, want %d", version, SchemaVersion())
+	}
+	if m := db.Migration(); m == nil || m.Backup != "" {
+		t.Errorf("New database should migrate without backup: %+v", m)
+	}
+}
+
+func TestOpen_MigratesLegacyDatabase(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	createLegacyDB(t, dbPath)
+
+	plan, err := PlanMigrations(dbPath)
+	if err != nil {
+		t.Fatalf("PlanMigrations failed: %v", err)
+	}
+	if !plan.Exists || plan.Current != 0 || len(plan.Pending) != SchemaVersion() {
+		t.Errorf("Unexpected plan: %+v", plan)
+	}
+
+	db, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open legacy failed: %v", err)
+	}
+	defer db.Close()
+
+	m := db.Migration()
+	if m == nil || m.From != 0 || m.To != SchemaVersion() {
+		t.Fatalf("Unexpected migration: %+v", m)
+	}
+	if _, err := os.Stat(m.Backup); err != nil {
+		t.Errorf("Backup not written: %v", err)
+	}
+
+	// Legacy rows survive and new columns are usable
+	f, err := db.Get("fp-legacy-1")
+	if err != nil || f == nil {
+		t.Fatalf("Legacy finding lost: %v", err)
+	}
+	if err := db.RecordObservations([]Observation{
+		{Fingerprint: "fp-legacy-1", Line: 2, Column: 4, ScanID: "s1", SeenAt: time.Now()},
+	}); err != nil {
+		t.Errorf("New columns unusable after migration: %v", err)
+	}
+
+	// Reopening is a no-op
+	db.Close()
+	db, err = Open(dbPath)
+	if err != nil {
+		t.Fatalf("Reopen failed: %v", err)
+	}
+	if db.Migration() != nil {
+		t.Error("Current database should not migrate again")
+	}
+}
+
+func TestMigrate_UTCTimestamps(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Rows as older versions wrote them: time.Time.String() in local time
+	legacy := map[string]string{
+		"aaa111": "2026-01-01 20:00:00 -0500 EST",                  // 2026-01-02 01:00 UTC
+		"bbb222": "2026-01-02 00:30:00.5 +0000 UTC m=+0.000000001", // 2026-01-02 00:30 UTC
+	}
+	for fp, at := range legacy {
+		f := &Finding{Fingerprint: fp, IssueID: "bd-" + fp, File: "a.go", Line: 1, Severity: "warning",
+			Category: "c", Message: "m", FirstSeen: time.Now(), LastSeen: time.Now()}
+		if err := db.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		if _, err := db.db.Exec(`UPDATE findings SET first_seen = ? WHERE fingerprint = ?`, at, fp); err != nil {
+			t.Fatalf("write legacy time: %v", err)
+		}
+	}
+
+	if err := applyMigration(db.db, migrationNamed(t, "UTC timestamps")); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	got, err := db.Query(FindingQuery{Sort: "first_seen"})
+	if err != nil {
+		t.Fatalf("Query failed: %v", err)
+	}
+	if fps := fingerprints(got); len(fps) != 2 || fps[0] != "bbb222" {
+		t.Errorf("first_seen order = %v, want [bbb222 aaa111]", fps)
+	}
+	want := time.Date(2026, 1, 2, 1, 0, 0, 0, time.UTC)
+	f, err := db.Get("aaa111")
+	if err != nil || f == nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if !f.FirstSeen.Equal(want) {
+		t.Errorf("aaa111 first seen = %v, want %v", f.FirstSeen, want)
+	}
+}
+
+func TestMigrate_CommentProjects(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	db.SetProject("api")
+	now := time.Now()
+	if err := db.Store(&Finding{Fingerprint: "fp-api", IssueID: "bd-1", File: "a.go", Line: 1,
+		Severity: "warning", Category: "c", Message: "m", FirstSeen: now, LastSeen: now}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	// The table as version 9 created it
+	if _, err := db.db.Exec(`
+		DROP TABLE issue_comments;
+		CREATE TABLE issue_comments (
+			issue_id TEXT NOT NULL,
+			digest TEXT NOT NULL,
+			fingerprint TEXT NOT NULL,
+			kind TEXT NOT NULL,
+			scan_id TEXT,
+			created_at TIMESTAMP NOT NULL,
+			PRIMARY KEY (issue_id, digest)
+		);
+		INSERT INTO issue_comments VALUES ('bd-1', 'abc', 'fp-api', 'severity', NULL, '2026-01-01 00:00:00.000000000+00:00');
+	`); err != nil {
+		t.Fatalf("create legacy table: %v", err)
+	}
+
+	if err := applyMigration(db.db, migrationNamed(t, "issue comments by project")); err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+
+	if posted, err := db.CommentPosted("bd-1", "severity", "abc"); err != nil || !posted {
+		t.Errorf("comment not kept under its finding's project: %v, %v", posted, err)
+	}
+	db.SetProject("")
+	if posted, _ := db.CommentPosted("bd-1", "severity", "abc"); posted {
+		t.Error("comment visible in the default project")
+	}
+}
+
+// migrationNamed returns the migration called name
+func migrationNamed(t *testing.T, name string) migration {
+	t.Helper()
+	for _, m := range migrations {
+		if m.name == name {
+			return m
+		}
+	}
+	t.Fatalf("no migration %q", name)
+	return migration{}
+}
+
+func TestOpenReadOnly(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "legacy.db")
+	createLegacyDB(t, dbPath)
+	before, _ := os.ReadFile(dbPath)
+
+	if _, err := OpenReadOnly(dbPath); !errors.Is(err, ErrSchemaOutdated) {
+		t.Fatalf("Expected ErrSchemaOutdated, got %v", err)
+	}
+	if after, _ := os.ReadFile(dbPath); string(after) != string(before) {
+		t.Error("OpenReadOnly modified an outdated database")
+	}
+
+	db, err := Open(dbPath)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	db.Close()
+
+	ro, err := OpenReadOnly(dbPath)
+	if err != nil {
+		t.Fatalf("OpenReadOnly failed: %v", err)
+	}
+	defer ro.Close()
+	if f, err := ro.Get("fp-legacy-1"); err != nil || f == nil {
+		t.Errorf("Get failed: %v", err)
+	}
+	if err := ro.BeginScan(&Scan{ID: "s1", StartedAt: time.Now()}); err == nil {
+		t.Error("Write through a read-only database succeeded")
+	}
+}
+
+func TestOpen_RefusesNewerSchema(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "future.db")
+
+	future, err := sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	future.Exec("PRAGMA user_version = 999")
+	future.Close()
+
+	if _, err := Open(dbPath); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("Expected ErrSchemaTooNew, got %v", err)
+	}
+	if _, err := PlanMigrations(dbPath); !errors.Is(err, ErrSchemaTooNew) {
+		t.Errorf("PlanMigrations: expected ErrSchemaTooNew, got %v", err)
+	}
+
+	// The refused database is left as it was, not switched to WAL
+	future, err = sql.Open("sqlite", dbPath)
+	if err != nil {
+		t.Fatalf("reopen: %v", err)
+	}
+	defer future.Close()
+	var mode string
+	if err := future.QueryRow("PRAGMA journal_mode").Scan(&mode); err != nil {
+		t.Fatalf("journal_mode: %v", err)
+	}
+	if mode == "wal" {
+		t.Error("Open switched a newer database to WAL")
+	}
+}
+
+func TestPlanMigrations_MissingFile(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "absent.db")
+
+	plan, err := PlanMigrations(dbPath)
+	if err != nil {
+		t.Fatalf("PlanMigrations failed: %v", err)
+	}
+	if plan.Exists || len(plan.Pending) != SchemaVersion() {
+		t.Errorf("Unexpected plan: %+v", plan)
+	}
+	if _, err := os.Stat(dbPath); !os.IsNotExist(err) {
+		t.Error("PlanMigrations must not create the database")
+	}
+}
@@ -0,0 +1,160 @@
+package db
+
+import (
+	"crypto/sha256"
+	"fmt"
+)
+
+// ProjectFingerprint namespaces a fingerprint by project, so identical
+// findings in different projects (two repositories with src/main.go) are
+// tracked separately. The default project ("") keeps plain fingerprints.
+func ProjectFingerprint(project, fingerprint string) string {
+	if project == "" {
+		return fingerprint
+	}
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(project+"\x00"+fingerprint)))
+}
+
+// SetProject scopes the database to a project view: findings and operations
+// are stored under it, and findings, scans and operations are read from it
+// only. Without a view, reads aggregate every project and writes use the
+// default project ("").
+func (t *TrackingDB) SetProject(project string) {
+	t.project = project
+	t.scoped = true
+}
+
+// Project returns the project view; "" is the default project
+func (t *TrackingDB) Project() string {
+	return t.project
+}
+
+// Fingerprint computes a finding's fingerprint in the project view
+func (t *TrackingDB) Fingerprint(file, category, message, codeSnippet, sourceContext string, line int) string {
+	fp, _ := ComputeFingerprintContext(FingerprintVersion, file, category, message, codeSnippet, sourceContext, line)
+	return ProjectFingerprint(t.project, fp)
+}
+
+// UseProject scopes the database to project like SetProject. The first named
+// project used with a database adopts the findings tracked before projects
+// existed, re-keying them into its namespace with their issues and history,
+// and labels their scans. Returns the number of findings adopted.
+func (t *TrackingDB) UseProject(project string) (int, error) {
+	t.SetProject(project)
+	if project == "" {
+		return 0, nil
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("begin project %s: %w", project, err)
+	}
+	defer tx.Rollback()
+
+	if n, err := adoptable(tx); err != nil || n == 0 {
+		return 0, err
+	}
+
+	rows, err := tx.Query(`SELECT fingerprint, fp_version FROM findings WHERE project = ''`)
+	if err != nil {
+		return 0, fmt.Errorf("get unscoped findings: %w", err)
+	}
+	type key struct {
+		fingerprint string
+		version     int
+	}
+	var legacy []key
+	for rows.Next() {
+		var k key
+		if err := rows.Scan(&k.fingerprint, &k.version); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan unscoped finding: %w", err)
+		}
+		legacy = append(legacy, k)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+	if len(legacy) == 0 {
+		return 0, nil
+	}
+
+	for _, k := range legacy {
+		if err := rekeyTx(tx, k.fingerprint, ProjectFingerprint(project, k.fingerprint), k.version); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := tx.Exec(`UPDATE findings SET project = ? WHERE project = ''`, project); err != nil {
+		return 0, fmt.Errorf("adopt findings: %w", err)
+	}
+	if _, err := tx.Exec(`UPDATE operation_log SET project = ? WHERE project = ''`, project); err != nil {
+		return 0, fmt.Errorf("adopt operations: %w", err)
+	}
+	// Scans recorded the report's project path before projects existed
+	if _, err := tx.Exec(`
+		UPDATE scans SET project = ?
+		WHERE COALESCE(project, '') = '' OR project LIKE '%/%' OR project LIKE '%\%'
+	`, project); err != nil {
+		return 0, fmt.Errorf("adopt scans: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit project %s: %w", project, err)
+	}
+	return len(legacy), nil
+}
+
+// Adoptable returns the number of findings UseProject would adopt into
+// project, without changing the database
+func (t *TrackingDB) Adoptable(project string) (int, error) {
+	if project == "" {
+		return 0, nil
+	}
+	return adoptable(t.db)
+}
+
+// adoptable counts the findings tracked before projects, or 0 once any
+// named project tracks findings
+func adoptable(q rowQuerier) (int, error) {
+	var named, legacy int
+	err := q.QueryRow(`
+		SELECT COALESCE(SUM(project != ''), 0), COALESCE(SUM(project = ''), 0) FROM findings
+	`).Scan(&named, &legacy)
+	if err != nil {
+		return 0, fmt.Errorf("count project findings: %w", err)
+	}
+	if named > 0 {
+		return 0, nil
+	}
+	return legacy, nil
+}
+
+// Projects returns the names of projects with tracked findings, sorted. The
+// default project is included as "" when it has findings.
+func (t *TrackingDB) Projects() ([]string, error) {
+	rows, err := t.db.Query(`SELECT DISTINCT project FROM findings ORDER BY project`)
+	if err != nil {
+		return nil, fmt.Errorf("get projects: %w", err)
+	}
+	defer rows.Close()
+
+	var projects []string
+	for rows.Next() {
+		var p string
+		if err := rows.Scan(&p); err != nil {
+			return nil, fmt.Errorf("scan project: %w", err)
+		}
+		projects = append(projects, p)
+	}
+	return projects, rows.Err()
+}
+
+// projectCond returns a condition limiting column to the project view, which
+// is always true without one
+func (t *TrackingDB) projectCond(column string) (string, []any) {
+	if !t.scoped {
+		return "1 = 1", nil
+	}
+	return column + " = ?", []any{t.project}
+}
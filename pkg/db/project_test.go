@@ -0,0 +1,140 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func TestProjectFingerprint(t *testing.T) {
+	fp := ComputeFingerprint("src/main.go", "security", "msg", "", 1)
+
+	if got := ProjectFingerprint("", fp); got != fp {
+		t.Errorf("default project changed fingerprint: %s", got)
+	}
+	a, b := ProjectFingerprint("api", fp), ProjectFingerprint("web", fp)
+	if a == fp || a == b || len(a) != len(fp) {
+		t.Errorf("project fingerprints not distinct: %s %s %s", fp, a, b)
+	}
+	if a != ProjectFingerprint("api", fp) {
+		t.Error("project fingerprint not stable")
+	}
+}
+
+func TestTrackingDB_UseProject(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	fp := ComputeFingerprint("a.go", "security", "msg", "", 1)
+	if err := db.Store(&Finding{
+		Fingerprint: fp, IssueID: "bd-1", File: "a.go", Line: 1, Severity: "critical",
+		Category: "security", Message: "msg", FirstSeen: now, LastSeen: now,
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	if err := db.BeginScan(&Scan{ID: "s1", StartedAt: now, Project: "/home/ci/api"}); err != nil {
+		t.Fatalf("BeginScan failed: %v", err)
+	}
+
+	if n, err := db.Adoptable("api"); err != nil || n != 1 {
+		t.Errorf("Adoptable = %d, %v; want 1", n, err)
+	}
+
+	// The first named project adopts the legacy findings and their scans
+	adopted, err := db.UseProject("api")
+	if err != nil {
+		t.Fatalf("UseProject failed: %v", err)
+	}
+	if adopted != 1 {
+		t.Errorf("adopted = %d, want 1", adopted)
+	}
+	apiFP := ProjectFingerprint("api", fp)
+	f, _ := db.Get(apiFP)
+	if f == nil || f.Project != "api" || f.IssueID != "bd-1" {
+		t.Fatalf("adopted finding = %+v", f)
+	}
+	if got := db.Fingerprint("a.go", "security", "msg", "", "", 1); got != apiFP {
+		t.Errorf("Fingerprint = %s, want %s", got, apiFP)
+	}
+	if scans, _ := db.GetScans(0); len(scans) != 1 || scans[0].Project != "api" {
+		t.Errorf("scans = %+v", scans)
+	}
+
+	if n, _ := db.Adoptable("web"); n != 0 {
+		t.Errorf("Adoptable after adoption = %d, want 0", n)
+	}
+
+	// A second project starts empty and does not see the first
+	adopted, err = db.UseProject("web")
+	if err != nil || adopted != 0 {
+		t.Fatalf("UseProject(web) = %d, %v", adopted, err)
+	}
+	if err := db.Store(&Finding{
+		Fingerprint: db.Fingerprint("a.go", "security", "msg", "", "", 1), IssueID: "web-1", File: "a.go", Line: 1,
+		Severity: "warning", Category: "security", Message: "msg", FirstSeen: now, LastSeen: now,
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	unresolved, _ := db.GetUnresolved()
+	if len(unresolved) != 1 || unresolved[0].IssueID != "web-1" {
+		t.Errorf("web unresolved = %+v", unresolved)
+	}
+	if n, _ := db.Count(FindingQuery{}); n != 1 {
+		t.Errorf("web Count = %d, want 1", n)
+	}
+	if scans, _ := db.GetScans(0); len(scans) != 0 {
+		t.Errorf("web scans = %+v", scans)
+	}
+
+	db.SetProject("api")
+	if f, _ := db.Lookup("web-1"); f != nil {
+		t.Error("Lookup found another project's issue")
+	}
+
+	projects, err := db.Projects()
+	if err != nil {
+		t.Fatalf("Projects failed: %v", err)
+	}
+	if len(projects) != 2 || projects[0] != "api" || projects[1] != "web" {
+		t.Errorf("Projects = %v", projects)
+	}
+
+	// Without a view, reads aggregate all projects
+	db.project, db.scoped = "", false
+	if n, _ := db.Count(FindingQuery{}); n != 2 {
+		t.Errorf("unscoped Count = %d, want 2", n)
+	}
+}
+
+func TestTrackingDB_OrphanedIssuesProjects(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	create := func(project, issueID string) {
+		t.Helper()
+		db.SetProject(project)
+		if _, err := db.LogOperation(&Operation{Operation: "create", Fingerprint: "fp-" + project,
+			IssueID: issueID, Status: "completed", CreatedAt: now}); err != nil {
+			t.Fatalf("LogOperation failed: %v", err)
+		}
+	}
+
+	// api tracks its bd-1; web's bd-1 and bd-2 were never stored
+	create("api", "bd-1")
+	if err := db.Store(&Finding{Fingerprint: "fp-api", IssueID: "bd-1", File: "a.go", Line: 1,
+		Severity: "warning", Category: "c", Message: "m", FirstSeen: now, LastSeen: now}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	create("web", "bd-1")
+	create("web", "bd-2")
+
+	db.SetProject("api")
+	if orphans, err := db.GetOrphanedIssues(); err != nil || len(orphans) != 0 {
+		t.Errorf("api orphans = %v, %v; want none", orphans, err)
+	}
+	db.SetProject("web")
+	if orphans, err := db.GetOrphanedIssues(); err != nil || len(orphans) != 2 {
+		t.Errorf("web orphans = %v, %v; want bd-1 and bd-2", orphans, err)
+	}
+}
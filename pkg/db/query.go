@@ -0,0 +1,209 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Finding status filters
+const (
+	StatusOpen       = "open"
+	StatusResolved   = "resolved"
+	StatusSuppressed = "suppressed" // Unresolved, but the issue was closed in Beads
+)
+
+// Sort keys accepted by FindingQuery.Sort. Prefix with "-" to reverse.
+var sortColumns = map[string]string{
+	"severity":   `CASE severity WHEN 'critical' THEN 0 WHEN 'warning' THEN 1 WHEN 'info' THEN 2 ELSE 3 END`,
+	"file":       `file`,
+	"category":   `category`,
+	"first_seen": `first_seen`,
+	"last_seen":  `last_seen`,
+	"issue_id":   `issue_id`,
+}
+
+// SortKeys lists the keys accepted by FindingQuery.Sort
+func SortKeys() []string {
+	return []string{"severity", "file", "category", "first_seen", "last_seen", "issue_id"}
+}
+
+// FindingQuery filters, sorts and pages tracked findings. Zero values match
+// everything; multiple filters combine with AND.
+type FindingQuery struct {
+	Status     string   // StatusOpen, StatusResolved, StatusSuppressed or "" for all
+	Severities []string // Any of these severities
+	Categories []string // Any of these categories
+	Path       string   // SQLite GLOB on the file path ("*" also matches "/")
+	IssueID    string
+
+	FirstSeenAfter  time.Time
+	FirstSeenBefore time.Time
+	LastSeenAfter   time.Time
+	LastSeenBefore  time.Time
+
+	Sort   string // Sort key (see SortKeys), "-key" for descending; default "-last_seen"
+	Limit  int    // 0 = unlimited
+	Offset int
+}
+
+// where builds the WHERE clause and arguments for the query's filters
+func (q FindingQuery) where() (string, []any, error) {
+	var conds []string
+	var args []any
+
+	switch q.Status {
+	case "":
+	case StatusOpen:
+		conds = append(conds, `resolved_at IS NULL AND suppressed_at IS NULL`)
+	case StatusResolved:
+		conds = append(conds, `resolved_at IS NOT NULL`)
+	case StatusSuppressed:
+		conds = append(conds, `resolved_at IS NULL AND suppressed_at IS NOT NULL`)
+	default:
+		return "", nil, fmt.Errorf("invalid status %q (use %s, %s or %s)", q.Status, StatusOpen, StatusResolved, StatusSuppressed)
+	}
+
+	in := func(column string, values []string) {
+		if len(values) == 0 {
+			return
+		}
+		conds = append(conds, column+` IN (?`+strings.Repeat(`, ?`, len(values)-1)+`)`)
+		for _, v := range values {
+			args = append(args, v)
+		}
+	}
+	in(`severity`, q.Severities)
+	in(`category`, q.Categories)
+
+	if q.Path != "" {
+		conds = append(conds, `file GLOB ?`)
+		args = append(args, q.Path)
+	}
+	if q.IssueID != "" {
+		conds = append(conds, `issue_id = ?`)
+		args = append(args, q.IssueID)
+	}
+
+	between := func(column string, after, before time.Time) {
+		if !after.IsZero() {
+			conds = append(conds, column+` >= ?`)
+			args = append(args, dbTime(after))
+		}
+		if !before.IsZero() {
+			conds = append(conds, column+` < ?`)
+			args = append(args, dbTime(before))
+		}
+	}
+	between(`first_seen`, q.FirstSeenAfter, q.FirstSeenBefore)
+	between(`last_seen`, q.LastSeenAfter, q.LastSeenBefore)
+
+	if len(conds) == 0 {
+		return "", nil, nil
+	}
+	return ` WHERE ` + strings.Join(conds, ` AND `), args, nil
+}
+
+// orderBy builds the ORDER BY clause; ties break on file, line and fingerprint
+// so pages are stable
+func (q FindingQuery) orderBy() (string, error) {
+	key := q.Sort
+	if key == "" {
+		key = "-last_seen"
+	}
+	dir := "ASC"
+	if strings.HasPrefix(key, "-") {
+		key, dir = key[1:], "DESC"
+	}
+
+	column, ok := sortColumns[key]
+	if !ok {
+		return "", fmt.Errorf("invalid sort key %q (use %s)", key, strings.Join(SortKeys(), ", "))
+	}
+	return ` ORDER BY ` + column + ` ` + dir + `, file ASC, line ASC, fingerprint ASC`, nil
+}
+
+// scopedWhere adds the project view to the query's filters
+func (t *TrackingDB) scopedWhere(q FindingQuery) (string, []any, error) {
+	where, args, err := q.where()
+	if err != nil || !t.scoped {
+		return where, args, err
+	}
+	cond, projectArgs := t.projectCond("project")
+	if where == "" {
+		return ` WHERE ` + cond, projectArgs, nil
+	}
+	return where + ` AND ` + cond, append(args, projectArgs...), nil
+}
+
+// Query returns the findings of the project view matching q
+func (t *TrackingDB) Query(q FindingQuery) ([]*Finding, error) {
+	where, args, err := t.scopedWhere(q)
+	if err != nil {
+		return nil, err
+	}
+	order, err := q.orderBy()
+	if err != nil {
+		return nil, err
+	}
+
+	query := `SELECT ` + findingColumns + ` FROM findings` + where + order
+	if q.Limit > 0 || q.Offset > 0 {
+		limit := q.Limit
+		if limit <= 0 {
+			limit = -1 // SQLite: no limit
+		}
+		query += ` LIMIT ? OFFSET ?`
+		args = append(args, limit, q.Offset)
+	}
+
+	return t.queryFindings(query, args...)
+}
+
+// Count returns how many findings of the project view match q's filters,
+// ignoring sort and paging
+func (t *TrackingDB) Count(q FindingQuery) (int, error) {
+	where, args, err := t.scopedWhere(q)
+	if err != nil {
+		return 0, err
+	}
+
+	var n int
+	if err := t.db.QueryRow(`SELECT COUNT(*) FROM findings`+where, args...).Scan(&n); err != nil {
+		return 0, fmt.Errorf("count findings: %w", err)
+	}
+	return n, nil
+}
+
+// Lookup finds a finding by issue ID, full fingerprint or unique fingerprint
+// prefix. It returns nil if nothing matches and an error if a prefix is
+// ambiguous.
+func (t *TrackingDB) Lookup(ref string) (*Finding, error) {
+	if ref == "" {
+		return nil, nil
+	}
+
+	f, err := t.GetByIssueID(ref)
+	if err != nil || f != nil {
+		return f, err
+	}
+
+	// Fingerprints are lowercase hex, so a prefix needs no escaping
+	if strings.Trim(strings.ToLower(ref), "0123456789abcdef") != "" {
+		return nil, nil
+	}
+	cond, args := t.projectCond("project")
+	matches, err := t.queryFindings(`SELECT `+findingColumns+` FROM findings WHERE fingerprint GLOB ? AND `+cond+` LIMIT 2`,
+		append([]any{strings.ToLower(ref) + "*"}, args...)...)
+	if err != nil {
+		return nil, err
+	}
+	switch len(matches) {
+	case 0:
+		return nil, nil
+	case 1:
+		return matches[0], nil
+	default:
+		return nil, fmt.Errorf("fingerprint prefix %q is ambiguous", ref)
+	}
+}
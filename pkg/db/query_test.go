@@ -0,0 +1,166 @@
+package db
+
+import (
+	"testing"
+	"time"
+)
+
+func seedQuery(t *testing.T, db *TrackingDB, now time.Time) {
+	t.Helper()
+	day := 24 * time.Hour
+	findings := []*Finding{
+		{Fingerprint: "aaa111", IssueID: "bd-1", File: "src/a.go", Line: 10, Severity: "warning", Category: "null-safety", Message: "m1", FirstSeen: now.Add(-10 * day), LastSeen: now},
+		{Fingerprint: "aaa222", IssueID: "bd-2", File: "src/sub/b.go", Line: 5, Severity: "critical", Category: "security", Message: "m2", FirstSeen: now.Add(-2 * day), LastSeen: now.Add(-day)},
+		{Fingerprint: "bbb333", IssueID: "bd-3", File: "lib/c.ts", Line: 1, Severity: "info", Category: "security", Message: "m3", FirstSeen: now.Add(-30 * day), LastSeen: now.Add(-5 * day)},
+	}
+	for _, f := range findings {
+		if err := db.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	if err := db.MarkResolved("bbb333", now.Add(-5*day)); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+}
+
+func fingerprints(findings []*Finding) []string {
+	var fps []string
+	for _, f := range findings {
+		fps = append(fps, f.Fingerprint)
+	}
+	return fps
+}
+
+func TestTrackingDB_Query(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	seedQuery(t, db, now)
+
+	tests := []struct {
+		name  string
+		query FindingQuery
+		want  []string
+	}{
+		{"default sort last_seen desc", FindingQuery{}, []string{"aaa111", "aaa222", "bbb333"}},
+		{"open", FindingQuery{Status: StatusOpen}, []string{"aaa111", "aaa222"}},
+		{"resolved", FindingQuery{Status: StatusResolved}, []string{"bbb333"}},
+		{"severity", FindingQuery{Severities: []string{"critical", "info"}}, []string{"aaa222", "bbb333"}},
+		{"category", FindingQuery{Categories: []string{"security"}, Sort: "file"}, []string{"bbb333", "aaa222"}},
+		{"path glob", FindingQuery{Path: "src/*"}, []string{"aaa111", "aaa222"}},
+		{"path glob extension", FindingQuery{Path: "*.ts"}, []string{"bbb333"}},
+		{"issue id", FindingQuery{IssueID: "bd-2"}, []string{"aaa222"}},
+		{"first seen after", FindingQuery{FirstSeenAfter: now.Add(-7 * 24 * time.Hour)}, []string{"aaa222"}},
+		{"last seen before", FindingQuery{LastSeenBefore: now.Add(-2 * 24 * time.Hour)}, []string{"bbb333"}},
+		{"sort severity", FindingQuery{Sort: "severity"}, []string{"aaa222", "aaa111", "bbb333"}},
+		{"sort severity desc", FindingQuery{Sort: "-severity"}, []string{"bbb333", "aaa111", "aaa222"}},
+		{"page", FindingQuery{Sort: "file", Limit: 1, Offset: 1}, []string{"aaa111"}},
+		{"offset only", FindingQuery{Sort: "file", Offset: 2}, []string{"aaa222"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := db.Query(tt.query)
+			if err != nil {
+				t.Fatalf("Query failed: %v", err)
+			}
+			gotFPs := fingerprints(got)
+			if len(gotFPs) != len(tt.want) {
+				t.Fatalf("got %v, want %v", gotFPs, tt.want)
+			}
+			for i := range tt.want {
+				if gotFPs[i] != tt.want[i] {
+					t.Fatalf("got %v, want %v", gotFPs, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTrackingDB_QueryMixedOffsets(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Same day on the wall clock, but "late" is seven hours after "early"
+	east := time.FixedZone("JST", 9*3600)
+	west := time.FixedZone("EST", -5*3600)
+	early := time.Date(2026, 1, 2, 8, 0, 0, 0, east) // 2026-01-01 23:00 UTC
+	late := time.Date(2026, 1, 1, 20, 0, 0, 0, west) // 2026-01-02 01:00 UTC
+	for fp, at := range map[string]time.Time{"aaa111": early, "bbb222": late} {
+		f := &Finding{Fingerprint: fp, IssueID: "bd-" + fp, File: "a.go", Line: 1, Severity: "warning",
+			Category: "c", Message: "m", FirstSeen: at, LastSeen: at}
+		if err := db.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	midnight := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name  string
+		query FindingQuery
+		want  []string
+	}{
+		{"after", FindingQuery{FirstSeenAfter: midnight}, []string{"bbb222"}},
+		{"before", FindingQuery{FirstSeenBefore: midnight.In(west)}, []string{"aaa111"}},
+		{"sort", FindingQuery{Sort: "first_seen"}, []string{"aaa111", "bbb222"}},
+	}
+	for _, tt := range tests {
+		got, err := db.Query(tt.query)
+		if err != nil {
+			t.Fatalf("%s: Query failed: %v", tt.name, err)
+		}
+		if fps := fingerprints(got); len(fps) != len(tt.want) || fps[0] != tt.want[0] {
+			t.Errorf("%s: got %v, want %v", tt.name, fps, tt.want)
+		}
+	}
+}
+
+func TestTrackingDB_QueryInvalid(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	if _, err := db.Query(FindingQuery{Status: "closed"}); err == nil {
+		t.Error("expected error for invalid status")
+	}
+	if _, err := db.Query(FindingQuery{Sort: "message"}); err == nil {
+		t.Error("expected error for invalid sort key")
+	}
+}
+
+func TestTrackingDB_Count(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedQuery(t, db, time.Now().Truncate(time.Second))
+
+	n, err := db.Count(FindingQuery{Status: StatusOpen, Limit: 1})
+	if err != nil {
+		t.Fatalf("Count failed: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Count = %d, want 2 (paging ignored)", n)
+	}
+}
+
+func TestTrackingDB_Lookup(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	seedQuery(t, db, time.Now().Truncate(time.Second))
+
+	for ref, want := range map[string]string{"bd-3": "bbb333", "aaa111": "aaa111", "BBB": "bbb333"} {
+		f, err := db.Lookup(ref)
+		if err != nil {
+			t.Fatalf("Lookup(%q) failed: %v", ref, err)
+		}
+		if f == nil || f.Fingerprint != want {
+			t.Errorf("Lookup(%q) = %v, want %s", ref, f, want)
+		}
+	}
+
+	if _, err := db.Lookup("aaa"); err == nil {
+		t.Error("expected error for ambiguous prefix")
+	}
+	if f, err := db.Lookup("zzz"); err != nil || f != nil {
+		t.Errorf("Lookup(zzz) = %v, %v; want nil, nil", f, err)
+	}
+}
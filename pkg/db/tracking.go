@@ -3,7 +3,7 @@
 package db
 
 import (
-	"crypto/sha256"
+	"crypto/rand"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -12,42 +12,20 @@ import (
 	_ "modernc.org/sqlite"
 )
 
-const schema = `
-CREATE TABLE IF NOT EXISTS findings (
-	fingerprint TEXT PRIMARY KEY,
-	issue_id TEXT NOT NULL,
-	file TEXT NOT NULL,
-	line INTEGER NOT NULL,
-	severity TEXT NOT NULL,
-	category TEXT NOT NULL,
-	message TEXT NOT NULL,
-	first_seen TIMESTAMP NOT NULL,
-	last_seen TIMESTAMP NOT NULL,
-	resolved_at TIMESTAMP
-);
-
-CREATE INDEX IF NOT EXISTS idx_findings_issue_id ON findings(issue_id);
-CREATE INDEX IF NOT EXISTS idx_findings_file ON findings(file);
-CREATE INDEX IF NOT EXISTS idx_findings_resolved ON findings(resolved_at);
-
-CREATE TABLE IF NOT EXISTS operation_log (
-	id INTEGER PRIMARY KEY AUTOINCREMENT,
-	operation TEXT NOT NULL,
-	fingerprint TEXT NOT NULL,
-	issue_id TEXT,
-	status TEXT NOT NULL,
-	error TEXT,
-	created_at TIMESTAMP NOT NULL
-);
-
-CREATE INDEX IF NOT EXISTS idx_op_status ON operation_log(status);
-CREATE INDEX IF NOT EXISTS idx_op_fingerprint ON operation_log(fingerprint);
-`
+// findingColumns is the column list matching scanFinding
+const findingColumns = `fingerprint, issue_id, file, line, col, severity, category, message,
+	first_seen, last_seen, resolved_at, last_scan_id,
+	suggestion, code_snippet, tool, rule_id, issue_hash, fp_version, branch, project,
+	issue_status, suppressed_at, source_context`
 
 // TrackingDB manages the findings database
 type TrackingDB struct {
-	db   *sql.DB
-	path string
+	db        *sql.DB
+	path      string
+	migration *MigrationResult
+	branch    string // Branch view; "" is the base branch (see SetBranch)
+	project   string // Project view (see SetProject)
+	scoped    bool   // Reads are limited to project
 }
 
 // Finding represents a tracked finding
@@ -56,12 +34,48 @@ type Finding struct {
 	IssueID     string
 	File        string
 	Line        int
+	Column      int
 	Severity    string
 	Category    string
 	Message     string
 	FirstSeen   time.Time
 	LastSeen    time.Time
 	ResolvedAt  *time.Time
+	LastScanID  string // Scan that most recently observed the finding
+
+	// Full scanner payload, kept so fingerprints can be re-derived and the
+	// filed issue audited without re-scanning
+	Suggestion  string
+	CodeSnippet string
+	Tool        string // Scanner that reported the finding (e.g. "ubs")
+	RuleID      string
+	IssueHash   string // beads.Issue.ContentHash of the issue as last filed, for drift detection
+
+	// SourceContext is the code read from disk around a finding reported
+	// without a snippet, as fingerprinted by FingerprintV3
+	SourceContext string
+
+	FingerprintVersion int // Algorithm that produced Fingerprint (0 = FingerprintVersion)
+
+	// Branch that first reported the finding and owns it until merged; ""
+	// is the base branch
+	Branch string
+
+	Project string // Project namespace of Fingerprint; "" is the default project
+
+	// Issue tracker state as of the last sync that read it
+	IssueStatus  string     // Status of the issue in Beads; "" if never read
+	SuppressedAt *time.Time // Issue closed in Beads while the finding persists
+}
+
+// Observation records that a tracked finding was seen again in a scan
+type Observation struct {
+	Fingerprint string
+	Line        int
+	Column      int
+	Severity    string
+	ScanID      string
+	SeenAt      time.Time
 }
 
 // Operation represents a logged operation
@@ -73,6 +87,7 @@ type Operation struct {
 	Status      string // "pending", "completed", "failed"
 	Error       string
 	CreatedAt   time.Time
+	Project     string
 }
 
 // Open creates or opens a tracking database
@@ -82,21 +97,68 @@ func Open(path string) (*TrackingDB, error) {
 		return nil, fmt.Errorf("open database %s: %w", path, err)
 	}
 
+	// Refuse a newer schema before touching the file: switching it to WAL
+	// would change a database this binary can't use
+	version, err := userVersion(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	if err := checkNotTooNew(path, version); err != nil {
+		db.Close()
+		return nil, err
+	}
+
 	// Enable WAL mode for better concurrency
 	if _, err := db.Exec("PRAGMA journal_mode=WAL"); err != nil {
 		db.Close()
 		return nil, fmt.Errorf("enable WAL mode: %w", err)
 	}
 
-	// Initialize schema
-	if _, err := db.Exec(schema); err != nil {
+	// Bring the schema up to date
+	result, err := migrate(db, path)
+	if err != nil {
 		db.Close()
-		return nil, fmt.Errorf("initialize schema: %w", err)
+		return nil, err
+	}
+
+	return &TrackingDB{db: db, path: path, migration: result}, nil
+}
+
+// OpenReadOnly opens an existing tracking database without modifying it:
+// no WAL switch, migration or backup. Writes through the returned database
+// fail. The schema must be current; an older one returns ErrSchemaOutdated.
+func OpenReadOnly(path string) (*TrackingDB, error) {
+	db, err := sql.Open("sqlite", "file:"+path+"?mode=ro")
+	if err != nil {
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+
+	version, err := userVersion(db)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open database %s: %w", path, err)
+	}
+	if err := checkNotTooNew(path, version); err != nil {
+		db.Close()
+		return nil, err
+	}
+	if target := SchemaVersion(); version != target {
+		db.Close()
+		return nil, fmt.Errorf("%s has schema version %d, this binary needs %d: %w",
+			path, version, target, ErrSchemaOutdated)
 	}
 
 	return &TrackingDB{db: db, path: path}, nil
 }
 
+// NewScanID returns an identifier for a single sync run
+func NewScanID(at time.Time) string {
+	var b [4]byte
+	rand.Read(b[:])
+	return fmt.Sprintf("%s-%x", at.UTC().Format("20060102T150405Z"), b)
+}
+
 // Close closes the database
 func (t *TrackingDB) Close() error {
 	return t.db.Close()
@@ -107,21 +169,32 @@ func (t *TrackingDB) Path() string {
 	return t.path
 }
 
-// ComputeFingerprint generates a stable fingerprint for a finding.
-// Uses code context when available for stability across line number changes.
+// Migration returns the schema migration applied by Open, or nil if the
+// database was already current
+func (t *TrackingDB) Migration() *MigrationResult {
+	return t.migration
+}
+
+// ComputeFingerprint generates a stable fingerprint for a finding using the
+// latest algorithm (FingerprintVersion). Uses code context when available for
+// stability across line number changes.
 func ComputeFingerprint(file, category, message, codeSnippet string, line int) string {
-	h := sha256.New()
+	fp, _ := ComputeFingerprintVersion(FingerprintVersion, file, category, message, codeSnippet, line)
+	return fp
+}
 
-	if codeSnippet != "" {
-		// Use code context for stability
-		context := normalizeCodeContext(codeSnippet)
-		fmt.Fprintf(h, "%s:%s:%s:%s", file, category, message, context)
-	} else {
-		// Fallback to line-based fingerprint
-		fmt.Fprintf(h, "%s:%d:%s:%s", file, line, category, message)
-	}
+// HasPayload reports whether the full scanner payload was stored. Rows
+// written before payload tracking only carry the summary columns.
+func (f *Finding) HasPayload() bool {
+	return f.IssueHash != ""
+}
 
-	return fmt.Sprintf("%x", h.Sum(nil))
+// RecomputeFingerprint re-derives the fingerprint from the stored payload
+// using the algorithm version and project the row was keyed with
+func (f *Finding) RecomputeFingerprint() string {
+	fp, _ := ComputeFingerprintContext(f.FingerprintVersion, f.File, f.Category, f.Message, f.CodeSnippet,
+		f.SourceContext, f.Line)
+	return ProjectFingerprint(f.Project, fp)
 }
 
 // normalizeCodeContext extracts first 3 + last 3 lines, normalized
@@ -149,20 +222,48 @@ func normalizeCodeContext(snippet string) string {
 	return strings.Join(normalized, "|")
 }
 
-// Store stores or updates a finding (upsert)
+// Store stores or updates a finding (upsert). New rows belong to the
+// current branch view. Existing rows keep their branch unless they were
+// resolved or the base branch stores them, which claims the finding.
 func (t *TrackingDB) Store(f *Finding) error {
 	query := `
-		INSERT INTO findings (fingerprint, issue_id, file, line, severity, category, message, first_seen, last_seen)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO findings (fingerprint, issue_id, file, line, col, severity, category, message,
+			first_seen, last_seen, last_scan_id, suggestion, code_snippet, tool, rule_id, issue_hash,
+			fp_version, branch, project, source_context)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(fingerprint) DO UPDATE SET
-			last_seen = excluded.last_seen,
+			branch = CASE WHEN excluded.branch = '' OR findings.resolved_at IS NOT NULL
+				THEN excluded.branch ELSE findings.branch END,
+			issue_id = excluded.issue_id,
+			file = excluded.file,
+			line = excluded.line,
+			col = excluded.col,
 			severity = excluded.severity,
+			category = excluded.category,
+			message = excluded.message,
+			last_seen = excluded.last_seen,
+			last_scan_id = excluded.last_scan_id,
+			suggestion = excluded.suggestion,
+			code_snippet = excluded.code_snippet,
+			source_context = excluded.source_context,
+			tool = excluded.tool,
+			rule_id = excluded.rule_id,
+			issue_hash = excluded.issue_hash,
+			fp_version = excluded.fp_version,
+			suppressed_at = CASE WHEN findings.resolved_at IS NULL THEN findings.suppressed_at END,
 			resolved_at = NULL
 	`
 
+	version := f.FingerprintVersion
+	if version == 0 {
+		version = FingerprintVersion
+	}
+
 	_, err := t.db.Exec(query,
-		f.Fingerprint, f.IssueID, f.File, f.Line, f.Severity, f.Category, f.Message,
-		f.FirstSeen, f.LastSeen)
+		f.Fingerprint, f.IssueID, f.File, f.Line, f.Column, f.Severity, f.Category, f.Message,
+		dbTime(f.FirstSeen), dbTime(f.LastSeen), nullString(f.LastScanID),
+		nullString(f.Suggestion), nullString(f.CodeSnippet), nullString(f.Tool), nullString(f.RuleID),
+		nullString(f.IssueHash), version, t.branch, t.project, nullString(f.SourceContext))
 	if err != nil {
 		return fmt.Errorf("store finding %s: %w", f.Fingerprint[:12], err)
 	}
@@ -172,19 +273,9 @@ func (t *TrackingDB) Store(f *Finding) error {
 
 // Get retrieves a finding by fingerprint
 func (t *TrackingDB) Get(fingerprint string) (*Finding, error) {
-	query := `
-		SELECT fingerprint, issue_id, file, line, severity, category, message, first_seen, last_seen, resolved_at
-		FROM findings
-		WHERE fingerprint = ?
-	`
-
-	var f Finding
-	var resolvedAt sql.NullTime
-
-	err := t.db.QueryRow(query, fingerprint).Scan(
-		&f.Fingerprint, &f.IssueID, &f.File, &f.Line, &f.Severity, &f.Category, &f.Message,
-		&f.FirstSeen, &f.LastSeen, &resolvedAt)
+	query := `SELECT ` + findingColumns + ` FROM findings WHERE fingerprint = ?`
 
+	f, err := scanFinding(t.db.QueryRow(query, fingerprint))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -192,28 +283,18 @@ func (t *TrackingDB) Get(fingerprint string) (*Finding, error) {
 		return nil, fmt.Errorf("get finding %s: %w", fingerprint[:12], err)
 	}
 
-	if resolvedAt.Valid {
-		f.ResolvedAt = &resolvedAt.Time
-	}
-
-	return &f, nil
+	return f, nil
 }
 
-// GetByIssueID retrieves a finding by Beads issue ID
+// GetByIssueID retrieves a finding of the project view by Beads issue ID.
+// Projects with separate backends may reuse IDs; the most recently seen
+// finding wins without a view.
 func (t *TrackingDB) GetByIssueID(issueID string) (*Finding, error) {
-	query := `
-		SELECT fingerprint, issue_id, file, line, severity, category, message, first_seen, last_seen, resolved_at
-		FROM findings
-		WHERE issue_id = ?
-	`
-
-	var f Finding
-	var resolvedAt sql.NullTime
-
-	err := t.db.QueryRow(query, issueID).Scan(
-		&f.Fingerprint, &f.IssueID, &f.File, &f.Line, &f.Severity, &f.Category, &f.Message,
-		&f.FirstSeen, &f.LastSeen, &resolvedAt)
+	cond, args := t.projectCond("project")
+	query := `SELECT ` + findingColumns + ` FROM findings WHERE issue_id = ? AND ` + cond + `
+		ORDER BY last_seen DESC LIMIT 1`
 
+	f, err := scanFinding(t.db.QueryRow(query, append([]any{issueID}, args...)...))
 	if err == sql.ErrNoRows {
 		return nil, nil
 	}
@@ -221,38 +302,38 @@ func (t *TrackingDB) GetByIssueID(issueID string) (*Finding, error) {
 		return nil, fmt.Errorf("get finding by issue %s: %w", issueID, err)
 	}
 
-	if resolvedAt.Valid {
-		f.ResolvedAt = &resolvedAt.Time
-	}
-
-	return &f, nil
+	return f, nil
 }
 
-// GetUnresolved retrieves all unresolved findings
+// GetUnresolved retrieves all unresolved findings of the project view, on
+// every branch (see InView)
 func (t *TrackingDB) GetUnresolved() ([]*Finding, error) {
+	cond, args := t.projectCond("project")
 	query := `
-		SELECT fingerprint, issue_id, file, line, severity, category, message, first_seen, last_seen, resolved_at
+		SELECT ` + findingColumns + `
 		FROM findings
-		WHERE resolved_at IS NULL
+		WHERE resolved_at IS NULL AND ` + cond + `
 		ORDER BY last_seen DESC
 	`
 
-	return t.queryFindings(query)
+	return t.queryFindings(query, args...)
 }
 
-// GetAll retrieves all findings (for debugging)
+// GetAll retrieves all findings of the project view
 func (t *TrackingDB) GetAll() ([]*Finding, error) {
+	cond, args := t.projectCond("project")
 	query := `
-		SELECT fingerprint, issue_id, file, line, severity, category, message, first_seen, last_seen, resolved_at
+		SELECT ` + findingColumns + `
 		FROM findings
+		WHERE ` + cond + `
 		ORDER BY last_seen DESC
 	`
 
-	return t.queryFindings(query)
+	return t.queryFindings(query, args...)
 }
 
-func (t *TrackingDB) queryFindings(query string) ([]*Finding, error) {
-	rows, err := t.db.Query(query)
+func (t *TrackingDB) queryFindings(query string, args ...any) ([]*Finding, error) {
+	rows, err := t.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("query findings: %w", err)
 	}
@@ -260,30 +341,147 @@ func (t *TrackingDB) queryFindings(query string) ([]*Finding, error) {
 
 	var findings []*Finding
 	for rows.Next() {
-		var f Finding
-		var resolvedAt sql.NullTime
-
-		err := rows.Scan(
-			&f.Fingerprint, &f.IssueID, &f.File, &f.Line, &f.Severity, &f.Category, &f.Message,
-			&f.FirstSeen, &f.LastSeen, &resolvedAt)
+		f, err := scanFinding(rows)
 		if err != nil {
 			return nil, fmt.Errorf("scan finding: %w", err)
 		}
+		findings = append(findings, f)
+	}
+
+	return findings, rows.Err()
+}
+
+// rowScanner is satisfied by *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// rowQuerier is implemented by *sql.DB and *sql.Tx
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// scanFinding reads a row selected with findingColumns
+func scanFinding(row rowScanner) (*Finding, error) {
+	var f Finding
+	var resolvedAt, suppressedAt sql.NullTime
+	var lastScanID, suggestion, snippet, tool, ruleID, issueHash, issueStatus, sourceContext sql.NullString
+
+	err := row.Scan(
+		&f.Fingerprint, &f.IssueID, &f.File, &f.Line, &f.Column, &f.Severity, &f.Category, &f.Message,
+		&f.FirstSeen, &f.LastSeen, &resolvedAt, &lastScanID,
+		&suggestion, &snippet, &tool, &ruleID, &issueHash, &f.FingerprintVersion, &f.Branch, &f.Project,
+		&issueStatus, &suppressedAt, &sourceContext)
+	if err != nil {
+		return nil, err
+	}
+
+	if resolvedAt.Valid {
+		f.ResolvedAt = &resolvedAt.Time
+	}
+	f.LastScanID = lastScanID.String
+	f.Suggestion = suggestion.String
+	f.CodeSnippet = snippet.String
+	f.SourceContext = sourceContext.String
+	f.Tool = tool.String
+	f.RuleID = ruleID.String
+	f.IssueHash = issueHash.String
+	f.IssueStatus = issueStatus.String
+	if suppressedAt.Valid {
+		f.SuppressedAt = &suppressedAt.Time
+	}
+
+	return &f, nil
+}
 
-		if resolvedAt.Valid {
-			f.ResolvedAt = &resolvedAt.Time
+// RecordObservations refreshes last_seen, position and scan ID for findings
+// seen again in the current scan, and logs a "seen" event for each. All rows
+// are written in one transaction. The base branch claims every finding it
+// observes; a branch view only refreshes its own rows and reopens findings
+// it had resolved for itself.
+func (t *TrackingDB) RecordObservations(obs []Observation) error {
+	if len(obs) == 0 {
+		return nil
+	}
+
+	tx, err := t.db.Begin()
+	if err != nil {
+		return fmt.Errorf("begin observations: %w", err)
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		UPDATE findings SET last_seen = ?, line = ?, col = ?, last_scan_id = ?, branch = ?
+		WHERE fingerprint = ? AND (branch = ? OR ? = '')
+	`)
+	if err != nil {
+		return fmt.Errorf("prepare observations: %w", err)
+	}
+	defer stmt.Close()
+
+	reopenStmt, err := tx.Prepare(`DELETE FROM branch_resolutions WHERE branch = ? AND fingerprint = ?`)
+	if err != nil {
+		return fmt.Errorf("prepare branch reopen: %w", err)
+	}
+	defer reopenStmt.Close()
+
+	eventStmt, err := tx.Prepare(insertEvent)
+	if err != nil {
+		return fmt.Errorf("prepare observation events: %w", err)
+	}
+	defer eventStmt.Close()
+
+	for _, o := range obs {
+		if _, err := stmt.Exec(dbTime(o.SeenAt), o.Line, o.Column, nullString(o.ScanID), t.branch,
+			o.Fingerprint, t.branch, t.branch); err != nil {
+			return fmt.Errorf("record observation %s: %w", o.Fingerprint[:12], err)
+		}
+		if t.branch != "" {
+			if _, err := reopenStmt.Exec(t.branch, o.Fingerprint); err != nil {
+				return fmt.Errorf("reopen %s on branch: %w", o.Fingerprint[:12], err)
+			}
 		}
+		if _, err := eventStmt.Exec(o.Fingerprint, nullString(o.ScanID), EventSeen,
+			o.Severity, o.Line, nil, dbTime(o.SeenAt)); err != nil {
+			return fmt.Errorf("record seen event %s: %w", o.Fingerprint[:12], err)
+		}
+	}
 
-		findings = append(findings, &f)
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit observations: %w", err)
 	}
+	return nil
+}
 
-	return findings, rows.Err()
+// nullString stores empty strings as NULL
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// timeLayout is the stored timestamp format: fixed-width UTC, so SQL
+// comparisons and ORDER BY on timestamp columns follow time order
+const timeLayout = "2006-01-02 15:04:05.000000000-07:00"
+
+// dbTime formats a timestamp for storage or comparison in SQL
+func dbTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
 }
 
-// MarkResolved marks a finding as resolved
+// MarkResolved marks a finding as resolved. In a branch view, findings the
+// branch inherited are only resolved for that branch (see MergeBranch).
 func (t *TrackingDB) MarkResolved(fingerprint string, resolvedAt time.Time) error {
+	if t.branch != "" {
+		f, err := t.Get(fingerprint)
+		if err != nil {
+			return err
+		}
+		if f != nil && f.Branch != t.branch {
+			return t.resolveOnBranch(fingerprint, resolvedAt)
+		}
+	}
+
 	query := `UPDATE findings SET resolved_at = ? WHERE fingerprint = ?`
-	result, err := t.db.Exec(query, resolvedAt, fingerprint)
+	result, err := t.db.Exec(query, dbTime(resolvedAt), fingerprint)
 	if err != nil {
 		return fmt.Errorf("mark resolved %s: %w", fingerprint[:12], err)
 	}
@@ -299,12 +497,12 @@ func (t *TrackingDB) MarkResolved(fingerprint string, resolvedAt time.Time) erro
 // LogOperation records an operation attempt
 func (t *TrackingDB) LogOperation(op *Operation) (int64, error) {
 	query := `
-		INSERT INTO operation_log (operation, fingerprint, issue_id, status, error, created_at)
-		VALUES (?, ?, ?, ?, ?, ?)
+		INSERT INTO operation_log (operation, fingerprint, issue_id, status, error, created_at, project)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
 	`
 
 	result, err := t.db.Exec(query,
-		op.Operation, op.Fingerprint, op.IssueID, op.Status, op.Error, op.CreatedAt)
+		op.Operation, op.Fingerprint, op.IssueID, op.Status, op.Error, dbTime(op.CreatedAt), t.project)
 	if err != nil {
 		return 0, fmt.Errorf("log operation: %w", err)
 	}
@@ -322,16 +520,17 @@ func (t *TrackingDB) UpdateOperationStatus(id int64, status, errorMsg string) er
 	return nil
 }
 
-// GetPendingOperations returns all pending operations
+// GetPendingOperations returns all pending operations of the project view
 func (t *TrackingDB) GetPendingOperations() ([]*Operation, error) {
+	cond, args := t.projectCond("project")
 	query := `
-		SELECT id, operation, fingerprint, issue_id, status, error, created_at
+		SELECT id, operation, fingerprint, issue_id, status, error, created_at, project
 		FROM operation_log
-		WHERE status = 'pending'
+		WHERE status = 'pending' AND ` + cond + `
 		ORDER BY created_at ASC
 	`
 
-	rows, err := t.db.Query(query)
+	rows, err := t.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get pending operations: %w", err)
 	}
@@ -343,7 +542,7 @@ func (t *TrackingDB) GetPendingOperations() ([]*Operation, error) {
 		var issueID, errMsg sql.NullString
 
 		err := rows.Scan(&op.ID, &op.Operation, &op.Fingerprint, &issueID,
-			&op.Status, &errMsg, &op.CreatedAt)
+			&op.Status, &errMsg, &op.CreatedAt, &op.Project)
 		if err != nil {
 			return nil, fmt.Errorf("scan operation: %w", err)
 		}
@@ -361,17 +560,20 @@ func (t *TrackingDB) GetPendingOperations() ([]*Operation, error) {
 	return ops, rows.Err()
 }
 
-// GetOrphanedIssues finds issues created but not in findings table
+// GetOrphanedIssues finds issues of the project view created but not in
+// the findings table. Projects with separate backends may reuse issue IDs,
+// so an issue only counts as tracked by findings of its own project.
 func (t *TrackingDB) GetOrphanedIssues() ([]string, error) {
+	cond, args := t.projectCond("o.project")
 	query := `
-		SELECT DISTINCT issue_id
-		FROM operation_log
-		WHERE operation = 'create' AND status = 'completed'
-		  AND issue_id NOT IN (SELECT issue_id FROM findings)
-		ORDER BY created_at DESC
+		SELECT DISTINCT o.issue_id
+		FROM operation_log o
+		WHERE o.operation = 'create' AND o.status = 'completed' AND ` + cond + `
+		  AND o.issue_id NOT IN (SELECT f.issue_id FROM findings f WHERE f.project = o.project)
+		ORDER BY o.created_at DESC
 	`
 
-	rows, err := t.db.Query(query)
+	rows, err := t.db.Query(query, args...)
 	if err != nil {
 		return nil, fmt.Errorf("get orphaned issues: %w", err)
 	}
@@ -389,13 +591,14 @@ func (t *TrackingDB) GetOrphanedIssues() ([]string, error) {
 	return issueIDs, rows.Err()
 }
 
-// Stats returns database statistics
+// Stats returns statistics of the project view
 func (t *TrackingDB) Stats() (total, unresolved, resolved int, err error) {
-	err = t.db.QueryRow("SELECT COUNT(*) FROM findings").Scan(&total)
+	cond, args := t.projectCond("project")
+	err = t.db.QueryRow("SELECT COUNT(*) FROM findings WHERE "+cond, args...).Scan(&total)
 	if err != nil {
 		return
 	}
-	err = t.db.QueryRow("SELECT COUNT(*) FROM findings WHERE resolved_at IS NULL").Scan(&unresolved)
+	err = t.db.QueryRow("SELECT COUNT(*) FROM findings WHERE resolved_at IS NULL AND "+cond, args...).Scan(&unresolved)
 	if err != nil {
 		return
 	}
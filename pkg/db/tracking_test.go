@@ -119,6 +119,41 @@ func TestTrackingDB_MarkResolved(t *testing.T) {
 	}
 }
 
+func TestTrackingDB_StorePayload(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now()
+	snippet := "const x = obj.prop;"
+	fp := ComputeFingerprint("test.ts", "null-safety", "msg", snippet, 42)
+
+	if err := db.Store(&Finding{
+		Fingerprint: fp, IssueID: "test-001",
+		File: "test.ts", Line: 42, Column: 7, Severity: "critical",
+		Category: "null-safety", Message: "msg",
+		FirstSeen: now, LastSeen: now,
+		Suggestion: "Add a guard", CodeSnippet: snippet,
+		Tool: "ubs", RuleID: "NS001", IssueHash: "abc123",
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	f, err := db.Get(fp)
+	if err != nil || f == nil {
+		t.Fatalf("Get failed: %v", err)
+	}
+	if f.Column != 7 || f.Suggestion != "Add a guard" || f.CodeSnippet != snippet ||
+		f.Tool != "ubs" || f.RuleID != "NS001" || f.IssueHash != "abc123" {
+		t.Errorf("Payload not round-tripped: %+v", f)
+	}
+	if !f.HasPayload() {
+		t.Error("HasPayload should be true")
+	}
+	if f.RecomputeFingerprint() != fp {
+		t.Error("Fingerprint should re-derive from stored payload")
+	}
+}
+
 func TestTrackingDB_GetUnresolved(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -173,11 +208,11 @@ func TestTrackingDB_Upsert(t *testing.T) {
 		FirstSeen: now, LastSeen: now,
 	})
 
-	// Update with new severity
+	// Update with new severity and line
 	later := now.Add(time.Hour)
 	db.Store(&Finding{
 		Fingerprint: fp, IssueID: "test-001",
-		File: "test.ts", Line: 1, Severity: "critical",
+		File: "test.ts", Line: 7, Severity: "critical",
 		Category: "x", Message: "msg",
 		FirstSeen: later, LastSeen: later,
 	})
@@ -187,12 +222,49 @@ func TestTrackingDB_Upsert(t *testing.T) {
 	if finding.Severity != "critical" {
 		t.Errorf("Severity not updated: %s", finding.Severity)
 	}
+	if finding.Line != 7 {
+		t.Errorf("Line not updated: %d", finding.Line)
+	}
 	// FirstSeen should NOT change on upsert
 	if finding.FirstSeen.After(now.Add(time.Minute)) {
 		t.Error("FirstSeen should not change on update")
 	}
 }
 
+func TestTrackingDB_RecordObservations(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	now := time.Now().Truncate(time.Second)
+	db.Store(&Finding{
+		Fingerprint: "fp-observed-1", IssueID: "t1",
+		File: "a.ts", Line: 1, Severity: "warning", Category: "x", Message: "m",
+		FirstSeen: now, LastSeen: now,
+	})
+
+	later := now.Add(24 * time.Hour)
+	err := db.RecordObservations([]Observation{
+		{Fingerprint: "fp-observed-1", Line: 3, Column: 9, ScanID: "scan-2", SeenAt: later},
+	})
+	if err != nil {
+		t.Fatalf("RecordObservations failed: %v", err)
+	}
+
+	f, _ := db.Get("fp-observed-1")
+	if !f.LastSeen.Equal(later) {
+		t.Errorf("LastSeen not refreshed: %v", f.LastSeen)
+	}
+	if f.Line != 3 || f.Column != 9 {
+		t.Errorf("Position not refreshed: %d:%d", f.Line, f.Column)
+	}
+	if f.LastScanID != "scan-2" {
+		t.Errorf("LastScanID not recorded: %q", f.LastScanID)
+	}
+	if !f.FirstSeen.Equal(now) {
+		t.Error("FirstSeen should not change on observation")
+	}
+}
+
 func TestComputeFingerprint(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -0,0 +1,141 @@
+package git
+
+import (
+	"bufio"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// uncommitted is the SHA git blame reports for lines not yet committed
+const uncommitted = "0000000000000000000000000000000000000000"
+
+// BlameLine is the commit that last changed one line
+type BlameLine struct {
+	Commit      string
+	Author      string
+	AuthorEmail string
+	AuthorTime  time.Time
+	Summary     string
+}
+
+// Uncommitted reports whether the line has local changes not yet committed
+func (b *BlameLine) Uncommitted() bool {
+	return b.Commit == uncommitted
+}
+
+// Blamer runs git blame, caching each file's result so several findings in
+// the same file cost one git invocation
+type Blamer struct {
+	repo  *Repo
+	cache map[string]blameResult
+}
+
+type blameResult struct {
+	lines []*BlameLine // Index 0 is line 1
+	err   error
+}
+
+// NewBlamer creates a blamer for the repository
+func NewBlamer(repo *Repo) *Blamer {
+	return &Blamer{repo: repo, cache: make(map[string]blameResult)}
+}
+
+// LastChange returns the most recent commit touching lines start..end of
+// file (relative to the directory the repo was opened from). end <= start
+// blames a single line. The range is clamped to the lines blame returned;
+// a range entirely outside the file is an error. Returns nil when the lines
+// are uncommitted.
+func (b *Blamer) LastChange(file string, start, end int) (*BlameLine, error) {
+	res, ok := b.cache[file]
+	if !ok {
+		res.lines, res.err = b.repo.blameFile(file)
+		b.cache[file] = res
+	}
+	if res.err != nil {
+		return nil, res.err
+	}
+
+	if end < start {
+		end = start
+	}
+	first, last := max(start, 1), min(end, len(res.lines))
+	if first > last {
+		return nil, fmt.Errorf("lines %d-%d outside %s (%d lines)", start, end, file, len(res.lines))
+	}
+	var latest *BlameLine
+	for n := first; n <= last; n++ {
+		line := res.lines[n-1]
+		if line.Uncommitted() {
+			continue
+		}
+		if latest == nil || line.AuthorTime.After(latest.AuthorTime) {
+			latest = line
+		}
+	}
+	return latest, nil
+}
+
+// blameFile blames every line of a file in the working tree
+func (r *Repo) blameFile(file string) ([]*BlameLine, error) {
+	out, err := r.run("blame", "--porcelain", "--", file)
+	if err != nil {
+		return nil, err
+	}
+	return parsePorcelain(out)
+}
+
+// parsePorcelain parses `git blame --porcelain` output. Commit details are
+// printed only the first time a commit appears, so they are shared.
+func parsePorcelain(out string) ([]*BlameLine, error) {
+	commits := make(map[string]*BlameLine)
+	var lines []*BlameLine
+	var current *BlameLine
+
+	// No line is longer than the whole output, e.g. minified source
+	scanner := bufio.NewScanner(strings.NewReader(out))
+	scanner.Buffer(make([]byte, 0, 64*1024), max(len(out)+1, 64*1024))
+	for scanner.Scan() {
+		text := scanner.Text()
+
+		// Content line ends the entry for one final line
+		if strings.HasPrefix(text, "\t") {
+			if current != nil {
+				lines = append(lines, current)
+			}
+			current = nil
+			continue
+		}
+
+		key, value, _ := strings.Cut(text, " ")
+		if current == nil {
+			// Header: <sha> <orig-line> <final-line> [<group-size>]
+			c, ok := commits[key]
+			if !ok {
+				c = &BlameLine{Commit: key}
+				commits[key] = c
+			}
+			current = c
+			continue
+		}
+
+		switch key {
+		case "author":
+			current.Author = value
+		case "author-mail":
+			current.AuthorEmail = strings.Trim(value, "<>")
+		case "author-time":
+			if sec, err := strconv.ParseInt(value, 10, 64); err == nil {
+				current.AuthorTime = time.Unix(sec, 0).UTC()
+			}
+		case "summary":
+			current.Summary = value
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("parse blame: %w", err)
+	}
+
+	return lines, nil
+}
@@ -0,0 +1,69 @@
+package git
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestBlamer_LastChange(t *testing.T) {
+	dir := initRepo(t, map[string]string{"app.ts": "one\ntwo\nthree\n"})
+
+	// Second author rewrites line 2
+	os.WriteFile(filepath.Join(dir, "app.ts"), []byte("one\nTWO\nthree\n"), 0o644)
+	gitCmd(t, dir, "-c", "user.name=Alice", "-c", "user.email=alice@example.com",
+		"commit", "-q", "-am", "Rewrite two", "--date", "2030-01-01T00:00:00Z")
+
+	// Uncommitted edit to line 3
+	os.WriteFile(filepath.Join(dir, "app.ts"), []byte("one\nTWO\nTHREE\n"), 0o644)
+
+	repo, err := Open(dir)
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	blamer := NewBlamer(repo)
+
+	first, err := blamer.LastChange("app.ts", 1, 1)
+	if err != nil || first == nil {
+		t.Fatalf("LastChange(1) = %v, %v", first, err)
+	}
+	if first.Author != "Dev" || first.AuthorEmail != "dev@example.com" || first.Summary != "initial" {
+		t.Errorf("Unexpected blame for line 1: %+v", first)
+	}
+
+	latest, _ := blamer.LastChange("app.ts", 1, 3)
+	if latest == nil || latest.Author != "Alice" || latest.Summary != "Rewrite two" {
+		t.Errorf("Expected newest committed change in range, got %+v", latest)
+	}
+
+	if only, _ := blamer.LastChange("app.ts", 3, 3); only != nil {
+		t.Errorf("Uncommitted line should have no author, got %+v", only)
+	}
+
+	// A snippet running past the end of the file is clamped to it
+	if past, err := blamer.LastChange("app.ts", 2, 10); err != nil || past == nil || past.Author != "Alice" {
+		t.Errorf("LastChange(2-10) = %+v, %v", past, err)
+	}
+	if _, err := blamer.LastChange("app.ts", 7, 9); err == nil {
+		t.Error("Expected error for lines past the end of the file")
+	}
+
+	if _, err := blamer.LastChange("missing.ts", 1, 1); err == nil {
+		t.Error("Expected error for untracked file")
+	}
+}
+
+func TestParsePorcelain_LongLine(t *testing.T) {
+	// Minified source: one line longer than bufio's default token size
+	long := strings.Repeat("x", 2*1024*1024)
+	out := "abc123 1 1 1\nauthor Dev\nsummary initial\n\t" + long + "\n"
+
+	lines, err := parsePorcelain(out)
+	if err != nil {
+		t.Fatalf("parsePorcelain failed: %v", err)
+	}
+	if len(lines) != 1 || lines[0].Author != "Dev" {
+		t.Errorf("parsePorcelain = %+v, want one line by Dev", lines)
+	}
+}
@@ -0,0 +1,178 @@
+// Package git reads repository metadata from a local checkout via the git
+// CLI. It never contacts remotes.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Repo is a local git working tree
+type Repo struct {
+	Root string // Absolute path of the working tree root
+	dir  string // Directory commands run from; paths are relative to it
+}
+
+// Open finds the repository containing dir. Returns an error when dir is
+// not inside a git working tree or git is not installed.
+func Open(dir string) (*Repo, error) {
+	r := &Repo{dir: dir}
+	root, err := r.run("rev-parse", "--show-toplevel")
+	if err != nil {
+		return nil, err
+	}
+	r.Root = root
+	return r, nil
+}
+
+// run executes git in the repo directory and returns trimmed stdout
+func (r *Repo) run(args ...string) (string, error) {
+	cmd := exec.Command("git", append([]string{"-C", r.dir}, args...)...)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return "", fmt.Errorf("git %s failed: %w\nstderr: %s", args[0], err, strings.TrimSpace(stderr.String()))
+	}
+
+	return strings.TrimSpace(stdout.String()), nil
+}
+
+// Renames returns files renamed between the given revision and the working
+// tree, mapping old path to new path. Paths are relative to the directory
+// the repo was opened from, matching how scanners report them.
+func (r *Repo) Renames(since string) (map[string]string, error) {
+	if err := checkRev(since); err != nil {
+		return nil, err
+	}
+	out, err := r.run("diff", "--relative", "-M", "--no-color", "--name-status", "--diff-filter=R", since)
+	if err != nil {
+		return nil, err
+	}
+
+	renames := make(map[string]string)
+	for _, line := range strings.Split(out, "\n") {
+		// R<score>\told\tnew
+		fields := strings.Split(line, "\t")
+		if len(fields) == 3 && strings.HasPrefix(fields[0], "R") {
+			renames[fields[1]] = fields[2]
+		}
+	}
+	return renames, nil
+}
+
+// Diff returns the unified diff between rev and the working tree, or a
+// range like "main...HEAD", with paths relative to the directory the repo
+// was opened from. Context is omitted; renames are detected. Prefixes,
+// color and external diff tools are fixed so user config (diff.noprefix,
+// diff.mnemonicPrefix, color.diff, diff.external) can't change the format.
+func (r *Repo) Diff(rev string) (string, error) {
+	if err := checkRev(rev); err != nil {
+		return "", err
+	}
+	return r.run("diff", "--relative", "-M", "-U0", "--no-color", "--no-ext-diff",
+		"--src-prefix=a/", "--dst-prefix=b/", rev)
+}
+
+// checkRev rejects revisions git would parse as an option, since they come
+// from user input
+func checkRev(rev string) error {
+	if rev == "" || strings.HasPrefix(rev, "-") {
+		return fmt.Errorf("invalid git revision %q", rev)
+	}
+	return nil
+}
+
+// Head returns the full SHA of the checked-out commit
+func (r *Repo) Head() (string, error) {
+	return r.run("rev-parse", "HEAD")
+}
+
+// Branch returns the checked-out branch name, or "" for a detached HEAD
+func (r *Repo) Branch() (string, error) {
+	branch, err := r.run("rev-parse", "--abbrev-ref", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	if branch == "HEAD" {
+		return "", nil
+	}
+	return branch, nil
+}
+
+// Prefix returns the opened directory relative to the repository root, with
+// a trailing slash ("" at the root). Prepending it to scanner paths gives
+// repository-relative paths.
+func (r *Repo) Prefix() (string, error) {
+	return r.run("rev-parse", "--show-prefix")
+}
+
+// RemoteURL returns the configured fetch URL of the named remote. It reads
+// local config only and never contacts the remote.
+func (r *Repo) RemoteURL(name string) (string, error) {
+	return r.run("config", "--get", "remote."+name+".url")
+}
+
+// WebURL converts a git remote URL to the repository's browsable URL:
+//
+//	git@github.com:user/repo.git          → https://github.com/user/repo
+//	ssh://git@host:2222/user/repo.git     → https://host/user/repo
+//	https://token@gitlab.com/g/sub/repo   → https://gitlab.com/g/sub/repo
+//
+// Returns "" for local paths and unrecognized URLs.
+func WebURL(remote string) string {
+	remote = strings.TrimSpace(remote)
+	web := "https"
+	var host, repoPath string
+
+	if i := strings.Index(remote, "://"); i >= 0 {
+		scheme, rest := remote[:i], remote[i+3:]
+		switch scheme {
+		case "http", "https", "ssh", "git", "git+ssh", "ssh+git":
+		default:
+			return ""
+		}
+		slash := strings.Index(rest, "/")
+		if slash < 0 {
+			return ""
+		}
+		host, repoPath = rest[:slash], rest[slash+1:]
+		if scheme == "http" {
+			web = "http"
+		}
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+		// Ports belong to the transport, not the web UI (except plain http(s))
+		if colon := strings.Index(host, ":"); colon >= 0 && scheme != "http" && scheme != "https" {
+			host = host[:colon]
+		}
+	} else if colon := strings.Index(remote, ":"); colon > 1 && !strings.Contains(remote[:colon], "/") {
+		// scp-like syntax: [user@]host:path
+		host, repoPath = remote[:colon], remote[colon+1:]
+		if at := strings.LastIndex(host, "@"); at >= 0 {
+			host = host[at+1:]
+		}
+	} else {
+		return ""
+	}
+
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if host == "" || repoPath == "" {
+		return ""
+	}
+
+	// Azure DevOps SSH remotes (ssh.dev.azure.com:v3/org/project/repo) map
+	// to https://dev.azure.com/org/project/_git/repo
+	if host == "ssh.dev.azure.com" {
+		parts := strings.Split(strings.TrimPrefix(repoPath, "v3/"), "/")
+		if len(parts) != 3 {
+			return ""
+		}
+		return "https://dev.azure.com/" + parts[0] + "/" + parts[1] + "/_git/" + parts[2]
+	}
+	return web + "://" + host + "/" + repoPath
+}
@@ -0,0 +1,183 @@
+// Package lang detects the programming language of a source file from its
+// name or shebang line and maps it to canonical names used for labels and
+// code fences.
+package lang
+
+import (
+	"path"
+	"strings"
+)
+
+// byExtension maps lower-case file extensions to canonical language names.
+// ".h" is claimed by C; C++ headers are recognised by their own extensions.
+var byExtension = map[string]string{
+	".c":          "c",
+	".h":          "c",
+	".cc":         "cpp",
+	".cpp":        "cpp",
+	".cxx":        "cpp",
+	".c++":        "cpp",
+	".hh":         "cpp",
+	".hpp":        "cpp",
+	".hxx":        "cpp",
+	".h++":        "cpp",
+	".cs":         "csharp",
+	".css":        "css",
+	".scss":       "scss",
+	".dart":       "dart",
+	".dockerfile": "dockerfile",
+	".ex":         "elixir",
+	".exs":        "elixir",
+	".erl":        "erlang",
+	".go":         "go",
+	".gradle":     "groovy",
+	".groovy":     "groovy",
+	".graphql":    "graphql",
+	".gql":        "graphql",
+	".hs":         "haskell",
+	".htm":        "html",
+	".html":       "html",
+	".java":       "java",
+	".js":         "javascript",
+	".cjs":        "javascript",
+	".mjs":        "javascript",
+	".jsx":        "javascript",
+	".json":       "json",
+	".kt":         "kotlin",
+	".kts":        "kotlin",
+	".lua":        "lua",
+	".md":         "markdown",
+	".mk":         "makefile",
+	".nix":        "nix",
+	".ml":         "ocaml",
+	".php":        "php",
+	".pl":         "perl",
+	".pm":         "perl",
+	".ps1":        "powershell",
+	".proto":      "protobuf",
+	".py":         "python",
+	".pyi":        "python",
+	".pyw":        "python",
+	".r":          "r",
+	".rb":         "ruby",
+	".rake":       "ruby",
+	".gemspec":    "ruby",
+	".rs":         "rust",
+	".scala":      "scala",
+	".sh":         "shell",
+	".bash":       "shell",
+	".zsh":        "shell",
+	".ksh":        "shell",
+	".sql":        "sql",
+	".svelte":     "svelte",
+	".swift":      "swift",
+	".tf":         "terraform",
+	".toml":       "toml",
+	".ts":         "typescript",
+	".cts":        "typescript",
+	".mts":        "typescript",
+	".tsx":        "typescript",
+	".vue":        "vue",
+	".xml":        "xml",
+	".yaml":       "yaml",
+	".yml":        "yaml",
+	".zig":        "zig",
+}
+
+// byFilename maps well-known extensionless (or special) file names
+var byFilename = map[string]string{
+	"dockerfile":     "dockerfile",
+	"containerfile":  "dockerfile",
+	"makefile":       "makefile",
+	"gnumakefile":    "makefile",
+	"cmakelists.txt": "cmake",
+	"jenkinsfile":    "groovy",
+	"rakefile":       "ruby",
+	"gemfile":        "ruby",
+	"vagrantfile":    "ruby",
+	"podfile":        "ruby",
+	".bashrc":        "shell",
+	".zshrc":         "shell",
+	".profile":       "shell",
+}
+
+// byInterpreter maps shebang interpreters (version suffixes stripped)
+var byInterpreter = map[string]string{
+	"sh":      "shell",
+	"bash":    "shell",
+	"dash":    "shell",
+	"zsh":     "shell",
+	"ksh":     "shell",
+	"python":  "python",
+	"node":    "javascript",
+	"deno":    "typescript",
+	"ts-node": "typescript",
+	"tsx":     "typescript",
+	"bun":     "javascript",
+	"ruby":    "ruby",
+	"perl":    "perl",
+	"php":     "php",
+	"lua":     "lua",
+	"pwsh":    "powershell",
+	"Rscript": "r",
+	"elixir":  "elixir",
+}
+
+// fence overrides the code fence info string where highlighters know the
+// language by another name
+var fence = map[string]string{
+	"terraform": "hcl",
+}
+
+// Detect returns the canonical language for a file path, or "" when the
+// name is not recognised
+func Detect(file string) string {
+	base := path.Base(strings.ReplaceAll(file, "\\", "/"))
+	lower := strings.ToLower(base)
+
+	if l, ok := byFilename[lower]; ok {
+		return l
+	}
+	// Dockerfile.prod, Makefile.common
+	if stem, _, ok := strings.Cut(lower, "."); ok {
+		if l, ok := byFilename[stem]; ok && (l == "dockerfile" || l == "makefile") {
+			return l
+		}
+	}
+
+	return byExtension[strings.ToLower(path.Ext(lower))]
+}
+
+// DetectShebang returns the language named by a "#!" first line, or ""
+func DetectShebang(firstLine string) string {
+	if !strings.HasPrefix(firstLine, "#!") {
+		return ""
+	}
+
+	fields := strings.Fields(firstLine[2:])
+	if len(fields) == 0 {
+		return ""
+	}
+	interp := path.Base(fields[0])
+
+	// #!/usr/bin/env [-S] python3 -u
+	if interp == "env" {
+		interp = ""
+		for _, f := range fields[1:] {
+			if !strings.HasPrefix(f, "-") && !strings.Contains(f, "=") {
+				interp = path.Base(f)
+				break
+			}
+		}
+	}
+
+	return byInterpreter[strings.TrimRight(interp, "0123456789.")]
+}
+
+// Fence returns the code fence info string for a canonical language name
+func Fence(language string) string {
+	if f, ok := fence[language]; ok {
+		return f
+	}
+	return language
+}
@@ -0,0 +1,59 @@
+package lang
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := map[string]string{
+		"src/app.ts":             "typescript",
+		"src/App.tsx":            "typescript",
+		"lib/index.mjs":          "javascript",
+		"include/vec.h":          "c",
+		"include/vec.hpp":        "cpp",
+		"main.go":                "go",
+		"SCRIPT.PY":              "python",
+		"infra/main.tf":          "terraform",
+		"Dockerfile":             "dockerfile",
+		"docker/Dockerfile.prod": "dockerfile",
+		"build.dockerfile":       "dockerfile",
+		"Makefile":               "makefile",
+		"rules.mk":               "makefile",
+		"CMakeLists.txt":         "cmake",
+		"Jenkinsfile":            "groovy",
+		"src\\win\\app.cs":       "csharp",
+		"README":                 "",
+		"data.unknownext":        "",
+	}
+
+	for file, want := range tests {
+		if got := Detect(file); got != want {
+			t.Errorf("Detect(%q) = %q, want %q", file, got, want)
+		}
+	}
+}
+
+func TestDetectShebang(t *testing.T) {
+	tests := map[string]string{
+		"#!/bin/sh":                            "shell",
+		"#!/usr/bin/env bash":                  "shell",
+		"#!/usr/bin/env python3":               "python",
+		"#!/usr/bin/python3.11 -u":             "python",
+		"#!/usr/bin/env -S node --no-warnings": "javascript",
+		"#!/usr/bin/env -S deno run":           "typescript",
+		"#! /usr/bin/ruby":                     "ruby",
+		"#!/usr/bin/env FOO=1 perl":            "perl",
+		"// not a shebang":                     "",
+		"#!/usr/bin/env":                       "",
+	}
+
+	for line, want := range tests {
+		if got := DetectShebang(line); got != want {
+			t.Errorf("DetectShebang(%q) = %q, want %q", line, got, want)
+		}
+	}
+}
+
+func TestFence(t *testing.T) {
+	if Fence("terraform") != "hcl" || Fence("go") != "go" || Fence("") != "" {
+		t.Error("Unexpected fence info strings")
+	}
+}
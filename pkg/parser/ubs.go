@@ -25,6 +25,12 @@ type UBSFinding struct {
 	Message     string `json:"message"`
 	Suggestion  string `json:"suggestion,omitempty"`
 	CodeSnippet string `json:"code_snippet,omitempty"`
+	Tool        string `json:"tool,omitempty"`    // Underlying analyzer, when UBS reports it
+	RuleID      string `json:"rule_id,omitempty"` // Analyzer rule identifier, when reported
+
+	// SourceContext is code read from disk around a finding reported
+	// without a snippet (strung --source-root); never part of UBS output
+	SourceContext string `json:"-"`
 }
 
 // UBSSummary represents the summary section
@@ -0,0 +1,332 @@
+// Package patch parses unified diffs (git diff output or patch files) into
+// the set of lines a change touches, for limiting findings to a pull
+// request's changes.
+package patch
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+// Scopes accepted by Filter
+const (
+	ScopeLines = "lines" // Findings on added or modified lines, plus Radius
+	ScopeFiles = "files" // Findings anywhere in a changed file
+)
+
+// Range is an inclusive span of line numbers in the new version of a file
+type Range struct {
+	Start int
+	End   int
+}
+
+// Changes records, per file, the lines a diff adds or modifies. Line
+// numbers refer to the new side of the diff.
+type Changes struct {
+	lines map[string][]Range // By new path
+	old   map[string]bool    // Old paths of renamed or deleted files
+
+	root   string // Absolute directory the diff paths are relative to
+	prefix string // Directory relative scanner paths are relative to, from root
+}
+
+// Resolve sets where scanner paths are relative to: root is the absolute
+// directory the diff's paths are relative to, prefix the scan directory
+// relative to root (see git.Repo.Prefix). Relative scanner paths are
+// joined to prefix; absolute ones under root are made relative to it.
+func (c *Changes) Resolve(root, prefix string) {
+	c.root = cleanPath(root)
+	c.prefix = prefix
+}
+
+// Parse reads a unified diff. Git's a/ and b/ path prefixes are stripped.
+// Removing lines without adding replacements touches the lines on either
+// side of the removal.
+func Parse(r io.Reader) (*Changes, error) {
+	c := &Changes{lines: make(map[string][]Range), old: make(map[string]bool)}
+
+	var (
+		file             string // New path of the current file; "" when deleted
+		oldLeft, newLeft int    // Lines remaining in the current hunk
+		next             int    // New-side number of the next hunk line
+		deleted          bool   // Lines were removed just before next
+	)
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 16*1024*1024)
+	lineNo := 0
+	for sc.Scan() {
+		lineNo++
+		text := sc.Text()
+
+		// Hunk bodies are consumed by count, so deleted lines that look
+		// like headers ("--- x") are not misread
+		if oldLeft > 0 || newLeft > 0 {
+			switch {
+			case strings.HasPrefix(text, "+"):
+				c.touch(file, next, next)
+				deleted = false
+				next++
+				newLeft--
+			case strings.HasPrefix(text, "-"):
+				deleted = true
+				oldLeft--
+			case strings.HasPrefix(text, `\`):
+				// "\ No newline at end of file"
+			default:
+				c.deletion(file, &deleted, next)
+				next++
+				oldLeft--
+				newLeft--
+			}
+			if oldLeft <= 0 && newLeft <= 0 {
+				c.deletion(file, &deleted, next)
+			}
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(text, "diff --git "):
+			file = ""
+		case strings.HasPrefix(text, "rename from "):
+			c.old[cleanPath(strings.TrimPrefix(text, "rename from "))] = true
+		case strings.HasPrefix(text, "rename to "):
+			file = cleanPath(strings.TrimPrefix(text, "rename to "))
+			c.add(file)
+		case strings.HasPrefix(text, "--- "):
+			if p := diffPath(strings.TrimPrefix(text, "--- "), "a/"); p != "" {
+				c.old[p] = true
+			}
+		case strings.HasPrefix(text, "+++ "):
+			file = diffPath(strings.TrimPrefix(text, "+++ "), "b/")
+			c.add(file)
+		case strings.HasPrefix(text, "@@ "):
+			oldCount, newStart, newCount, err := parseHunkHeader(text)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNo, err)
+			}
+			oldLeft, newLeft, next, deleted = oldCount, newCount, newStart, false
+			if newCount == 0 {
+				// Pure deletion: newStart is the line before the removed block
+				next = newStart + 1
+			}
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, fmt.Errorf("read diff: %w", err)
+	}
+
+	return c, nil
+}
+
+// add registers a changed file, which may have no changed lines (pure
+// renames, binary files)
+func (c *Changes) add(file string) {
+	if file == "" {
+		return
+	}
+	if _, ok := c.lines[file]; !ok {
+		c.lines[file] = nil
+	}
+}
+
+// touch marks lines start..end (clamped to 1) of file as changed
+func (c *Changes) touch(file string, start, end int) {
+	if file == "" {
+		return
+	}
+	if start < 1 {
+		start = 1
+	}
+	if end < start {
+		return
+	}
+
+	ranges := c.lines[file]
+	if n := len(ranges); n > 0 && start <= ranges[n-1].End+1 && start >= ranges[n-1].Start {
+		if end > ranges[n-1].End {
+			ranges[n-1].End = end
+		}
+		return
+	}
+	c.lines[file] = append(ranges, Range{Start: start, End: end})
+}
+
+// deletion marks the lines on either side of a removed block that was not
+// replaced by added lines
+func (c *Changes) deletion(file string, deleted *bool, next int) {
+	if *deleted {
+		c.touch(file, next-1, next)
+		*deleted = false
+	}
+}
+
+// Files returns the new paths of all changed files, sorted
+func (c *Changes) Files() []string {
+	files := make([]string, 0, len(c.lines))
+	for f := range c.lines {
+		files = append(files, f)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// Lines returns the changed line ranges of a file, in diff order
+func (c *Changes) Lines(file string) []Range {
+	if key, ok := c.match(file); ok {
+		return c.lines[key]
+	}
+	return nil
+}
+
+// HasFile reports whether the diff changes file, under its new path or,
+// for renamed and deleted files, its old one
+func (c *Changes) HasFile(file string) bool {
+	if _, ok := c.match(file); ok {
+		return true
+	}
+	_, ok := lookup(c.resolve(file), c.old)
+	return ok
+}
+
+// Touches reports whether line of file is within radius lines of a
+// changed line
+func (c *Changes) Touches(file string, line, radius int) bool {
+	key, ok := c.match(file)
+	if !ok {
+		return false
+	}
+	for _, r := range c.lines[key] {
+		if line >= r.Start-radius && line <= r.End+radius {
+			return true
+		}
+	}
+	return false
+}
+
+// match finds the diff path for a scanner path
+func (c *Changes) match(file string) (string, bool) {
+	return lookup(c.resolve(file), c.lines)
+}
+
+// resolve converts a scanner path to a diff path (see Resolve)
+func (c *Changes) resolve(file string) string {
+	file = cleanPath(file)
+	if path.IsAbs(file) {
+		if c.root != "" && strings.HasPrefix(file, c.root+"/") {
+			return file[len(c.root)+1:]
+		}
+		return file
+	}
+	return cleanPath(path.Join(c.prefix, file))
+}
+
+// lookup finds file among the diff paths in keys. Without an exact match,
+// an absolute path outside the root matches a diff path it ends with, and
+// a relative path (a scan of an unknown subdirectory) a diff path ending
+// with it, at a directory boundary. Ambiguous suffix matches match nothing.
+func lookup[V any](file string, keys map[string]V) (string, bool) {
+	if _, ok := keys[file]; ok {
+		return file, true
+	}
+
+	match, n := "", 0
+	for key := range keys {
+		var ok bool
+		if path.IsAbs(file) {
+			ok = strings.HasSuffix(file, "/"+key)
+		} else {
+			ok = strings.HasSuffix(key, "/"+file)
+		}
+		if ok {
+			match, n = key, n+1
+		}
+	}
+	return match, n == 1
+}
+
+// Filter limits findings to the changes of a diff
+type Filter struct {
+	Changes *Changes
+	Scope   string // ScopeLines (default) or ScopeFiles
+	Radius  int    // Extra lines of context around changed lines
+}
+
+// Keep reports whether a finding at file:line is within the changes.
+// File-level findings (line 0) are kept when their file changed.
+func (f *Filter) Keep(file string, line int) bool {
+	if f.Scope == ScopeFiles || line <= 0 {
+		_, ok := f.Changes.match(file)
+		return ok
+	}
+	return f.Changes.Touches(file, line, f.Radius)
+}
+
+// Apply returns the findings within the changes
+func (f *Filter) Apply(findings []parser.UBSFinding) []parser.UBSFinding {
+	kept := make([]parser.UBSFinding, 0, len(findings))
+	for _, finding := range findings {
+		if f.Keep(finding.File, finding.Line) {
+			kept = append(kept, finding)
+		}
+	}
+	return kept
+}
+
+// parseHunkHeader parses "@@ -a,b +c,d @@ ..."; counts default to 1
+func parseHunkHeader(text string) (oldCount, newStart, newCount int, err error) {
+	fields := strings.Fields(text)
+	if len(fields) < 4 || fields[3] != "@@" || !strings.HasPrefix(fields[1], "-") || !strings.HasPrefix(fields[2], "+") {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q", text)
+	}
+	if _, oldCount, err = parseHunkRange(fields[1][1:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q: %w", text, err)
+	}
+	if newStart, newCount, err = parseHunkRange(fields[2][1:]); err != nil {
+		return 0, 0, 0, fmt.Errorf("malformed hunk header %q: %w", text, err)
+	}
+	return oldCount, newStart, newCount, nil
+}
+
+func parseHunkRange(s string) (start, count int, err error) {
+	startStr, countStr, hasCount := strings.Cut(s, ",")
+	if start, err = strconv.Atoi(startStr); err != nil {
+		return 0, 0, err
+	}
+	count = 1
+	if hasCount {
+		if count, err = strconv.Atoi(countStr); err != nil {
+			return 0, 0, err
+		}
+	}
+	return start, count, nil
+}
+
+// diffPath extracts the path from a "---"/"+++" header value, dropping
+// git's prefix and any timestamp. Returns "" for /dev/null.
+func diffPath(value, prefix string) string {
+	p := value
+	if strings.HasPrefix(p, `"`) {
+		if unquoted, err := strconv.Unquote(p); err == nil {
+			p = unquoted
+		}
+	} else if i := strings.IndexByte(p, '\t'); i >= 0 {
+		p = p[:i]
+	}
+	if p == "/dev/null" {
+		return ""
+	}
+	return cleanPath(strings.TrimPrefix(p, prefix))
+}
+
+func cleanPath(p string) string {
+	p = path.Clean(strings.ReplaceAll(p, `\`, "/"))
+	return strings.TrimPrefix(p, "./")
+}
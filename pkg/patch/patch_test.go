@@ -0,0 +1,155 @@
+package patch
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+const sampleDiff = `diff --git a/src/a.go b/src/a.go
+index 1111111..2222222 100644
+--- a/src/a.go
++++ b/src/a.go
+@@ -10,3 +10,4 @@ func f() {
+ 	x := 1
+-	y := 2
++	y := 3
++	z := 4
+ 	return
+@@ -40,2 +41,0 @@ func g() {
+--- a comment that looks like a header
+-	old()
+diff --git a/old/b.go b/new/b.go
+similarity index 90%
+rename from old/b.go
+rename to new/b.go
+diff --git a/gone.go b/gone.go
+deleted file mode 100644
+--- a/gone.go
++++ /dev/null
+@@ -1 +0,0 @@
+-package gone
+diff --git a/c.go b/c.go
+new file mode 100644
+--- /dev/null
++++ b/c.go
+@@ -0,0 +1,2 @@
++package c
++func C() {}
+\ No newline at end of file
+`
+
+func TestParse(t *testing.T) {
+	c, err := Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	if got, want := c.Files(), []string{"c.go", "new/b.go", "src/a.go"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Files = %v, want %v", got, want)
+	}
+	if got, want := c.Lines("src/a.go"), []Range{{11, 12}, {41, 42}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(src/a.go) = %v, want %v", got, want)
+	}
+	if got, want := c.Lines("c.go"), []Range{{1, 2}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Lines(c.go) = %v, want %v", got, want)
+	}
+
+	for _, file := range []string{"src/a.go", "./src/a.go", "/home/ci/repo/src/a.go", "old/b.go", "gone.go"} {
+		if !c.HasFile(file) {
+			t.Errorf("HasFile(%q) = false", file)
+		}
+	}
+	if c.HasFile("a.go.bak") || c.HasFile("other/a.go") {
+		t.Error("HasFile matched an unrelated path")
+	}
+}
+
+func TestChanges_Resolve(t *testing.T) {
+	diff := ""
+	for _, file := range []string{"main.go", "pkg/a/util.go", "pkg/b/util.go"} {
+		diff += fmt.Sprintf("--- a/%s\n+++ b/%s\n@@ -1 +1 @@\n-x\n+y\n", file, file)
+	}
+	c, err := Parse(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	check := func(files map[string]bool) {
+		t.Helper()
+		for file, want := range files {
+			if got := c.HasFile(file); got != want {
+				t.Errorf("HasFile(%q) = %v, want %v", file, got, want)
+			}
+		}
+	}
+
+	// Without a root, suffixes match only when unambiguous
+	check(map[string]bool{
+		"a/util.go":               true,
+		"util.go":                 false, // pkg/a or pkg/b
+		"cmd/x/main.go":           false, // not the root main.go
+		"/home/ci/repo/main.go":   true,
+		"/home/ci/repo/x/util.go": false,
+	})
+
+	// Scanned from pkg/a: paths resolve exactly
+	c.Resolve("/home/ci/repo", "pkg/a/")
+	check(map[string]bool{
+		"util.go":                   true,
+		"../b/util.go":              true,
+		"main.go":                   false,
+		"/home/ci/repo/main.go":     true,
+		"/home/ci/repo/cmd/main.go": false,
+		"/home/ci/repo/pkg/b/x.go":  false,
+	})
+	if got := c.Lines("util.go"); len(got) != 1 {
+		t.Errorf("Lines(util.go) = %v", got)
+	}
+}
+
+func TestFilter(t *testing.T) {
+	c, err := Parse(strings.NewReader(sampleDiff))
+	if err != nil {
+		t.Fatalf("Parse failed: %v", err)
+	}
+
+	findings := []parser.UBSFinding{
+		{File: "src/a.go", Line: 12},
+		{File: "src/a.go", Line: 14},
+		{File: "src/a.go", Line: 30},
+		{File: "src/a.go", Line: 0},
+		{File: "new/b.go", Line: 5},
+		{File: "untouched.go", Line: 1},
+	}
+	lines := func(fs []parser.UBSFinding) []string {
+		var out []string
+		for _, f := range fs {
+			out = append(out, fmt.Sprintf("%s:%d", f.File, f.Line))
+		}
+		return out
+	}
+
+	tests := []struct {
+		filter Filter
+		want   []string
+	}{
+		{Filter{Changes: c}, []string{"src/a.go:12", "src/a.go:0"}},
+		{Filter{Changes: c, Radius: 2}, []string{"src/a.go:12", "src/a.go:14", "src/a.go:0"}},
+		{Filter{Changes: c, Scope: ScopeFiles}, []string{"src/a.go:12", "src/a.go:14", "src/a.go:30", "src/a.go:0", "new/b.go:5"}},
+	}
+	for _, tt := range tests {
+		if got := lines(tt.filter.Apply(findings)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Apply(scope=%q radius=%d) = %v, want %v", tt.filter.Scope, tt.filter.Radius, got, tt.want)
+		}
+	}
+}
+
+func TestParse_Malformed(t *testing.T) {
+	if _, err := Parse(strings.NewReader("+++ b/a.go\n@@ -1 +x @@\n")); err == nil {
+		t.Error("expected error for malformed hunk header")
+	}
+}
@@ -0,0 +1,227 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+// CompareFormats lists the output formats accepted by Comparison.Write
+var CompareFormats = []string{"text", "json", "markdown"}
+
+// ComparedFinding is one finding in a scan-to-scan comparison
+type ComparedFinding struct {
+	Fingerprint string   `json:"fingerprint"`
+	File        string   `json:"file"`
+	Line        int      `json:"line"`
+	Column      int      `json:"column,omitempty"`
+	Severity    string   `json:"severity"`
+	Category    string   `json:"category"`
+	Message     string   `json:"message"`
+	Suggestion  string   `json:"suggestion,omitempty"`
+	Changes     []string `json:"changes,omitempty"`    // Field changes since base
+	MovedFrom   string   `json:"moved_from,omitempty"` // Base "file:line" of a moved finding
+	Displaced   bool     `json:"displaced,omitempty"`  // Moved, but possibly another finding (see sync.MoveRecord.Displaced)
+}
+
+// Location returns "file:line"
+func (f ComparedFinding) Location() string {
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// Comparison classifies the findings of a head scan against a base scan
+type Comparison struct {
+	Base      string            `json:"base"`
+	Head      string            `json:"head"`
+	New       []ComparedFinding `json:"new"`
+	Fixed     []ComparedFinding `json:"fixed"`
+	Changed   []ComparedFinding `json:"changed"`
+	Moved     []ComparedFinding `json:"moved"`
+	Unchanged int               `json:"unchanged"`
+}
+
+// NewComparison builds a comparison from the diff of two scans (see
+// sync.DiffScans). base and head name the scans in the output.
+func NewComparison(base, head string, d *sync.DiffResult) *Comparison {
+	c := &Comparison{
+		Base:      base,
+		Head:      head,
+		New:       make([]ComparedFinding, 0, len(d.New)),
+		Fixed:     make([]ComparedFinding, 0, len(d.Resolved)),
+		Changed:   make([]ComparedFinding, 0, len(d.Changed)),
+		Moved:     make([]ComparedFinding, 0, len(d.Moved)),
+		Unchanged: len(d.Unchanged),
+	}
+
+	for _, f := range d.New {
+		c.New = append(c.New, comparedFinding(f, nil, ""))
+	}
+	for _, r := range d.Resolved {
+		cf := comparedFinding(storedFinding(r), nil, "")
+		cf.Fingerprint = r.Fingerprint
+		c.Fixed = append(c.Fixed, cf)
+	}
+	for _, ch := range d.Changed {
+		c.Changed = append(c.Changed, comparedFinding(ch.Current, ch.Fields, ""))
+	}
+	for _, m := range d.Moved {
+		cf := comparedFinding(m.Current, m.Fields, fmt.Sprintf("%s:%d", m.Previous.File, m.Previous.Line))
+		cf.Displaced = m.Displaced()
+		c.Moved = append(c.Moved, cf)
+	}
+
+	for _, list := range [][]ComparedFinding{c.New, c.Fixed, c.Changed, c.Moved} {
+		sortCompared(list)
+	}
+	return c
+}
+
+// CountNew returns the number of new findings at or above minSeverity.
+// Displaced moves count as new, so swapping a fixed finding for another one
+// does not slip past a gate.
+func (c *Comparison) CountNew(minSeverity string) int {
+	n := 0
+	for _, f := range c.New {
+		if severityRank(f.Severity) <= severityRank(minSeverity) {
+			n++
+		}
+	}
+	for _, f := range c.Moved {
+		if f.Displaced && severityRank(f.Severity) <= severityRank(minSeverity) {
+			n++
+		}
+	}
+	return n
+}
+
+func comparedFinding(f parser.UBSFinding, fields []sync.FieldChange, movedFrom string) ComparedFinding {
+	cf := ComparedFinding{
+		Fingerprint: sync.Fingerprint(f),
+		File:        f.File,
+		Line:        f.Line,
+		Column:      f.Column,
+		Severity:    f.Severity,
+		Category:    f.Category,
+		Message:     f.Message,
+		Suggestion:  f.Suggestion,
+		MovedFrom:   movedFrom,
+	}
+	for _, fc := range fields {
+		cf.Changes = append(cf.Changes, fc.String())
+	}
+	return cf
+}
+
+func sortCompared(findings []ComparedFinding) {
+	sort.SliceStable(findings, func(i, j int) bool {
+		a, b := findings[i], findings[j]
+		if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+}
+
+// Write renders the comparison in the named format
+func (c *Comparison) Write(w io.Writer, format string) error {
+	switch format {
+	case "text", "":
+		return c.WriteText(w)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(c)
+	case "markdown", "md":
+		return c.WriteMarkdown(w)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(CompareFormats, ", "))
+	}
+}
+
+// summary counts the findings in each class
+func (c *Comparison) summary() string {
+	return fmt.Sprintf("%d new, %d fixed, %d changed, %d moved, %d unchanged",
+		len(c.New), len(c.Fixed), len(c.Changed), len(c.Moved), c.Unchanged)
+}
+
+// compareSection is one listed class of findings
+type compareSection struct {
+	title    string
+	findings []ComparedFinding
+}
+
+// sections returns the listed classes in display order
+func (c *Comparison) sections() []compareSection {
+	return []compareSection{
+		{"New", c.New},
+		{"Changed", c.Changed},
+		{"Moved", c.Moved},
+		{"Fixed", c.Fixed},
+	}
+}
+
+// details describes a finding's move and field changes on one line
+func (f ComparedFinding) details() string {
+	var parts []string
+	if f.MovedFrom != "" {
+		parts = append(parts, "from "+f.MovedFrom)
+	}
+	if f.Displaced {
+		parts = append(parts, "counted as new")
+	}
+	parts = append(parts, f.Changes...)
+	return strings.Join(strings.Fields(strings.Join(parts, "; ")), " ")
+}
+
+// WriteText renders the comparison as plain text
+func (c *Comparison) WriteText(w io.Writer) error {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "Comparing %s → %s\n", c.Base, c.Head)
+	fmt.Fprintf(&b, "Findings: %s\n", c.summary())
+	for _, s := range c.sections() {
+		if len(s.findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", s.title)
+		for _, f := range s.findings {
+			fmt.Fprintf(&b, "  [%s] %s %s: %s", f.Severity, f.Location(), f.Category, f.Message)
+			if d := f.details(); d != "" {
+				fmt.Fprintf(&b, " (%s)", d)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteMarkdown renders the comparison as Markdown for a PR comment
+func (c *Comparison) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## strung diff\n\n")
+	fmt.Fprintf(&b, "%s → %s: **%s**.\n", markdownCell(c.Base), markdownCell(c.Head), c.summary())
+	for _, s := range c.sections() {
+		if len(s.findings) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n### %s (%d)\n\n| Severity | Location | Category | Message | Details |\n|---|---|---|---|---|\n", s.title, len(s.findings))
+		for _, f := range s.findings {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s | %s |\n", f.Severity, markdownCell(f.Location()), markdownCell(f.Category),
+				markdownCell(f.Message), markdownCell(orDash(f.details())))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
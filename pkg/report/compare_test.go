@@ -0,0 +1,101 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+func TestComparison(t *testing.T) {
+	base := []parser.UBSFinding{
+		{File: "a.go", Line: 20, Severity: "warning", Category: "leak", Message: "escalated"},
+		{File: "a.go", Line: 30, Severity: "info", Category: "style", Message: "fixed"},
+	}
+	head := []parser.UBSFinding{
+		{File: "a.go", Line: 20, Severity: "critical", Category: "leak", Message: "escalated"},
+		{File: "b.go", Line: 1, Severity: "info", Category: "style", Message: "minor | nit"},
+		{File: "a.go", Line: 40, Severity: "critical", Category: "xss", Message: "introduced"},
+	}
+
+	c := NewComparison("base.json", "head.json", sync.DiffScans(base, head, nil, sync.DefaultMatchThreshold))
+
+	if len(c.New) != 2 || c.New[0].Severity != "critical" || len(c.Fixed) != 1 || len(c.Changed) != 1 {
+		t.Fatalf("Comparison = %+v", c)
+	}
+	if got := c.CountNew("critical"); got != 1 {
+		t.Errorf("CountNew(critical) = %d, want 1", got)
+	}
+	if got := c.CountNew("info"); got != 2 {
+		t.Errorf("CountNew(info) = %d, want 2", got)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Write(&buf, "text"); err != nil {
+		t.Fatal(err)
+	}
+	text := buf.String()
+	for _, want := range []string{"2 new, 1 fixed, 1 changed, 0 moved, 0 unchanged", "[critical] a.go:20 leak: escalated (severity warning → critical)"} {
+		if !strings.Contains(text, want) {
+			t.Errorf("text missing %q:\n%s", want, text)
+		}
+	}
+
+	buf.Reset()
+	if err := c.Write(&buf, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+	for _, want := range []string{"### New (2)", "| info | b.go:1 | style | minor \\| nit | - |", "### Fixed (1)"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+	if strings.Contains(md, "### Moved") {
+		t.Error("empty sections should be omitted")
+	}
+
+	buf.Reset()
+	if err := c.Write(&buf, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Comparison
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(decoded.New) != 2 || decoded.Moved == nil {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	if err := c.Write(&buf, "html"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestComparison_CountNewDisplaced(t *testing.T) {
+	base := []parser.UBSFinding{
+		{File: "a.go", Line: 10, Severity: "critical", Category: "sql", Message: "query built from input",
+			CodeSnippet: "db.Query(\"SELECT * FROM users WHERE id=\" + id)"},
+		{File: "b.go", Line: 10, Severity: "critical", Category: "sql", Message: "query built from input",
+			CodeSnippet: "db.Query(\"DELETE FROM t WHERE k=\" + k)"},
+	}
+	head := []parser.UBSFinding{
+		// Fixed one occurrence, introduced another nearby
+		{File: "a.go", Line: 30, Severity: "critical", Category: "sql", Message: "query built from input",
+			CodeSnippet: "db.Exec(\"UPDATE accounts SET owner=\" + owner)"},
+		// Same code shifted down by inserted lines (same fingerprint)
+		{File: "b.go", Line: 14, Severity: "critical", Category: "sql", Message: "query built from input",
+			CodeSnippet: "db.Query(\"DELETE FROM t WHERE k=\" + k)"},
+	}
+
+	c := NewComparison("base.json", "head.json", sync.DiffScans(base, head, nil, sync.DefaultMatchThreshold))
+	if len(c.New) != 0 || len(c.Moved) != 1 || len(c.Changed) != 1 || !c.Moved[0].Displaced {
+		t.Fatalf("Comparison = %+v", c)
+	}
+	if got := c.CountNew("critical"); got != 1 {
+		t.Errorf("CountNew = %d, want 1 (the swapped-in finding)", got)
+	}
+}
@@ -0,0 +1,173 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Formats lists the output formats accepted by Write
+var Formats = []string{"table", "json", "markdown"}
+
+// Write renders the status in the named format
+func (s *Status) Write(w io.Writer, format string) error {
+	switch format {
+	case "table", "":
+		return s.WriteTable(w)
+	case "json":
+		return s.WriteJSON(w)
+	case "markdown", "md":
+		return s.WriteMarkdown(w)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(Formats, ", "))
+	}
+}
+
+// WriteJSON renders the status as indented JSON
+func (s *Status) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(s)
+}
+
+// WriteTable renders the status as aligned plain-text tables
+func (s *Status) WriteTable(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintf(tw, "Database: %s\n", s.Database)
+	if s.Project != "" {
+		fmt.Fprintf(tw, "Project: %s\n", s.Project)
+	}
+	fmt.Fprintf(tw, "Findings: %d open, %d resolved", s.Open, s.Resolved)
+	if s.Suppressed > 0 {
+		fmt.Fprintf(tw, ", %d suppressed", s.Suppressed)
+	}
+	fmt.Fprintln(tw)
+	fmt.Fprintf(tw, "Last sync: %s\n", s.lastSyncSummary())
+
+	if len(s.ByProject) > 0 {
+		writeBreakdownTable(tw, "Project", s.ByProject)
+	}
+	writeBreakdownTable(tw, "Severity", s.BySeverity)
+	writeBreakdownTable(tw, "Category", s.ByCategory)
+	writeBreakdownTable(tw, "Directory", s.ByDirectory)
+	writeBucketTable(tw, "Age", "Open", s.ByAge)
+	writeBucketTable(tw, "File", "Open", s.TopFiles)
+
+	fmt.Fprintf(tw, "\nPending operations: %d\n", len(s.PendingOperations))
+	if len(s.PendingOperations) > 0 {
+		fmt.Fprintln(tw, "ID\tOperation\tIssue\tAge")
+		for _, op := range s.PendingOperations {
+			fmt.Fprintf(tw, "%d\t%s\t%s\t%s\n", op.ID, op.Operation, orDash(op.IssueID), formatAge(s.GeneratedAt.Sub(op.CreatedAt)))
+		}
+	}
+
+	return tw.Flush()
+}
+
+// WriteMarkdown renders the status as Markdown, e.g. for a PR comment
+func (s *Status) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## strung status\n\n")
+	if s.Project != "" {
+		fmt.Fprintf(&b, "Project: `%s`\n\n", s.Project)
+	}
+	fmt.Fprintf(&b, "**%d open**, %d resolved", s.Open, s.Resolved)
+	if s.Suppressed > 0 {
+		fmt.Fprintf(&b, ", %d suppressed", s.Suppressed)
+	}
+	fmt.Fprintf(&b, " findings. Last sync: %s.\n", s.lastSyncSummary())
+
+	if len(s.ByProject) > 0 {
+		writeBreakdownMarkdown(&b, "Project", s.ByProject)
+	}
+	writeBreakdownMarkdown(&b, "Severity", s.BySeverity)
+	writeBreakdownMarkdown(&b, "Category", s.ByCategory)
+	writeBreakdownMarkdown(&b, "Directory", s.ByDirectory)
+	writeBucketMarkdown(&b, "Age", s.ByAge)
+	writeBucketMarkdown(&b, "File", s.TopFiles)
+
+	if len(s.PendingOperations) > 0 {
+		fmt.Fprintf(&b, "\n### Pending operations\n\n| ID | Operation | Issue | Age |\n|---:|---|---|---|\n")
+		for _, op := range s.PendingOperations {
+			fmt.Fprintf(&b, "| %d | %s | %s | %s |\n", op.ID, op.Operation, markdownCell(orDash(op.IssueID)), formatAge(s.GeneratedAt.Sub(op.CreatedAt)))
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// lastSyncSummary describes the latest scan in one line
+func (s *Status) lastSyncSummary() string {
+	if s.LastSync == nil {
+		return "never"
+	}
+	ls := s.LastSync
+	at := ls.StartedAt
+	if ls.FinishedAt != nil {
+		at = *ls.FinishedAt
+	}
+	return fmt.Sprintf("%s (%s ago), %s: %d new, %d changed, %d resolved",
+		at.UTC().Format(time.RFC3339), formatAge(s.GeneratedAt.Sub(at)), ls.Result, ls.New, ls.Changed, ls.Resolved)
+}
+
+func writeBreakdownTable(w io.Writer, title string, rows []Breakdown) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\tOpen\tResolved\n", title)
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", r.Key, r.Open, r.Resolved)
+	}
+}
+
+func writeBucketTable(w io.Writer, title, column string, rows []Bucket) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s\t%s\n", title, column)
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%d\n", r.Key, r.Count)
+	}
+}
+
+func writeBreakdownMarkdown(b *strings.Builder, title string, rows []Breakdown) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n| %s | Open | Resolved |\n|---|---:|---:|\n", title)
+	for _, r := range rows {
+		fmt.Fprintf(b, "| %s | %d | %d |\n", markdownCell(r.Key), r.Open, r.Resolved)
+	}
+}
+
+func writeBucketMarkdown(b *strings.Builder, title string, rows []Bucket) {
+	if len(rows) == 0 {
+		return
+	}
+	fmt.Fprintf(b, "\n| %s | Open |\n|---|---:|\n", title)
+	for _, r := range rows {
+		fmt.Fprintf(b, "| %s | %d |\n", markdownCell(r.Key), r.Count)
+	}
+}
+
+// markdownCell keeps scanner-controlled text from breaking a table row
+func markdownCell(s string) string {
+	s = strings.NewReplacer("|", `\|`, "\n", " ", "\r", "").Replace(s)
+	if strings.ContainsAny(s, "`*_[]") {
+		return "`" + strings.ReplaceAll(s, "`", "'") + "`"
+	}
+	return strings.NewReplacer("<", "&lt;", ">", "&gt;").Replace(s)
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
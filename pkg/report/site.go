@@ -0,0 +1,279 @@
+package report
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/source"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+// Finding states shown in the HTML report. With a current scan, findings
+// are classified against the tracking database; without one they are
+// StateOpen, StateSuppressed or StateResolved.
+const (
+	StateNew        = "new"
+	StateChanged    = "changed"
+	StateMoved      = "moved"
+	StateUnchanged  = "unchanged"
+	StateResolved   = "resolved"
+	StateOpen       = "open"
+	StateSuppressed = "suppressed" // Issue closed in Beads while the finding persists
+)
+
+// SiteFinding is one finding in the HTML report
+type SiteFinding struct {
+	Fingerprint string
+	IssueID     string
+	IssueURL    string // Link to the issue, when an issue URL template is set
+	State       string
+	File        string
+	Line        int
+	Column      int
+	Severity    string
+	Category    string
+	Message     string
+	Suggestion  string
+	Snippet     string
+	Excerpt     *source.Excerpt // Source lines around the finding, when readable
+	Permalink   string          // Forge link to the location
+	Changes     []string        // Tracked field changes since the previous scan
+	MovedFrom   string          // Previous "file:line" of a moved finding
+	FirstSeen   time.Time       // Zero for new findings
+}
+
+// ShortFingerprint returns the first 12 characters of the fingerprint
+func (f SiteFinding) ShortFingerprint() string {
+	if len(f.Fingerprint) > 12 {
+		return f.Fingerprint[:12]
+	}
+	return f.Fingerprint
+}
+
+// Location returns "file:line"
+func (f SiteFinding) Location() string {
+	return fmt.Sprintf("%s:%d", f.File, f.Line)
+}
+
+// SiteFile groups the findings of one file
+type SiteFile struct {
+	Path     string
+	Page     string // Page name relative to the site root
+	Findings []SiteFinding
+	Open     int // Findings not resolved
+}
+
+// Site is the content of the static HTML report
+type Site struct {
+	Title       string
+	GeneratedAt time.Time
+	Commit      string
+	HasScan     bool // Findings were classified against a current scan
+	Status      *Status
+	Findings    []SiteFinding
+	Files       []SiteFile
+	States      map[string]int // Findings per state
+}
+
+// SiteOptions supplies the current scan and link rendering for BuildSite
+type SiteOptions struct {
+	Title  string
+	Commit string
+
+	// Diff classifies the current scan against the tracking database; nil
+	// renders the database state only
+	Diff *sync.DiffResult
+
+	// Permalink returns the forge URL of a location, or ""
+	Permalink func(parser.UBSFinding) string
+
+	// IssueURL is a template for issue links; "{id}" is replaced with the
+	// issue ID. Empty renders plain IDs.
+	IssueURL string
+
+	Source       *source.Reader // Reads source excerpts; nil uses snippets only
+	ContextLines int
+}
+
+// BuildSite collects the report content from the database and the diff of
+// the current scan
+func BuildSite(database *db.TrackingDB, now time.Time, opts SiteOptions) (*Site, error) {
+	status, err := BuildStatus(database, now, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	site := &Site{
+		Title:       opts.Title,
+		GeneratedAt: now,
+		Commit:      opts.Commit,
+		HasScan:     opts.Diff != nil,
+		Status:      status,
+		States:      make(map[string]int),
+	}
+	if site.Title == "" {
+		site.Title = "strung report"
+	}
+
+	if opts.Diff != nil {
+		d := opts.Diff
+		for _, f := range d.New {
+			site.add(opts, StateNew, f, nil, nil, "")
+		}
+		for _, c := range d.Changed {
+			site.add(opts, StateChanged, c.Current, c.Previous, c.Fields, "")
+		}
+		for _, m := range d.Moved {
+			site.add(opts, StateMoved, m.Current, m.Previous, m.Fields, fmt.Sprintf("%s:%d", m.Previous.File, m.Previous.Line))
+		}
+		for _, u := range d.Unchanged {
+			site.add(opts, StateUnchanged, u.Current, u.Previous, nil, "")
+		}
+		for _, r := range d.Resolved {
+			site.add(opts, StateResolved, storedFinding(r), r, nil, "")
+		}
+	} else {
+		findings, err := database.GetAll()
+		if err != nil {
+			return nil, err
+		}
+		for _, f := range findings {
+			state := StateOpen
+			if f.ResolvedAt != nil {
+				state = StateResolved
+			} else if f.Suppressed() {
+				state = StateSuppressed
+			}
+			site.add(opts, state, storedFinding(f), f, nil, "")
+		}
+	}
+
+	sort.SliceStable(site.Findings, func(i, j int) bool {
+		a, b := site.Findings[i], site.Findings[j]
+		if ra, rb := severityRank(a.Severity), severityRank(b.Severity); ra != rb {
+			return ra < rb
+		}
+		if a.File != b.File {
+			return a.File < b.File
+		}
+		return a.Line < b.Line
+	})
+	site.Files = groupFiles(site.Findings)
+
+	return site, nil
+}
+
+// add appends a finding; previous is the tracked row, if any
+func (s *Site) add(opts SiteOptions, state string, f parser.UBSFinding, previous *db.Finding, fields []sync.FieldChange, movedFrom string) {
+	sf := SiteFinding{
+		Fingerprint: sync.Fingerprint(f),
+		State:       state,
+		File:        f.File,
+		Line:        f.Line,
+		Column:      f.Column,
+		Severity:    f.Severity,
+		Category:    f.Category,
+		Message:     f.Message,
+		Suggestion:  f.Suggestion,
+		Snippet:     f.CodeSnippet,
+		MovedFrom:   movedFrom,
+	}
+	if previous != nil {
+		sf.Fingerprint = previous.Fingerprint
+		sf.IssueID = previous.IssueID
+		sf.FirstSeen = previous.FirstSeen
+	}
+	if sf.IssueID != "" && opts.IssueURL != "" {
+		sf.IssueURL = strings.ReplaceAll(opts.IssueURL, "{id}", sf.IssueID)
+	}
+	for _, fc := range fields {
+		sf.Changes = append(sf.Changes, fc.String())
+	}
+	if opts.Permalink != nil && state != StateResolved {
+		sf.Permalink = opts.Permalink(f)
+	}
+	if opts.Source != nil && state != StateResolved {
+		if ex, err := opts.Source.Excerpt(f.File, f.Line, f.Column, opts.ContextLines); err == nil &&
+			(f.CodeSnippet == "" || strings.TrimSpace(f.CodeSnippet) == strings.TrimSpace(ex.TargetLine())) {
+			sf.Excerpt = ex
+		}
+	}
+
+	s.Findings = append(s.Findings, sf)
+	s.States[state]++
+}
+
+// storedFinding converts a tracked row back into scanner form
+func storedFinding(f *db.Finding) parser.UBSFinding {
+	return parser.UBSFinding{
+		File:        f.File,
+		Line:        f.Line,
+		Column:      f.Column,
+		Severity:    f.Severity,
+		Category:    f.Category,
+		Message:     f.Message,
+		Suggestion:  f.Suggestion,
+		CodeSnippet: f.CodeSnippet,
+		Tool:        f.Tool,
+		RuleID:      f.RuleID,
+	}
+}
+
+func groupFiles(findings []SiteFinding) []SiteFile {
+	byPath := make(map[string]*SiteFile)
+	var order []string
+	for _, f := range findings {
+		sf, ok := byPath[f.File]
+		if !ok {
+			sf = &SiteFile{Path: f.File, Page: filePage(f.File)}
+			byPath[f.File] = sf
+			order = append(order, f.File)
+		}
+		sf.Findings = append(sf.Findings, f)
+		if f.State != StateResolved {
+			sf.Open++
+		}
+	}
+
+	sort.Strings(order)
+	files := make([]SiteFile, 0, len(order))
+	for _, p := range order {
+		sf := byPath[p]
+		sort.SliceStable(sf.Findings, func(i, j int) bool { return sf.Findings[i].Line < sf.Findings[j].Line })
+		files = append(files, *sf)
+	}
+	return files
+}
+
+// filePage names the page of a source file: a readable slug plus a short
+// hash so distinct paths never collide
+func filePage(file string) string {
+	var b strings.Builder
+	for _, r := range strings.TrimPrefix(path.Clean(strings.ReplaceAll(file, "\\", "/")), "/") {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		default:
+			b.WriteByte('_')
+		}
+	}
+	slug := b.String()
+	if len(slug) > 80 {
+		slug = slug[len(slug)-80:]
+	}
+	sum := sha256.Sum256([]byte(file))
+	return "files/" + slug + "-" + fmt.Sprintf("%x", sum[:4]) + ".html"
+}
+
+func severityRank(severity string) int {
+	if r, ok := severityOrder[severity]; ok {
+		return r
+	}
+	return len(severityOrder)
+}
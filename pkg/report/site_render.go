@@ -0,0 +1,257 @@
+package report
+
+import (
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/source"
+)
+
+// WriteSite renders the report into dir as index.html, diff.html (with a
+// current scan) and one page per file under files/. Pages are
+// self-contained: styles and the table filter script are inlined.
+func (s *Site) WriteSite(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, "files"), 0o755); err != nil {
+		return fmt.Errorf("create report directory: %w", err)
+	}
+
+	pages := []sitePageFile{{"index.html", "index", sitePage{Site: s}}}
+	if s.HasScan {
+		pages = append(pages, sitePageFile{"diff.html", "diff", sitePage{Site: s}})
+	}
+	for i := range s.Files {
+		pages = append(pages, sitePageFile{s.Files[i].Page, "file", sitePage{Site: s, Root: "../", File: &s.Files[i]}})
+	}
+
+	for _, p := range pages {
+		if err := writeTemplate(filepath.Join(dir, filepath.FromSlash(p.name)), p.tmpl, p.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// sitePageFile pairs an output page with its template and data
+type sitePageFile struct {
+	name string
+	tmpl string
+	data sitePage
+}
+
+// sitePage is the data passed to each page template
+type sitePage struct {
+	*Site
+	Root string    // Relative path back to the site root
+	File *SiteFile // Set on file pages
+}
+
+// ByState returns the findings in one state
+func (p sitePage) ByState(state string) []SiteFinding {
+	var out []SiteFinding
+	for _, f := range p.Findings {
+		if f.State == state {
+			out = append(out, f)
+		}
+	}
+	return out
+}
+
+// PageFor returns the page of a file, relative to the site root
+func (p sitePage) PageFor(file string) string {
+	for _, f := range p.Files {
+		if f.Path == file {
+			return f.Page
+		}
+	}
+	return ""
+}
+
+func writeTemplate(path, name string, data any) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create %s: %w", path, err)
+	}
+	if err := siteTemplates.ExecuteTemplate(f, name, data); err != nil {
+		f.Close()
+		return fmt.Errorf("render %s: %w", path, err)
+	}
+	return f.Close()
+}
+
+// excerptLine is one numbered source line
+type excerptLine struct {
+	Number int
+	Text   string
+	Target bool
+	Caret  string // Spaces up to the flagged column, then "^"
+}
+
+func excerptLines(ex *source.Excerpt) []excerptLine {
+	lines := make([]excerptLine, 0, len(ex.Lines))
+	for i, text := range ex.Lines {
+		l := excerptLine{Number: ex.Start + i, Text: text, Target: ex.Start+i == ex.Target}
+		if l.Target && ex.Column > 0 {
+			l.Caret = strings.Repeat(" ", ex.Column-1) + "^"
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+var siteTemplates = template.Must(template.New("site").Funcs(template.FuncMap{
+	"excerptLines": excerptLines,
+	"lower":        strings.ToLower,
+	"join":         strings.Join,
+	"list":         func(items ...string) []string { return items },
+}).Parse(siteTemplateText))
+
+const siteTemplateText = `
+{{define "head"}}<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}}</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 1100px; padding: 0 1em; color: #1f2328; }
+a { color: #0969da; text-decoration: none; }
+a:hover { text-decoration: underline; }
+nav { margin-bottom: 1em; }
+nav a { margin-right: 1em; }
+table { border-collapse: collapse; width: 100%; margin: 1em 0; }
+th, td { border-bottom: 1px solid #d0d7de; padding: 4px 8px; text-align: left; vertical-align: top; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+code, pre { font-family: ui-monospace, monospace; font-size: 12px; }
+pre { background: #f6f8fa; padding: 8px; overflow-x: auto; }
+.cards { display: flex; gap: 1em; flex-wrap: wrap; }
+.card { border: 1px solid #d0d7de; border-radius: 6px; padding: 8px 16px; }
+.card b { display: block; font-size: 1.6em; }
+.sev, .state { display: inline-block; border-radius: 10px; padding: 0 8px; font-size: 12px; color: #fff; background: #8c959f; }
+.sev-critical { background: #cf222e; } .sev-warning { background: #9a6700; } .sev-info { background: #0969da; }
+.state-new { background: #cf222e; } .state-changed, .state-moved { background: #9a6700; }
+.state-resolved { background: #1a7f37; } .state-unchanged, .state-open { background: #57606a; }
+.state-suppressed { background: #8c959f; }
+.finding { border: 1px solid #d0d7de; border-radius: 6px; padding: 8px 16px; margin: 1em 0; }
+.target { background: #fff8c5; }
+.filters { display: flex; gap: 1em; flex-wrap: wrap; align-items: center; }
+.muted { color: #57606a; }
+</style>
+</head>
+<body>
+{{end}}
+
+{{define "nav"}}<nav><a href="{{.Root}}index.html">Summary</a>{{if .HasScan}}<a href="{{.Root}}diff.html">Changes since last scan</a>{{end}}</nav>
+{{end}}
+
+{{define "foot"}}<p class="muted">Generated {{.GeneratedAt.Format "2006-01-02 15:04:05 MST"}}{{if .Commit}} at commit <code>{{.Commit}}</code>{{end}} by strung.</p>
+</body>
+</html>
+{{end}}
+
+{{define "issue"}}{{if .IssueURL}}<a href="{{.IssueURL}}">{{.IssueID}}</a>{{else if .IssueID}}{{.IssueID}}{{else}}<span class="muted">-</span>{{end}}{{end}}
+
+{{define "index"}}{{template "head" .Title}}{{template "nav" .}}
+<h1>{{.Title}}</h1>
+<div class="cards">
+<div class="card"><b>{{.Status.Open}}</b>open</div>
+<div class="card"><b>{{.Status.Resolved}}</b>resolved</div>
+{{if .HasScan}}{{range $state := list "new" "changed" "moved" "resolved"}}<div class="card"><b>{{index $.States $state}}</b>{{$state}} this scan</div>
+{{end}}{{end}}</div>
+
+<h2>Findings by severity</h2>
+<table>
+<tr><th>Severity</th><th>Open</th><th>Resolved</th></tr>
+{{range .Status.BySeverity}}<tr><td><span class="sev sev-{{.Key}}">{{.Key}}</span></td><td class="n">{{.Open}}</td><td class="n">{{.Resolved}}</td></tr>
+{{end}}</table>
+
+<h2>Findings</h2>
+<div class="filters">
+<label>Severity <select id="f-severity"><option value="">all</option>{{range .Status.BySeverity}}<option>{{.Key}}</option>{{end}}</select></label>
+<label>State <select id="f-state"><option value="">all</option>{{range $state, $n := .States}}<option>{{$state}}</option>{{end}}</select></label>
+<label>Search <input id="f-text" type="search" placeholder="file, category, message, issue"></label>
+<span id="f-count" class="muted"></span>
+</div>
+<table id="findings">
+<tr><th>Severity</th><th>State</th><th>Location</th><th>Category</th><th>Message</th><th>Issue</th></tr>
+{{range .Findings}}<tr data-severity="{{.Severity}}" data-state="{{.State}}" data-text="{{lower .File}} {{lower .Category}} {{lower .Message}} {{lower .IssueID}}">
+<td><span class="sev sev-{{.Severity}}">{{.Severity}}</span></td>
+<td><span class="state state-{{.State}}">{{.State}}</span></td>
+<td><a href="{{$.PageFor .File}}#f-{{.ShortFingerprint}}">{{.Location}}</a></td>
+<td>{{.Category}}</td>
+<td>{{.Message}}</td>
+<td>{{template "issue" .}}</td>
+</tr>
+{{end}}</table>
+
+<h2>Files</h2>
+<table>
+<tr><th>File</th><th>Open</th><th>Total</th></tr>
+{{range .Files}}<tr><td><a href="{{.Page}}">{{.Path}}</a></td><td class="n">{{.Open}}</td><td class="n">{{len .Findings}}</td></tr>
+{{end}}</table>
+
+<script>
+(function () {
+  var sev = document.getElementById("f-severity"), state = document.getElementById("f-state"),
+      text = document.getElementById("f-text"), count = document.getElementById("f-count"),
+      rows = document.querySelectorAll("#findings tr[data-severity]");
+  function apply() {
+    var q = text.value.toLowerCase(), shown = 0;
+    rows.forEach(function (r) {
+      var ok = (!sev.value || r.dataset.severity === sev.value) &&
+               (!state.value || r.dataset.state === state.value) &&
+               (!q || r.dataset.text.indexOf(q) >= 0);
+      r.style.display = ok ? "" : "none";
+      if (ok) shown++;
+    });
+    count.textContent = shown + " of " + rows.length + " findings";
+  }
+  [sev, state, text].forEach(function (el) { el.addEventListener("input", apply); });
+  apply();
+})();
+</script>
+{{template "foot" .}}{{end}}
+
+{{define "finding"}}<div class="finding" id="f-{{.ShortFingerprint}}">
+<p><span class="sev sev-{{.Severity}}">{{.Severity}}</span> <span class="state state-{{.State}}">{{.State}}</span>
+<b>{{.Category}}</b> at {{if .Permalink}}<a href="{{.Permalink}}">{{.Location}}</a>{{else}}<code>{{.Location}}</code>{{end}}
+&middot; issue {{template "issue" .}} &middot; <code class="muted">{{.ShortFingerprint}}</code></p>
+<p>{{.Message}}</p>
+{{if .MovedFrom}}<p class="muted">Moved from <code>{{.MovedFrom}}</code></p>{{end}}
+{{if .Changes}}<p class="muted">Changed: {{join .Changes "; "}}</p>{{end}}
+{{if .Excerpt}}<pre>{{range excerptLines .Excerpt}}{{if .Target}}<span class="target">{{printf "%5d" .Number}} &gt; {{.Text}}</span>{{if .Caret}}
+        {{.Caret}}{{end}}{{else}}{{printf "%5d" .Number}}   {{.Text}}{{end}}
+{{end}}</pre>
+{{else if .Snippet}}<pre>{{.Snippet}}</pre>
+{{end}}{{if .Suggestion}}<p><b>Suggestion:</b> {{.Suggestion}}</p>{{end}}
+{{if not .FirstSeen.IsZero}}<p class="muted">First seen {{.FirstSeen.Format "2006-01-02"}}</p>{{end}}
+</div>
+{{end}}
+
+{{define "file"}}{{template "head" .File.Path}}{{template "nav" .}}
+<h1><code>{{.File.Path}}</code></h1>
+<p>{{.File.Open}} open of {{len .File.Findings}} findings.</p>
+{{range .File.Findings}}{{template "finding" .}}{{end}}
+{{template "foot" .}}{{end}}
+
+{{define "diff"}}{{template "head" "Changes since last scan"}}{{template "nav" .}}
+<h1>Changes since last scan</h1>
+{{range $state := list "new" "changed" "moved" "resolved"}}{{$findings := $.ByState $state}}
+<h2>{{$state}} ({{len $findings}})</h2>
+{{if $findings}}<table>
+<tr><th>Severity</th><th>Location</th><th>Category</th><th>Message</th><th>Details</th><th>Issue</th></tr>
+{{range $findings}}<tr>
+<td><span class="sev sev-{{.Severity}}">{{.Severity}}</span></td>
+<td><a href="{{$.PageFor .File}}#f-{{.ShortFingerprint}}">{{.Location}}</a></td>
+<td>{{.Category}}</td>
+<td>{{.Message}}</td>
+<td>{{if .MovedFrom}}from <code>{{.MovedFrom}}</code>{{if .Changes}}; {{end}}{{end}}{{join .Changes "; "}}</td>
+<td>{{template "issue" .}}</td>
+</tr>
+{{end}}</table>
+{{else}}<p class="muted">None.</p>
+{{end}}{{end}}
+{{template "foot" .}}{{end}}
+`
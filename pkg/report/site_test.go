@@ -0,0 +1,158 @@
+package report
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/source"
+	"github.com/TheEditor/strung/pkg/sync"
+)
+
+func TestBuildSite_Diff(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+
+	tracked := []*db.Finding{
+		{Fingerprint: "old-1", IssueID: "bd-1", File: "src/a.go", Line: 3, Severity: "warning", Category: "style", Message: "fixed", FirstSeen: now, LastSeen: now},
+		{Fingerprint: "old-2", IssueID: "bd-2", File: "src/a.go", Line: 9, Severity: "warning", Category: "null", Message: "kept", FirstSeen: now, LastSeen: now},
+	}
+	for _, f := range tracked {
+		if err := database.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+
+	diff := &sync.DiffResult{
+		New: []parser.UBSFinding{{File: "src/b.go", Line: 1, Severity: "critical", Category: "xss", Message: "<script>alert(1)</script>", CodeSnippet: "x := y"}},
+		Changed: []sync.ChangeRecord{{
+			Previous: tracked[1],
+			Current:  parser.UBSFinding{File: "src/a.go", Line: 9, Severity: "critical", Category: "null", Message: "kept"},
+			Fields:   []sync.FieldChange{{Field: "severity", Previous: "warning", Current: "critical"}},
+		}},
+		Resolved: []*db.Finding{tracked[0]},
+	}
+
+	site, err := BuildSite(database, now, SiteOptions{
+		Diff:      diff,
+		IssueURL:  "https://tracker.example.com/issues/{id}",
+		Permalink: func(f parser.UBSFinding) string { return "https://example.com/" + f.File },
+	})
+	if err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+
+	if !site.HasScan || site.States[StateNew] != 1 || site.States[StateChanged] != 1 || site.States[StateResolved] != 1 {
+		t.Errorf("States = %v", site.States)
+	}
+	if len(site.Findings) != 3 || site.Findings[0].Severity != "critical" {
+		t.Fatalf("Findings = %+v", site.Findings)
+	}
+	if len(site.Files) != 2 || site.Files[0].Path != "src/a.go" || site.Files[0].Open != 1 {
+		t.Errorf("Files = %+v", site.Files)
+	}
+
+	var changed SiteFinding
+	for _, f := range site.Findings {
+		if f.State == StateChanged {
+			changed = f
+		}
+	}
+	if changed.IssueURL != "https://tracker.example.com/issues/bd-2" || changed.Permalink != "https://example.com/src/a.go" {
+		t.Errorf("changed links = %q, %q", changed.IssueURL, changed.Permalink)
+	}
+	if len(changed.Changes) != 1 || !strings.Contains(changed.Changes[0], "severity") {
+		t.Errorf("Changes = %v", changed.Changes)
+	}
+
+	dir := t.TempDir()
+	if err := site.WriteSite(dir); err != nil {
+		t.Fatalf("WriteSite failed: %v", err)
+	}
+
+	index := readFile(t, filepath.Join(dir, "index.html"))
+	if strings.Contains(index, "<script>alert(1)") {
+		t.Error("scanner text must be escaped")
+	}
+	for _, want := range []string{`href="https://tracker.example.com/issues/bd-2"`, `href="` + site.Files[1].Page + `#f-`, `id="f-state"`} {
+		if !strings.Contains(index, want) {
+			t.Errorf("index.html missing %q", want)
+		}
+	}
+
+	diffPage := readFile(t, filepath.Join(dir, "diff.html"))
+	for _, want := range []string{"new (1)", "changed (1)", "resolved (1)", "severity warning → critical"} {
+		if !strings.Contains(diffPage, want) {
+			t.Errorf("diff.html missing %q", want)
+		}
+	}
+
+	filePage := readFile(t, filepath.Join(dir, filepath.FromSlash(site.Files[1].Page)))
+	for _, want := range []string{"x := y", `href="../index.html"`, `href="https://example.com/src/b.go"`} {
+		if !strings.Contains(filePage, want) {
+			t.Errorf("file page missing %q", want)
+		}
+	}
+}
+
+func TestBuildSite_TrackedOnly(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	seedStatus(t, database, now)
+
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "src"), 0o755)
+	os.WriteFile(filepath.Join(root, "src", "a.go"), []byte("package a\nfunc f() {}\n"), 0o644)
+
+	site, err := BuildSite(database, now, SiteOptions{Source: source.NewReader(root), ContextLines: 1})
+	if err != nil {
+		t.Fatalf("BuildSite failed: %v", err)
+	}
+	if site.HasScan || site.States[StateOpen] != 3 || site.States[StateResolved] != 1 {
+		t.Errorf("States = %v", site.States)
+	}
+
+	var excerpt *source.Excerpt
+	for _, f := range site.Findings {
+		if f.File == "src/a.go" && f.Line == 1 {
+			excerpt = f.Excerpt
+		}
+	}
+	if excerpt == nil || excerpt.Target != 1 {
+		t.Fatalf("expected an excerpt for src/a.go:1, got %+v", excerpt)
+	}
+
+	dir := t.TempDir()
+	if err := site.WriteSite(dir); err != nil {
+		t.Fatalf("WriteSite failed: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "diff.html")); !os.IsNotExist(err) {
+		t.Error("diff.html should only be written with a current scan")
+	}
+}
+
+func TestFilePage(t *testing.T) {
+	a, b := filePage("src/a b.go"), filePage("src/a_b.go")
+	if a == b {
+		t.Errorf("distinct paths share page %s", a)
+	}
+	if !strings.HasPrefix(a, "files/src_a_b.go-") || !strings.HasSuffix(a, ".html") {
+		t.Errorf("filePage = %s", a)
+	}
+	if p := filePage("../../etc/passwd"); strings.Contains(strings.TrimPrefix(p, "files/"), "/") {
+		t.Errorf("filePage escapes the site: %s", p)
+	}
+}
+
+func readFile(t *testing.T, path string) string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read %s: %v", path, err)
+	}
+	return string(data)
+}
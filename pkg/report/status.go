@@ -0,0 +1,266 @@
+// Package report summarizes tracking database state for humans and tools.
+package report
+
+import (
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+// Breakdown counts open and resolved findings for one group
+type Breakdown struct {
+	Key      string `json:"key"`
+	Open     int    `json:"open"`
+	Resolved int    `json:"resolved"`
+}
+
+// Bucket counts open findings for one group
+type Bucket struct {
+	Key   string `json:"key"`
+	Count int    `json:"count"`
+}
+
+// SyncInfo describes the most recent sync run
+type SyncInfo struct {
+	ID         string     `json:"id"`
+	StartedAt  time.Time  `json:"started_at"`
+	FinishedAt *time.Time `json:"finished_at,omitempty"`
+	Result     string     `json:"result"`
+	New        int        `json:"new"`
+	Changed    int        `json:"changed"`
+	Resolved   int        `json:"resolved"`
+}
+
+// Operation is an issue tracker operation that never completed
+type Operation struct {
+	ID          int64     `json:"id"`
+	Operation   string    `json:"operation"`
+	Fingerprint string    `json:"fingerprint"`
+	IssueID     string    `json:"issue_id,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// Status is a snapshot of the tracking database
+type Status struct {
+	Database    string    `json:"database"`
+	Project     string    `json:"project,omitempty"` // Project view; "" for all projects
+	GeneratedAt time.Time `json:"generated_at"`
+	Open        int       `json:"open"`
+	Resolved    int       `json:"resolved"`
+	Suppressed  int       `json:"suppressed"` // Unresolved, issue closed in Beads; not in Open
+
+	ByProject   []Breakdown `json:"by_project,omitempty"` // Only with several projects
+	BySeverity  []Breakdown `json:"by_severity"`
+	ByCategory  []Breakdown `json:"by_category"`
+	ByDirectory []Breakdown `json:"by_directory"`
+	ByAge       []Bucket    `json:"by_age"` // Open findings by time since first seen
+	TopFiles    []Bucket    `json:"top_files"`
+
+	LastSync          *SyncInfo   `json:"last_sync,omitempty"`
+	PendingOperations []Operation `json:"pending_operations"`
+}
+
+// ageBuckets are upper bounds for open finding age, in order
+var ageBuckets = []struct {
+	key string
+	max time.Duration
+}{
+	{"<1d", 24 * time.Hour},
+	{"1-7d", 7 * 24 * time.Hour},
+	{"7-30d", 30 * 24 * time.Hour},
+	{"30-90d", 90 * 24 * time.Hour},
+	{">90d", 0},
+}
+
+// severityOrder sorts known severities first
+var severityOrder = map[string]int{"critical": 0, "warning": 1, "info": 2}
+
+// BuildStatus summarizes the database as of now. top limits the directory
+// and file lists (0 = unlimited).
+func BuildStatus(database *db.TrackingDB, now time.Time, top int) (*Status, error) {
+	findings, err := database.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	st := &Status{
+		Database:          database.Path(),
+		Project:           database.Project(),
+		GeneratedAt:       now,
+		PendingOperations: []Operation{},
+	}
+
+	project := newGrouping()
+	severity := newGrouping()
+	category := newGrouping()
+	directory := newGrouping()
+	files := make(map[string]int)
+	ages := make([]int, len(ageBuckets))
+
+	for _, f := range findings {
+		open := f.ResolvedAt == nil
+		if open && f.Suppressed() {
+			st.Suppressed++
+			continue
+		}
+		dir := path.Dir(strings.ReplaceAll(f.File, "\\", "/"))
+
+		projectKey := f.Project
+		if projectKey == "" {
+			projectKey = "(default)"
+		}
+		project.add(projectKey, open)
+		severity.add(f.Severity, open)
+		category.add(f.Category, open)
+		directory.add(dir, open)
+
+		if !open {
+			st.Resolved++
+			continue
+		}
+		st.Open++
+		files[f.File]++
+		ages[ageBucket(now.Sub(f.FirstSeen))]++
+	}
+
+	st.BySeverity = severity.sorted(func(a, b Breakdown) bool {
+		oa, okA := severityOrder[a.Key]
+		ob, okB := severityOrder[b.Key]
+		if okA != okB {
+			return okA
+		}
+		if oa != ob {
+			return oa < ob
+		}
+		return a.Key < b.Key
+	})
+	if len(project) > 1 {
+		st.ByProject = project.sorted(byOpenDesc)
+	}
+	st.ByCategory = category.sorted(byOpenDesc)
+	st.ByDirectory = limit(directory.sorted(byOpenDesc), top)
+
+	for i, b := range ageBuckets {
+		st.ByAge = append(st.ByAge, Bucket{Key: b.key, Count: ages[i]})
+	}
+
+	st.TopFiles = make([]Bucket, 0, len(files))
+	for file, n := range files {
+		st.TopFiles = append(st.TopFiles, Bucket{Key: file, Count: n})
+	}
+	sort.Slice(st.TopFiles, func(i, j int) bool {
+		if st.TopFiles[i].Count != st.TopFiles[j].Count {
+			return st.TopFiles[i].Count > st.TopFiles[j].Count
+		}
+		return st.TopFiles[i].Key < st.TopFiles[j].Key
+	})
+	st.TopFiles = limit(st.TopFiles, top)
+
+	scan, err := database.LatestScan()
+	if err != nil {
+		return nil, err
+	}
+	if scan != nil {
+		st.LastSync = &SyncInfo{
+			ID:         scan.ID,
+			StartedAt:  scan.StartedAt,
+			FinishedAt: scan.FinishedAt,
+			Result:     scan.Result,
+			New:        scan.New,
+			Changed:    scan.Changed,
+			Resolved:   scan.Resolved,
+		}
+	}
+
+	ops, err := database.GetPendingOperations()
+	if err != nil {
+		return nil, err
+	}
+	for _, op := range ops {
+		st.PendingOperations = append(st.PendingOperations, Operation{
+			ID:          op.ID,
+			Operation:   op.Operation,
+			Fingerprint: op.Fingerprint,
+			IssueID:     op.IssueID,
+			CreatedAt:   op.CreatedAt,
+		})
+	}
+
+	return st, nil
+}
+
+// ageBucket returns the index of the bucket for an age
+func ageBucket(age time.Duration) int {
+	for i, b := range ageBuckets {
+		if b.max == 0 || age < b.max {
+			return i
+		}
+	}
+	return len(ageBuckets) - 1
+}
+
+// grouping accumulates breakdowns by key
+type grouping map[string]*Breakdown
+
+func newGrouping() grouping {
+	return make(grouping)
+}
+
+func (g grouping) add(key string, open bool) {
+	if key == "" {
+		key = "(none)"
+	}
+	b, ok := g[key]
+	if !ok {
+		b = &Breakdown{Key: key}
+		g[key] = b
+	}
+	if open {
+		b.Open++
+	} else {
+		b.Resolved++
+	}
+}
+
+func (g grouping) sorted(less func(a, b Breakdown) bool) []Breakdown {
+	out := make([]Breakdown, 0, len(g))
+	for _, b := range g {
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return less(out[i], out[j]) })
+	return out
+}
+
+// byOpenDesc sorts by open count, then total, then key
+func byOpenDesc(a, b Breakdown) bool {
+	if a.Open != b.Open {
+		return a.Open > b.Open
+	}
+	if a.Resolved != b.Resolved {
+		return a.Resolved > b.Resolved
+	}
+	return a.Key < b.Key
+}
+
+func limit[T any](items []T, n int) []T {
+	if n > 0 && len(items) > n {
+		return items[:n]
+	}
+	return items
+}
+
+// formatAge renders a duration as a short human age ("3d", "5h")
+func formatAge(d time.Duration) string {
+	switch {
+	case d >= 24*time.Hour:
+		return fmt.Sprintf("%dd", int(d.Hours()/24))
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", int(d.Hours()))
+	default:
+		return fmt.Sprintf("%dm", int(d.Minutes()))
+	}
+}
@@ -0,0 +1,219 @@
+package report
+
+import (
+	"bytes"
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+func setupTestDB(t *testing.T) *db.TrackingDB {
+	t.Helper()
+	database, err := db.Open(filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func seedStatus(t *testing.T, database *db.TrackingDB, now time.Time) {
+	t.Helper()
+	day := 24 * time.Hour
+	findings := []*db.Finding{
+		{Fingerprint: "fp1", IssueID: "bd-1", File: "src/a.go", Line: 1, Severity: "critical", Category: "null-safety", Message: "m1", FirstSeen: now.Add(-2 * time.Hour)},
+		{Fingerprint: "fp2", IssueID: "bd-2", File: "src/a.go", Line: 2, Severity: "warning", Category: "null-safety", Message: "m2", FirstSeen: now.Add(-10 * day)},
+		{Fingerprint: "fp3", IssueID: "bd-3", File: "lib/b.go", Line: 3, Severity: "warning", Category: "security", Message: "m3", FirstSeen: now.Add(-200 * day)},
+		{Fingerprint: "fp4", IssueID: "bd-4", File: "lib/b.go", Line: 4, Severity: "info", Category: "security", Message: "m4", FirstSeen: now.Add(-3 * day)},
+	}
+	for _, f := range findings {
+		f.LastSeen = now
+		if err := database.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	if err := database.MarkResolved("fp4", now.Add(-day)); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+
+	if err := database.BeginScan(&db.Scan{ID: "scan-1", StartedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("BeginScan failed: %v", err)
+	}
+	if err := database.FinishScan("scan-1", db.ScanSucceeded, 3, 0, 1, now.Add(-time.Hour)); err != nil {
+		t.Fatalf("FinishScan failed: %v", err)
+	}
+	if _, err := database.LogOperation(&db.Operation{Operation: "create", Fingerprint: "fp1", Status: "pending", CreatedAt: now.Add(-time.Hour)}); err != nil {
+		t.Fatalf("LogOperation failed: %v", err)
+	}
+}
+
+func TestBuildStatus(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	seedStatus(t, database, now)
+
+	st, err := BuildStatus(database, now, 1)
+	if err != nil {
+		t.Fatalf("BuildStatus failed: %v", err)
+	}
+
+	if st.Open != 3 || st.Resolved != 1 {
+		t.Errorf("open/resolved = %d/%d, want 3/1", st.Open, st.Resolved)
+	}
+
+	wantSev := []Breakdown{{"critical", 1, 0}, {"warning", 2, 0}, {"info", 0, 1}}
+	if len(st.BySeverity) != len(wantSev) {
+		t.Fatalf("BySeverity = %+v", st.BySeverity)
+	}
+	for i, want := range wantSev {
+		if st.BySeverity[i] != want {
+			t.Errorf("BySeverity[%d] = %+v, want %+v", i, st.BySeverity[i], want)
+		}
+	}
+
+	if len(st.ByCategory) != 2 || st.ByCategory[0] != (Breakdown{"null-safety", 2, 0}) {
+		t.Errorf("ByCategory = %+v", st.ByCategory)
+	}
+
+	// top=1 limits directories and files
+	if len(st.ByDirectory) != 1 || st.ByDirectory[0].Key != "src" {
+		t.Errorf("ByDirectory = %+v", st.ByDirectory)
+	}
+	if len(st.TopFiles) != 1 || st.TopFiles[0] != (Bucket{"src/a.go", 2}) {
+		t.Errorf("TopFiles = %+v", st.TopFiles)
+	}
+
+	ages := map[string]int{}
+	for _, b := range st.ByAge {
+		ages[b.Key] = b.Count
+	}
+	if ages["<1d"] != 1 || ages["7-30d"] != 1 || ages[">90d"] != 1 || ages["1-7d"] != 0 {
+		t.Errorf("ByAge = %+v", st.ByAge)
+	}
+
+	if st.LastSync == nil || st.LastSync.ID != "scan-1" || st.LastSync.New != 3 || st.LastSync.Resolved != 1 {
+		t.Errorf("LastSync = %+v", st.LastSync)
+	}
+	if len(st.PendingOperations) != 1 || st.PendingOperations[0].Fingerprint != "fp1" {
+		t.Errorf("PendingOperations = %+v", st.PendingOperations)
+	}
+}
+
+func TestBuildStatus_Empty(t *testing.T) {
+	st, err := BuildStatus(setupTestDB(t), time.Now(), 10)
+	if err != nil {
+		t.Fatalf("BuildStatus failed: %v", err)
+	}
+	if st.Open != 0 || st.LastSync != nil || st.PendingOperations == nil {
+		t.Errorf("unexpected empty status: %+v", st)
+	}
+
+	var buf bytes.Buffer
+	if err := st.WriteTable(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Last sync: never") {
+		t.Errorf("table output missing last sync:\n%s", buf.String())
+	}
+}
+
+func TestBuildStatus_Projects(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	seedStatus(t, database, now)
+
+	st, err := BuildStatus(database, now, 10)
+	if err != nil {
+		t.Fatalf("BuildStatus failed: %v", err)
+	}
+	if st.ByProject != nil {
+		t.Errorf("single project has breakdown: %+v", st.ByProject)
+	}
+
+	database.SetProject("web")
+	if err := database.Store(&db.Finding{Fingerprint: database.Fingerprint("w.go", "x", "m", "", "", 1), File: "w.go", Line: 1,
+		Severity: "info", Category: "x", Message: "m", FirstSeen: now, LastSeen: now}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+
+	st, err = BuildStatus(database, now, 10)
+	if err != nil {
+		t.Fatalf("BuildStatus failed: %v", err)
+	}
+	if st.Project != "web" || st.Open != 1 || st.ByProject != nil {
+		t.Errorf("web status: project %q, open %d, by project %+v", st.Project, st.Open, st.ByProject)
+	}
+
+	all, err := db.Open(database.Path())
+	if err != nil {
+		t.Fatalf("Open failed: %v", err)
+	}
+	defer all.Close()
+	st, err = BuildStatus(all, now, 10)
+	if err != nil {
+		t.Fatalf("BuildStatus failed: %v", err)
+	}
+	want := []Breakdown{{"(default)", 3, 1}, {"web", 1, 0}}
+	if len(st.ByProject) != 2 || st.ByProject[0] != want[0] || st.ByProject[1] != want[1] {
+		t.Errorf("ByProject = %+v, want %+v", st.ByProject, want)
+	}
+}
+
+func TestStatusWrite(t *testing.T) {
+	database := setupTestDB(t)
+	now := time.Now().UTC().Truncate(time.Second)
+	seedStatus(t, database, now)
+
+	st, err := BuildStatus(database, now, 10)
+	if err != nil {
+		t.Fatalf("BuildStatus failed: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := st.Write(&buf, "json"); err != nil {
+		t.Fatal(err)
+	}
+	var decoded Status
+	if err := json.Unmarshal(buf.Bytes(), &decoded); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if decoded.Open != 3 || len(decoded.TopFiles) != 2 {
+		t.Errorf("decoded = %+v", decoded)
+	}
+
+	buf.Reset()
+	if err := st.Write(&buf, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+	for _, want := range []string{"**3 open**", "| Severity | Open | Resolved |", "| critical | 1 | 0 |", "### Pending operations", "success: 3 new"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	buf.Reset()
+	if err := st.Write(&buf, "table"); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), "Pending operations: 1") {
+		t.Errorf("table missing pending operations:\n%s", buf.String())
+	}
+
+	if err := st.Write(&buf, "xml"); err == nil {
+		t.Error("expected error for unknown format")
+	}
+}
+
+func TestMarkdownCell(t *testing.T) {
+	if got := markdownCell("a|b"); got != `a\|b` {
+		t.Errorf("markdownCell = %q", got)
+	}
+	if got := markdownCell("src/__init__.py"); got != "`src/__init__.py`" {
+		t.Errorf("markdownCell = %q", got)
+	}
+}
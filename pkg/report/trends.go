@@ -0,0 +1,322 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+// Trend period lengths
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// DefaultPeriods is how many periods a trend report covers by default
+const DefaultPeriods = 12
+
+// SeverityCounts splits a count by severity
+type SeverityCounts struct {
+	Critical int `json:"critical"`
+	Warning  int `json:"warning"`
+	Info     int `json:"info"`
+	Other    int `json:"other"`
+}
+
+func (c *SeverityCounts) add(severity string) {
+	switch severity {
+	case "critical":
+		c.Critical++
+	case "warning":
+		c.Warning++
+	case "info":
+		c.Info++
+	default:
+		c.Other++
+	}
+}
+
+// Total sums all severities
+func (c SeverityCounts) Total() int {
+	return c.Critical + c.Warning + c.Info + c.Other
+}
+
+// TrendPeriod is one point of the trend series
+type TrendPeriod struct {
+	Start      time.Time      `json:"start"`
+	End        time.Time      `json:"end"`
+	Open       SeverityCounts `json:"open"`       // Open at the end of the period
+	Introduced int            `json:"introduced"` // First seen during the period
+	Fixed      int            `json:"fixed"`      // Resolved during the period
+}
+
+// ResolveTime summarizes how long resolved findings of a category stayed open
+type ResolveTime struct {
+	Category    string  `json:"category"`
+	Resolved    int     `json:"resolved"`
+	MeanHours   float64 `json:"mean_hours"`
+	MedianHours float64 `json:"median_hours"`
+}
+
+// Trends is a history report built from the tracking database
+type Trends struct {
+	Database    string        `json:"database"`
+	GeneratedAt time.Time     `json:"generated_at"`
+	Period      string        `json:"period"`
+	Periods     []TrendPeriod `json:"periods"`
+	ResolveTime []ResolveTime `json:"resolve_time"` // Resolved within the report window
+	Age         []Bucket      `json:"age"`          // Open findings by time since first seen
+}
+
+// TrendOptions selects the report window
+type TrendOptions struct {
+	Period string    // PeriodDay, PeriodWeek or PeriodMonth (default week)
+	Since  time.Time // Start of the first period; zero = DefaultPeriods back
+}
+
+// BuildTrends computes trend series from the finding event history, so
+// open counts follow severity changes, suppression and regressions.
+// Findings tracked before history was recorded fall back to their
+// first-seen, suppression and resolution times.
+func BuildTrends(database *db.TrackingDB, now time.Time, opts TrendOptions) (*Trends, error) {
+	if opts.Period == "" {
+		opts.Period = PeriodWeek
+	}
+	if _, err := nextPeriod(opts.Period, now); err != nil {
+		return nil, err
+	}
+
+	now = now.UTC()
+	start := opts.Since.UTC()
+	if opts.Since.IsZero() {
+		start = periodStart(opts.Period, now)
+		for i := 1; i < DefaultPeriods; i++ {
+			start = previousPeriod(opts.Period, start)
+		}
+	} else {
+		start = periodStart(opts.Period, start)
+	}
+	if start.After(now) {
+		return nil, fmt.Errorf("report start %s is in the future", start.Format(time.RFC3339))
+	}
+
+	findings, err := database.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	events, err := database.TransitionEvents()
+	if err != nil {
+		return nil, err
+	}
+	history := make(map[string][]*db.Event)
+	for _, e := range events {
+		history[e.Fingerprint] = append(history[e.Fingerprint], e)
+	}
+
+	tr := &Trends{
+		Database:    database.Path(),
+		GeneratedAt: now,
+		Period:      opts.Period,
+		ResolveTime: []ResolveTime{},
+	}
+
+	for ps := start; !ps.After(now); {
+		pe, _ := nextPeriod(opts.Period, ps)
+		tr.Periods = append(tr.Periods, TrendPeriod{Start: ps, End: pe})
+		ps = pe
+	}
+
+	durations := make(map[string][]time.Duration)
+	ages := make([]int, len(ageBuckets))
+
+	for _, f := range findings {
+		evs := timeline(f, history[f.Fingerprint])
+
+		var st trendState
+		for _, e := range evs {
+			at := e.CreatedAt.UTC()
+			for i := range tr.Periods {
+				p := &tr.Periods[i]
+				if !inPeriod(at, p) {
+					continue
+				}
+				switch e.Event {
+				case db.EventNew:
+					p.Introduced++
+				case db.EventResolved:
+					p.Fixed++
+				}
+			}
+			if e.Event == db.EventResolved && st.open && !at.Before(start) {
+				durations[f.Category] = append(durations[f.Category], at.Sub(st.since))
+			}
+			st.apply(e)
+		}
+		if st.active() {
+			ages[ageBucket(now.Sub(f.FirstSeen.UTC()))]++
+		}
+
+		// Replay for the state at each period end (or now, for the
+		// current period)
+		st, next := trendState{}, 0
+		for i := range tr.Periods {
+			p := &tr.Periods[i]
+			at := p.End
+			if at.After(now) {
+				at = now
+			}
+			for next < len(evs) && evs[next].CreatedAt.Before(at) {
+				st.apply(evs[next])
+				next++
+			}
+			if st.active() {
+				p.Open.add(st.severity)
+			}
+		}
+	}
+
+	for i, b := range ageBuckets {
+		tr.Age = append(tr.Age, Bucket{Key: b.key, Count: ages[i]})
+	}
+
+	for category, ds := range durations {
+		if category == "" {
+			category = "(none)"
+		}
+		tr.ResolveTime = append(tr.ResolveTime, ResolveTime{
+			Category:    category,
+			Resolved:    len(ds),
+			MeanHours:   mean(ds).Hours(),
+			MedianHours: median(ds).Hours(),
+		})
+	}
+	sort.Slice(tr.ResolveTime, func(i, j int) bool {
+		a, b := tr.ResolveTime[i], tr.ResolveTime[j]
+		if a.MeanHours != b.MeanHours {
+			return a.MeanHours > b.MeanHours
+		}
+		return a.Category < b.Category
+	})
+
+	return tr, nil
+}
+
+// trendState is a finding's state while replaying its history
+type trendState struct {
+	open       bool
+	suppressed bool
+	severity   string
+	since      time.Time // Start of the current open spell
+}
+
+func (s *trendState) apply(e *db.Event) {
+	switch e.Event {
+	case db.EventNew:
+		s.open, s.suppressed, s.since = true, false, e.CreatedAt.UTC()
+	case db.EventResolved:
+		s.open = false
+	case db.EventSuppressed:
+		s.suppressed = true
+	case db.EventUnsuppressed, db.EventReopened:
+		s.suppressed = false
+	}
+	if e.Severity != "" {
+		s.severity = e.Severity
+	}
+}
+
+// active reports whether the finding counts as open; like status, findings
+// whose issue was closed in Beads do not
+func (s *trendState) active() bool {
+	return s.open && !s.suppressed
+}
+
+// timeline returns a finding's state transitions, oldest first. Without a
+// recorded "new" event, the finding's first-seen time stands in for it,
+// with the severity of its earliest event. Without any history, its
+// suppression and resolution times are used.
+func timeline(f *db.Finding, events []*db.Event) []*db.Event {
+	severity := f.Severity
+	if len(events) > 0 && events[0].Severity != "" {
+		severity = events[0].Severity
+	}
+	if len(events) > 0 && events[0].Event == db.EventNew {
+		return events
+	}
+
+	timeline := []*db.Event{{Fingerprint: f.Fingerprint, Event: db.EventNew, Severity: severity, CreatedAt: f.FirstSeen}}
+	if len(events) > 0 {
+		return append(timeline, events...)
+	}
+	if f.SuppressedAt != nil && f.ResolvedAt == nil {
+		timeline = append(timeline, &db.Event{Event: db.EventSuppressed, CreatedAt: *f.SuppressedAt})
+	}
+	if f.ResolvedAt != nil {
+		timeline = append(timeline, &db.Event{Event: db.EventResolved, CreatedAt: *f.ResolvedAt})
+	}
+	return timeline
+}
+
+func inPeriod(t time.Time, p *TrendPeriod) bool {
+	return !t.Before(p.Start) && t.Before(p.End)
+}
+
+// periodStart truncates t (UTC) to the start of its period. Weeks start on
+// Monday.
+func periodStart(period string, t time.Time) time.Time {
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch period {
+	case PeriodWeek:
+		offset := (int(day.Weekday()) + 6) % 7
+		return day.AddDate(0, 0, -offset)
+	case PeriodMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+func nextPeriod(period string, start time.Time) (time.Time, error) {
+	switch period {
+	case PeriodDay:
+		return start.AddDate(0, 0, 1), nil
+	case PeriodWeek:
+		return start.AddDate(0, 0, 7), nil
+	case PeriodMonth:
+		return start.AddDate(0, 1, 0), nil
+	default:
+		return time.Time{}, fmt.Errorf("invalid period %q (use %s, %s or %s)", period, PeriodDay, PeriodWeek, PeriodMonth)
+	}
+}
+
+func previousPeriod(period string, start time.Time) time.Time {
+	switch period {
+	case PeriodWeek:
+		return start.AddDate(0, 0, -7)
+	case PeriodMonth:
+		return start.AddDate(0, -1, 0)
+	default:
+		return start.AddDate(0, 0, -1)
+	}
+}
+
+func mean(ds []time.Duration) time.Duration {
+	var sum time.Duration
+	for _, d := range ds {
+		sum += d
+	}
+	return sum / time.Duration(len(ds))
+}
+
+func median(ds []time.Duration) time.Duration {
+	sorted := append([]time.Duration(nil), ds...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
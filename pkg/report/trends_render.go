@@ -0,0 +1,379 @@
+package report
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// TrendFormats lists the output formats accepted by Trends.Write
+var TrendFormats = []string{"csv", "json", "markdown", "html"}
+
+// Write renders the trend report in the named format
+func (tr *Trends) Write(w io.Writer, format string) error {
+	switch format {
+	case "csv":
+		return tr.WriteCSV(w)
+	case "json":
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(tr)
+	case "markdown", "md":
+		return tr.WriteMarkdown(w)
+	case "html":
+		return tr.WriteHTML(w)
+	default:
+		return fmt.Errorf("unknown format %q (want %s)", format, strings.Join(TrendFormats, ", "))
+	}
+}
+
+// WriteCSV renders every series as tidy rows of metric, period, key, value
+// so one file loads into a spreadsheet pivot table
+func (tr *Trends) WriteCSV(w io.Writer) error {
+	cw := csv.NewWriter(w)
+	row := func(metric, period, key string, value string) {
+		cw.Write([]string{metric, period, key, value})
+	}
+
+	row("metric", "period", "key", "value")
+	for _, p := range tr.Periods {
+		label := tr.label(p)
+		row("open", label, "critical", strconv.Itoa(p.Open.Critical))
+		row("open", label, "warning", strconv.Itoa(p.Open.Warning))
+		row("open", label, "info", strconv.Itoa(p.Open.Info))
+		row("open", label, "other", strconv.Itoa(p.Open.Other))
+		row("introduced", label, "", strconv.Itoa(p.Introduced))
+		row("fixed", label, "", strconv.Itoa(p.Fixed))
+	}
+	for _, rt := range tr.ResolveTime {
+		row("resolved", "", rt.Category, strconv.Itoa(rt.Resolved))
+		row("mean_hours_to_resolve", "", rt.Category, formatHours(rt.MeanHours))
+		row("median_hours_to_resolve", "", rt.Category, formatHours(rt.MedianHours))
+	}
+	for _, b := range tr.Age {
+		row("open_age", "", b.Key, strconv.Itoa(b.Count))
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteMarkdown renders the report with tables and ASCII charts
+func (tr *Trends) WriteMarkdown(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString("## strung trends\n\n")
+	fmt.Fprintf(&b, "%d %s periods, generated %s.\n", len(tr.Periods), tr.Period, tr.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("\n### Open findings\n\n```\n")
+	b.WriteString(tr.asciiOpen(40))
+	b.WriteString("```\n\n| Period | Critical | Warning | Info | Other | Total | Introduced | Fixed |\n|---|---:|---:|---:|---:|---:|---:|---:|\n")
+	for _, p := range tr.Periods {
+		fmt.Fprintf(&b, "| %s | %d | %d | %d | %d | %d | %d | %d |\n", tr.label(p),
+			p.Open.Critical, p.Open.Warning, p.Open.Info, p.Open.Other, p.Open.Total(), p.Introduced, p.Fixed)
+	}
+
+	b.WriteString("\n### Introduced vs fixed\n\n```\n")
+	b.WriteString(tr.asciiFlow(20))
+	b.WriteString("```\n")
+
+	b.WriteString("\n### Time to resolve\n\n")
+	if len(tr.ResolveTime) == 0 {
+		b.WriteString("No findings resolved in this window.\n")
+	} else {
+		b.WriteString("| Category | Resolved | Mean | Median |\n|---|---:|---:|---:|\n")
+		for _, rt := range tr.ResolveTime {
+			fmt.Fprintf(&b, "| %s | %d | %s | %s |\n", markdownCell(rt.Category), rt.Resolved, humanHours(rt.MeanHours), humanHours(rt.MedianHours))
+		}
+	}
+
+	b.WriteString("\n### Age of open findings\n\n```\n")
+	b.WriteString(asciiBuckets(tr.Age, 40))
+	b.WriteString("```\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// WriteHTML renders a self-contained page with inline SVG charts
+func (tr *Trends) WriteHTML(w io.Writer) error {
+	var b strings.Builder
+
+	b.WriteString(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>strung trends</title>
+<style>
+body { font-family: system-ui, sans-serif; margin: 2em auto; max-width: 960px; color: #1f2328; }
+table { border-collapse: collapse; margin: 1em 0; }
+th, td { border: 1px solid #d0d7de; padding: 4px 10px; }
+td.n { text-align: right; font-variant-numeric: tabular-nums; }
+.legend span { display: inline-block; margin-right: 1.2em; }
+.legend i { display: inline-block; width: 0.9em; height: 0.9em; margin-right: 0.3em; vertical-align: middle; }
+svg text { font-size: 11px; fill: #57606a; }
+</style>
+</head>
+<body>
+`)
+	fmt.Fprintf(&b, "<h1>strung trends</h1>\n<p>%d %s periods, generated %s.</p>\n",
+		len(tr.Periods), html.EscapeString(tr.Period), tr.GeneratedAt.Format(time.RFC3339))
+
+	b.WriteString("<h2>Open findings</h2>\n")
+	b.WriteString(legend([][2]string{{"critical", colorCritical}, {"warning", colorWarning}, {"info", colorInfo}, {"other", colorOther}}))
+	b.WriteString(tr.svgOpen())
+
+	b.WriteString("<h2>Introduced vs fixed</h2>\n")
+	b.WriteString(legend([][2]string{{"introduced", colorIntroduced}, {"fixed", colorFixed}}))
+	b.WriteString(tr.svgFlow())
+
+	b.WriteString("<table>\n<tr><th>Period</th><th>Critical</th><th>Warning</th><th>Info</th><th>Other</th><th>Total</th><th>Introduced</th><th>Fixed</th></tr>\n")
+	for _, p := range tr.Periods {
+		fmt.Fprintf(&b, "<tr><td>%s</td><td class=n>%d</td><td class=n>%d</td><td class=n>%d</td><td class=n>%d</td><td class=n>%d</td><td class=n>%d</td><td class=n>%d</td></tr>\n",
+			tr.label(p), p.Open.Critical, p.Open.Warning, p.Open.Info, p.Open.Other, p.Open.Total(), p.Introduced, p.Fixed)
+	}
+	b.WriteString("</table>\n")
+
+	b.WriteString("<h2>Time to resolve</h2>\n")
+	if len(tr.ResolveTime) == 0 {
+		b.WriteString("<p>No findings resolved in this window.</p>\n")
+	} else {
+		b.WriteString("<table>\n<tr><th>Category</th><th>Resolved</th><th>Mean</th><th>Median</th></tr>\n")
+		for _, rt := range tr.ResolveTime {
+			fmt.Fprintf(&b, "<tr><td>%s</td><td class=n>%d</td><td class=n>%s</td><td class=n>%s</td></tr>\n",
+				html.EscapeString(rt.Category), rt.Resolved, humanHours(rt.MeanHours), humanHours(rt.MedianHours))
+		}
+		b.WriteString("</table>\n")
+	}
+
+	b.WriteString("<h2>Age of open findings</h2>\n")
+	b.WriteString(svgBuckets(tr.Age))
+
+	b.WriteString("</body>\n</html>\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// label formats a period's start for axis labels and tables
+func (tr *Trends) label(p TrendPeriod) string {
+	if tr.Period == PeriodMonth {
+		return p.Start.Format("2006-01")
+	}
+	return p.Start.Format("2006-01-02")
+}
+
+// asciiOpen draws one stacked bar per period: # critical, = warning,
+// - info, . other
+func (tr *Trends) asciiOpen(width int) string {
+	peak := 0
+	for _, p := range tr.Periods {
+		peak = max(peak, p.Open.Total())
+	}
+
+	var b strings.Builder
+	for _, p := range tr.Periods {
+		fmt.Fprintf(&b, "%-10s |", tr.label(p))
+		for _, seg := range []struct {
+			n    int
+			char string
+		}{{p.Open.Critical, "#"}, {p.Open.Warning, "="}, {p.Open.Info, "-"}, {p.Open.Other, "."}} {
+			b.WriteString(strings.Repeat(seg.char, scale(seg.n, peak, width)))
+		}
+		fmt.Fprintf(&b, " %d\n", p.Open.Total())
+	}
+	b.WriteString("\n# critical  = warning  - info  . other\n")
+	return b.String()
+}
+
+// asciiFlow draws introduced (+) and fixed (-) bars per period
+func (tr *Trends) asciiFlow(width int) string {
+	peak := 0
+	for _, p := range tr.Periods {
+		peak = max(peak, p.Introduced, p.Fixed)
+	}
+
+	var b strings.Builder
+	for _, p := range tr.Periods {
+		fmt.Fprintf(&b, "%-10s | %-*s %3d introduced\n", tr.label(p), width, strings.Repeat("+", scale(p.Introduced, peak, width)), p.Introduced)
+		fmt.Fprintf(&b, "%-10s | %-*s %3d fixed\n", "", width, strings.Repeat("-", scale(p.Fixed, peak, width)), p.Fixed)
+	}
+	return b.String()
+}
+
+func asciiBuckets(buckets []Bucket, width int) string {
+	peak := 0
+	for _, bk := range buckets {
+		peak = max(peak, bk.Count)
+	}
+
+	var b strings.Builder
+	for _, bk := range buckets {
+		fmt.Fprintf(&b, "%-6s |%s %d\n", bk.Key, strings.Repeat("#", scale(bk.Count, peak, width)), bk.Count)
+	}
+	return b.String()
+}
+
+// scale maps n out of peak onto width characters, keeping any non-zero
+// value visible
+func scale(n, peak, width int) int {
+	if n <= 0 || peak <= 0 {
+		return 0
+	}
+	return max(1, n*width/peak)
+}
+
+// Chart colors
+const (
+	colorCritical   = "#cf222e"
+	colorWarning    = "#d4a72c"
+	colorInfo       = "#0969da"
+	colorOther      = "#8c959f"
+	colorIntroduced = "#cf222e"
+	colorFixed      = "#1a7f37"
+)
+
+// Chart geometry
+const (
+	svgWidth   = 900
+	svgHeight  = 240
+	svgMarginL = 40
+	svgMarginB = 40
+	svgMarginT = 10
+)
+
+// svgOpen draws stacked columns of open findings by severity
+func (tr *Trends) svgOpen() string {
+	peak := 0
+	for _, p := range tr.Periods {
+		peak = max(peak, p.Open.Total())
+	}
+
+	c := newSVGChart(len(tr.Periods), peak)
+	for i, p := range tr.Periods {
+		y := 0
+		for _, seg := range []struct {
+			n     int
+			color string
+			name  string
+		}{{p.Open.Critical, colorCritical, "critical"}, {p.Open.Warning, colorWarning, "warning"}, {p.Open.Info, colorInfo, "info"}, {p.Open.Other, colorOther, "other"}} {
+			c.bar(i, 0, 1, y, seg.n, seg.color, fmt.Sprintf("%s: %d %s", tr.label(p), seg.n, seg.name))
+			y += seg.n
+		}
+		c.label(i, tr.label(p))
+	}
+	return c.String()
+}
+
+// svgFlow draws paired introduced and fixed columns per period
+func (tr *Trends) svgFlow() string {
+	peak := 0
+	for _, p := range tr.Periods {
+		peak = max(peak, p.Introduced, p.Fixed)
+	}
+
+	c := newSVGChart(len(tr.Periods), peak)
+	for i, p := range tr.Periods {
+		c.bar(i, 0, 2, 0, p.Introduced, colorIntroduced, fmt.Sprintf("%s: %d introduced", tr.label(p), p.Introduced))
+		c.bar(i, 1, 2, 0, p.Fixed, colorFixed, fmt.Sprintf("%s: %d fixed", tr.label(p), p.Fixed))
+		c.label(i, tr.label(p))
+	}
+	return c.String()
+}
+
+func svgBuckets(buckets []Bucket) string {
+	peak := 0
+	for _, bk := range buckets {
+		peak = max(peak, bk.Count)
+	}
+
+	c := newSVGChart(len(buckets), peak)
+	for i, bk := range buckets {
+		c.bar(i, 0, 1, 0, bk.Count, colorInfo, fmt.Sprintf("%s: %d", bk.Key, bk.Count))
+		c.label(i, bk.Key)
+	}
+	return c.String()
+}
+
+// svgChart lays out a column chart with one slot per category
+type svgChart struct {
+	b     strings.Builder
+	slots int
+	peak  int
+}
+
+func newSVGChart(slots, peak int) *svgChart {
+	c := &svgChart{slots: max(slots, 1), peak: max(peak, 1)}
+	fmt.Fprintf(&c.b, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d" role="img">`+"\n",
+		svgWidth, svgHeight, svgWidth, svgHeight)
+
+	// Axis with the peak value
+	base := svgHeight - svgMarginB
+	fmt.Fprintf(&c.b, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="#d0d7de"/>`+"\n", svgMarginL, base, svgWidth, base)
+	fmt.Fprintf(&c.b, `<text x="%d" y="%d" text-anchor="end">%d</text>`+"\n", svgMarginL-4, svgMarginT+10, c.peak)
+	fmt.Fprintf(&c.b, `<text x="%d" y="%d" text-anchor="end">0</text>`+"\n", svgMarginL-4, base)
+	return c
+}
+
+func (c *svgChart) slotWidth() float64 {
+	return float64(svgWidth-svgMarginL) / float64(c.slots)
+}
+
+// bar draws n units starting at offset units up, in sub-column sub of subs
+func (c *svgChart) bar(slot, sub, subs, offset, n int, color, title string) {
+	if n <= 0 {
+		return
+	}
+	plot := float64(svgHeight - svgMarginB - svgMarginT)
+	unit := plot / float64(c.peak)
+	sw := c.slotWidth()
+	w := sw * 0.8 / float64(subs)
+	x := float64(svgMarginL) + float64(slot)*sw + sw*0.1 + float64(sub)*w
+	h := float64(n) * unit
+	y := float64(svgHeight-svgMarginB) - float64(offset)*unit - h
+	fmt.Fprintf(&c.b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s"><title>%s</title></rect>`+"\n",
+		x, y, w, h, color, html.EscapeString(title))
+}
+
+func (c *svgChart) label(slot int, text string) {
+	x := float64(svgMarginL) + (float64(slot)+0.5)*c.slotWidth()
+	y := svgHeight - svgMarginB + 14
+	// Rotate dense labels so they do not overlap
+	if c.slotWidth() < 60 {
+		fmt.Fprintf(&c.b, `<text x="%.1f" y="%d" text-anchor="end" transform="rotate(-45 %.1f %d)">%s</text>`+"\n",
+			x, y, x, y, html.EscapeString(text))
+		return
+	}
+	fmt.Fprintf(&c.b, `<text x="%.1f" y="%d" text-anchor="middle">%s</text>`+"\n", x, y, html.EscapeString(text))
+}
+
+func (c *svgChart) String() string {
+	return c.b.String() + "</svg>\n"
+}
+
+func legend(entries [][2]string) string {
+	var b strings.Builder
+	b.WriteString(`<p class="legend">`)
+	for _, e := range entries {
+		fmt.Fprintf(&b, `<span><i style="background:%s"></i>%s</span>`, e[1], html.EscapeString(e[0]))
+	}
+	b.WriteString("</p>\n")
+	return b.String()
+}
+
+func formatHours(h float64) string {
+	return strconv.FormatFloat(h, 'f', 1, 64)
+}
+
+// humanHours renders hours as "5.0h" or "3.2d"
+func humanHours(h float64) string {
+	if h >= 48 {
+		return strconv.FormatFloat(h/24, 'f', 1, 64) + "d"
+	}
+	return strconv.FormatFloat(h, 'f', 1, 64) + "h"
+}
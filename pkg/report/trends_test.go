@@ -0,0 +1,226 @@
+package report
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+func TestBuildTrends(t *testing.T) {
+	database := setupTestDB(t)
+
+	// Wednesday; weeks start Monday 2026-03-02, 03-09, 03-16
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+	findings := []*db.Finding{
+		{Fingerprint: "fp1", File: "a.go", Severity: "critical", Category: "security", FirstSeen: time.Date(2026, 3, 3, 0, 0, 0, 0, time.UTC)},
+		{Fingerprint: "fp2", File: "a.go", Severity: "warning", Category: "security", FirstSeen: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)},
+		{Fingerprint: "fp3", File: "b.go", Severity: "info", Category: "style", FirstSeen: time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)},
+		{Fingerprint: "fp4", File: "b.go", Severity: "warning", Category: "style", FirstSeen: now.Add(-200 * day)},
+	}
+	for _, f := range findings {
+		f.LastSeen = now
+		if err := database.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	// fp2 fixed in week 2 after 6 days, fp1 fixed in week 3 after 14 days
+	database.MarkResolved("fp2", time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC))
+	database.MarkResolved("fp1", time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC))
+
+	tr, err := BuildTrends(database, now, TrendOptions{Period: PeriodWeek, Since: time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC)})
+	if err != nil {
+		t.Fatalf("BuildTrends failed: %v", err)
+	}
+
+	if len(tr.Periods) != 3 {
+		t.Fatalf("got %d periods, want 3", len(tr.Periods))
+	}
+	if !tr.Periods[0].Start.Equal(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("first period starts %v, want Monday 2026-03-02", tr.Periods[0].Start)
+	}
+
+	want := []struct {
+		open, introduced, fixed int
+	}{
+		{3, 2, 0}, // fp4 (old), fp1, fp2
+		{3, 1, 1}, // fp2 fixed, fp3 introduced
+		{2, 0, 1}, // fp1 fixed
+	}
+	for i, w := range want {
+		p := tr.Periods[i]
+		if p.Open.Total() != w.open || p.Introduced != w.introduced || p.Fixed != w.fixed {
+			t.Errorf("period %d: open=%d introduced=%d fixed=%d, want %+v", i, p.Open.Total(), p.Introduced, p.Fixed, w)
+		}
+	}
+	if tr.Periods[0].Open.Critical != 1 || tr.Periods[2].Open.Critical != 0 {
+		t.Errorf("critical series = %d..%d", tr.Periods[0].Open.Critical, tr.Periods[2].Open.Critical)
+	}
+
+	if len(tr.ResolveTime) != 1 || tr.ResolveTime[0].Category != "security" || tr.ResolveTime[0].Resolved != 2 {
+		t.Fatalf("ResolveTime = %+v", tr.ResolveTime)
+	}
+	if got := tr.ResolveTime[0].MeanHours; got != 10*24 {
+		t.Errorf("mean hours = %v, want 240", got)
+	}
+
+	ages := map[string]int{}
+	for _, b := range tr.Age {
+		ages[b.Key] = b.Count
+	}
+	if ages["7-30d"] != 1 || ages[">90d"] != 1 {
+		t.Errorf("Age = %+v", tr.Age)
+	}
+}
+
+func TestBuildTrends_Events(t *testing.T) {
+	database := setupTestDB(t)
+
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	date := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	for _, f := range []*db.Finding{
+		{Fingerprint: "fp1", File: "a.go", Severity: "warning", Category: "security", FirstSeen: date(3)},
+		{Fingerprint: "fp2", File: "b.go", Severity: "info", Category: "style", FirstSeen: date(3)},
+	} {
+		f.LastSeen = now
+		if err := database.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	if err := database.Suppress("fp2", date(10)); err != nil {
+		t.Fatalf("Suppress failed: %v", err)
+	}
+
+	// fp1 was critical, downgraded, fixed, then reported again
+	for _, e := range []*db.Event{
+		{Fingerprint: "fp1", Event: db.EventNew, Severity: "critical", CreatedAt: date(3)},
+		{Fingerprint: "fp1", Event: db.EventSeen, Severity: "critical", CreatedAt: date(4)},
+		{Fingerprint: "fp1", Event: db.EventChanged, Severity: "warning", CreatedAt: date(10)},
+		{Fingerprint: "fp1", Event: db.EventResolved, Severity: "warning", CreatedAt: date(11)},
+		{Fingerprint: "fp1", Event: db.EventNew, Severity: "warning", CreatedAt: date(17)},
+		{Fingerprint: "fp2", Event: db.EventNew, Severity: "info", CreatedAt: date(3)},
+		{Fingerprint: "fp2", Event: db.EventSuppressed, Severity: "info", CreatedAt: date(10)},
+	} {
+		if err := database.RecordEvent(e); err != nil {
+			t.Fatalf("RecordEvent failed: %v", err)
+		}
+	}
+
+	tr, err := BuildTrends(database, now, TrendOptions{Period: PeriodWeek, Since: date(2)})
+	if err != nil {
+		t.Fatalf("BuildTrends failed: %v", err)
+	}
+
+	want := []struct {
+		open       SeverityCounts
+		introduced int
+		fixed      int
+	}{
+		{SeverityCounts{Critical: 1, Info: 1}, 2, 0},
+		{SeverityCounts{}, 0, 1}, // fp1 fixed, fp2 suppressed
+		{SeverityCounts{Warning: 1}, 1, 0},
+	}
+	if len(tr.Periods) != len(want) {
+		t.Fatalf("got %d periods, want %d", len(tr.Periods), len(want))
+	}
+	for i, w := range want {
+		p := tr.Periods[i]
+		if p.Open != w.open || p.Introduced != w.introduced || p.Fixed != w.fixed {
+			t.Errorf("period %d: open=%+v introduced=%d fixed=%d, want %+v", i, p.Open, p.Introduced, p.Fixed, w)
+		}
+	}
+
+	if len(tr.ResolveTime) != 1 || tr.ResolveTime[0].MeanHours != 8*24 {
+		t.Errorf("ResolveTime = %+v, want 8 days in security", tr.ResolveTime)
+	}
+	open := 0
+	for _, b := range tr.Age {
+		open += b.Count
+	}
+	if open != 1 {
+		t.Errorf("Age counts %d open findings, want 1", open)
+	}
+}
+
+func TestBuildTrends_Defaults(t *testing.T) {
+	now := time.Date(2026, 3, 18, 12, 0, 0, 0, time.UTC)
+	tr, err := BuildTrends(setupTestDB(t), now, TrendOptions{Period: PeriodMonth})
+	if err != nil {
+		t.Fatalf("BuildTrends failed: %v", err)
+	}
+	if len(tr.Periods) != DefaultPeriods {
+		t.Errorf("got %d periods, want %d", len(tr.Periods), DefaultPeriods)
+	}
+	if got := tr.label(tr.Periods[0]); got != "2025-04" {
+		t.Errorf("first month = %s, want 2025-04", got)
+	}
+
+	if _, err := BuildTrends(setupTestDB(t), now, TrendOptions{Period: "year"}); err == nil {
+		t.Error("expected error for invalid period")
+	}
+}
+
+func TestTrendsWrite(t *testing.T) {
+	tr := &Trends{
+		Period: PeriodDay,
+		Periods: []TrendPeriod{
+			{Start: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC), Open: SeverityCounts{Critical: 2, Warning: 4}, Introduced: 6},
+			{Start: time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC), Open: SeverityCounts{Critical: 1, Warning: 4}, Fixed: 1},
+		},
+		ResolveTime: []ResolveTime{{Category: "a<b", Resolved: 1, MeanHours: 72, MedianHours: 72}},
+		Age:         []Bucket{{Key: "<1d", Count: 5}},
+	}
+
+	var buf bytes.Buffer
+	if err := tr.Write(&buf, "csv"); err != nil {
+		t.Fatal(err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid CSV: %v", err)
+	}
+	if len(rows) != 1+2*6+3+1 {
+		t.Errorf("got %d CSV rows", len(rows))
+	}
+	if strings.Join(rows[1], ",") != "open,2026-03-01,critical,2" {
+		t.Errorf("first data row = %v", rows[1])
+	}
+
+	buf.Reset()
+	if err := tr.Write(&buf, "markdown"); err != nil {
+		t.Fatal(err)
+	}
+	md := buf.String()
+	for _, want := range []string{"2026-03-01 |" + strings.Repeat("#", 13) + strings.Repeat("=", 26) + " 6", "| a&lt;b | 1 | 3.0d | 3.0d |", "| 2026-03-02 | 1 | 4 | 0 | 0 | 5 | 0 | 1 |"} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown missing %q:\n%s", want, md)
+		}
+	}
+
+	buf.Reset()
+	if err := tr.Write(&buf, "html"); err != nil {
+		t.Fatal(err)
+	}
+	page := buf.String()
+	for _, want := range []string{"<!DOCTYPE html>", "<svg", "<title>2026-03-01: 2 critical</title>", "a&lt;b"} {
+		if !strings.Contains(page, want) {
+			t.Errorf("html missing %q", want)
+		}
+	}
+	if strings.Contains(page, "<script") || strings.Contains(page, "<link") {
+		t.Error("html should be self-contained")
+	}
+}
+
+func TestScale(t *testing.T) {
+	if scale(1, 1000, 40) != 1 {
+		t.Error("non-zero values should stay visible")
+	}
+	if scale(0, 10, 40) != 0 || scale(10, 10, 40) != 40 {
+		t.Error("scale endpoints wrong")
+	}
+}
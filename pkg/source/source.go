@@ -0,0 +1,146 @@
+// Package source reads code context for findings from the working tree.
+package source
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// MaxFileSize is the largest file read for context; bigger files are
+// usually generated or minified and make poor excerpts
+const MaxFileSize = 2 << 20
+
+// Errors returned for files that cannot provide context
+var (
+	ErrOutsideRoot = errors.New("path outside source root")
+	ErrBinary      = errors.New("binary file")
+	ErrTooLarge    = errors.New("file too large")
+	ErrLineRange   = errors.New("line out of range")
+)
+
+// Excerpt is a window of source lines around a finding
+type Excerpt struct {
+	Start  int      // Line number of Lines[0]
+	Target int      // Flagged line number
+	Column int      // Flagged column (1-based); 0 when unknown
+	Lines  []string // Lines without trailing newlines
+}
+
+// End returns the line number of the last excerpt line
+func (e *Excerpt) End() int {
+	return e.Start + len(e.Lines) - 1
+}
+
+// TargetLine returns the text of the flagged line
+func (e *Excerpt) TargetLine() string {
+	return e.Lines[e.Target-e.Start]
+}
+
+// Reader loads files under a root directory, caching each file for the
+// lifetime of the reader
+type Reader struct {
+	Root  string
+	cache map[string]fileResult
+}
+
+type fileResult struct {
+	lines []string
+	err   error
+}
+
+// NewReader creates a reader for files under root
+func NewReader(root string) *Reader {
+	return &Reader{Root: root, cache: make(map[string]fileResult)}
+}
+
+// Line returns the text of one line
+func (r *Reader) Line(file string, line int) (string, error) {
+	ex, err := r.Excerpt(file, line, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	return ex.TargetLine(), nil
+}
+
+// Excerpt returns the flagged line with up to context lines on each side
+func (r *Reader) Excerpt(file string, line, column, context int) (*Excerpt, error) {
+	lines, err := r.lines(file)
+	if err != nil {
+		return nil, err
+	}
+	if line < 1 || line > len(lines) {
+		return nil, fmt.Errorf("%s:%d: %w (file has %d lines)", file, line, ErrLineRange, len(lines))
+	}
+
+	start := max(1, line-context)
+	end := min(len(lines), line+context)
+	return &Excerpt{
+		Start:  start,
+		Target: line,
+		Column: column,
+		Lines:  lines[start-1 : end],
+	}, nil
+}
+
+// lines reads and caches a file split into lines
+func (r *Reader) lines(file string) ([]string, error) {
+	if res, ok := r.cache[file]; ok {
+		return res.lines, res.err
+	}
+
+	lines, err := r.read(file)
+	r.cache[file] = fileResult{lines: lines, err: err}
+	return lines, err
+}
+
+func (r *Reader) read(file string) ([]string, error) {
+	path, err := r.resolve(file)
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > MaxFileSize {
+		return nil, fmt.Errorf("%s: %w", file, ErrTooLarge)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.IndexByte(data[:min(len(data), 8000)], 0) >= 0 {
+		return nil, fmt.Errorf("%s: %w", file, ErrBinary)
+	}
+
+	text := strings.ReplaceAll(string(data), "\r\n", "\n")
+	text = strings.TrimSuffix(text, "\n")
+	return strings.Split(text, "\n"), nil
+}
+
+// resolve maps a scanner path to a file under the root, refusing paths
+// that escape it
+func (r *Reader) resolve(file string) (string, error) {
+	root, err := filepath.Abs(r.Root)
+	if err != nil {
+		return "", err
+	}
+
+	path := filepath.FromSlash(file)
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	path = filepath.Clean(path)
+
+	rel, err := filepath.Rel(root, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s: %w", file, ErrOutsideRoot)
+	}
+	return path, nil
+}
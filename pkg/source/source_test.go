@@ -0,0 +1,65 @@
+package source
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func writeFile(t *testing.T, root, name, content string) {
+	t.Helper()
+	path := filepath.Join(root, name)
+	os.MkdirAll(filepath.Dir(path), 0o755)
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestReader_Excerpt(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "src/a.ts", "one\r\ntwo\r\nthree\r\nfour\r\nfive\r\n")
+
+	r := NewReader(root)
+
+	ex, err := r.Excerpt("src/a.ts", 2, 3, 2)
+	if err != nil {
+		t.Fatalf("Excerpt failed: %v", err)
+	}
+	if ex.Start != 1 || ex.End() != 4 || ex.TargetLine() != "two" || ex.Column != 3 {
+		t.Errorf("Unexpected excerpt: %+v", ex)
+	}
+	if !reflect.DeepEqual(ex.Lines, []string{"one", "two", "three", "four"}) {
+		t.Errorf("Lines = %q", ex.Lines)
+	}
+
+	if line, _ := r.Line("./src/a.ts", 5); line != "five" {
+		t.Errorf("Line(5) = %q", line)
+	}
+	if _, err := r.Line("src/a.ts", 6); !errors.Is(err, ErrLineRange) {
+		t.Errorf("Expected ErrLineRange, got %v", err)
+	}
+}
+
+func TestReader_Rejects(t *testing.T) {
+	root := t.TempDir()
+	writeFile(t, root, "bin.dat", "abc\x00def")
+	outside := filepath.Join(t.TempDir(), "secret.txt")
+	os.WriteFile(outside, []byte("secret\n"), 0o644)
+
+	r := NewReader(root)
+
+	if _, err := r.Line("bin.dat", 1); !errors.Is(err, ErrBinary) {
+		t.Errorf("Expected ErrBinary, got %v", err)
+	}
+	if _, err := r.Line("../etc/passwd", 1); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("Expected ErrOutsideRoot, got %v", err)
+	}
+	if _, err := r.Line(outside, 1); !errors.Is(err, ErrOutsideRoot) {
+		t.Errorf("Expected ErrOutsideRoot for absolute path, got %v", err)
+	}
+	if _, err := r.Line("missing.ts", 1); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected not-exist error, got %v", err)
+	}
+}
@@ -0,0 +1,159 @@
+package sync
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+// Issue comment kinds
+const (
+	CommentSeverity   = "severity"   // Severity changed
+	CommentMoved      = "moved"      // Line or file changed
+	CommentMessage    = "message"    // Scanner message reworded
+	CommentRegression = "regression" // Resolved finding reported again
+	CommentClosing    = "closing"    // Finding gone, issue about to be closed
+	CommentPersists   = "persists"   // Issue closed in Beads, finding still reported
+)
+
+// IssueComment is a structured comment on a tracked issue: a summary line
+// followed by "Key: value" details
+type IssueComment struct {
+	IssueID     string
+	Fingerprint string
+	Kind        string
+	Summary     string
+	Details     []CommentDetail
+}
+
+// CommentDetail is one "Key: value" line of a comment
+type CommentDetail struct {
+	Key   string
+	Value string
+}
+
+// Digest identifies the comment's content. The scan that posts it is not
+// part of the digest, so repeated syncs produce the same digest.
+func (c IssueComment) Digest() string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\x00%s\x00", c.Kind, c.Summary)
+	for _, d := range c.Details {
+		fmt.Fprintf(h, "%s\x00%s\x00", d.Key, d.Value)
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// Body renders the comment, stamped with the scan that triggered it
+func (c IssueComment) Body(scanID string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "strung: %s\n", c.Summary)
+	if len(c.Details) > 0 || scanID != "" {
+		b.WriteString("\n")
+	}
+	for _, d := range c.Details {
+		fmt.Fprintf(&b, "%s: %s\n", d.Key, d.Value)
+	}
+	if scanID != "" {
+		fmt.Fprintf(&b, "Scan: %s\n", scanID)
+	}
+	return b.String()
+}
+
+func location(file string, line int) string {
+	return fmt.Sprintf("%s:%d", file, line)
+}
+
+// ChangeComments returns comments for the severity, position and message
+// drift of a changed finding. Other drift is not worth a comment.
+func ChangeComments(c ChangeRecord) []IssueComment {
+	prev, cur := c.Previous, c.Current
+	comment := func(kind, summary string, details ...CommentDetail) IssueComment {
+		return IssueComment{IssueID: prev.IssueID, Fingerprint: prev.Fingerprint, Kind: kind,
+			Summary: summary, Details: details}
+	}
+
+	var comments []IssueComment
+	if c.HasField("severity") {
+		comments = append(comments, comment(CommentSeverity,
+			fmt.Sprintf("severity changed from %s to %s", prev.Severity, cur.Severity),
+			CommentDetail{"Location", location(cur.File, cur.Line)}))
+	}
+	if c.HasField("file") || c.HasField("line") {
+		comments = append(comments, comment(CommentMoved,
+			fmt.Sprintf("finding moved from %s to %s", location(prev.File, prev.Line), location(cur.File, cur.Line))))
+	}
+	if c.HasField("message") {
+		comments = append(comments, comment(CommentMessage, "scanner message changed",
+			CommentDetail{"Location", location(cur.File, cur.Line)},
+			CommentDetail{"Previous", oneLine(prev.Message)},
+			CommentDetail{"Current", oneLine(cur.Message)}))
+	}
+	return comments
+}
+
+// MoveComments returns the comment for a fuzzy-matched move, or none when
+// only the fingerprint changed (e.g. a re-worded message at the same place)
+func MoveComments(m MoveRecord) []IssueComment {
+	if m.Previous.File == m.Current.File && m.Previous.Line == m.Current.Line {
+		return nil
+	}
+	return []IssueComment{{
+		IssueID:     m.Previous.IssueID,
+		Fingerprint: m.Fingerprint,
+		Kind:        CommentMoved,
+		Summary: fmt.Sprintf("finding moved from %s to %s",
+			location(m.Previous.File, m.Previous.Line), location(m.Current.File, m.Current.Line)),
+		Details: []CommentDetail{{"Match score", fmt.Sprintf("%.2f", m.Score)}},
+	}}
+}
+
+// RegressionComment returns the comment for the issue filed when a
+// resolved finding is reported again
+func RegressionComment(issueID string, previous *db.Finding, current parser.UBSFinding) IssueComment {
+	details := []CommentDetail{{"Location", location(current.File, current.Line)}}
+	if previous.ResolvedAt != nil {
+		details = append(details, CommentDetail{"Resolved", previous.ResolvedAt.UTC().Format("2006-01-02")})
+	}
+	return IssueComment{
+		IssueID:     issueID,
+		Fingerprint: previous.Fingerprint,
+		Kind:        CommentRegression,
+		Summary:     fmt.Sprintf("regression of %s, which was resolved and is reported again", previous.IssueID),
+		Details:     details,
+	}
+}
+
+// ClosingComment returns the comment posted before auto-closing the issue
+// of a finding the scan no longer reports
+func ClosingComment(f *db.Finding) IssueComment {
+	return IssueComment{
+		IssueID:     f.IssueID,
+		Fingerprint: f.Fingerprint,
+		Kind:        CommentClosing,
+		Summary:     "finding no longer reported, closing this issue",
+		Details: []CommentDetail{
+			{"Location", location(f.File, f.Line)},
+			{"Last seen", f.LastSeen.UTC().Format("2006-01-02 15:04 MST")},
+		},
+	}
+}
+
+// PersistsComment returns the comment for an issue closed in Beads while
+// the finding is still reported
+func PersistsComment(f *db.Finding) IssueComment {
+	return IssueComment{
+		IssueID:     f.IssueID,
+		Fingerprint: f.Fingerprint,
+		Kind:        CommentPersists,
+		Summary:     "this issue was closed, but the finding is still reported; sync leaves it suppressed until the issue is reopened",
+		Details:     []CommentDetail{{"Location", location(f.File, f.Line)}},
+	}
+}
+
+// oneLine collapses whitespace so a value fits a "Key: value" line
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,66 @@
+package sync
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+func TestChangeComments(t *testing.T) {
+	prev := &db.Finding{Fingerprint: "fp-1", IssueID: "bd-1", File: "a.go", Line: 10,
+		Severity: "warning", Message: "old message"}
+	change := ChangeRecord{
+		Previous: prev,
+		Current:  parser.UBSFinding{File: "a.go", Line: 12, Severity: "critical", Message: "new\n  message"},
+		Fields: []FieldChange{
+			{Field: "severity", Previous: "warning", Current: "critical"},
+			{Field: "line", Previous: "10", Current: "12"},
+			{Field: "message", Previous: "old message", Current: "new message"},
+			{Field: "suggestion", Previous: "", Current: "fix it"},
+		},
+	}
+
+	comments := ChangeComments(change)
+	var kinds []string
+	for _, c := range comments {
+		if c.IssueID != "bd-1" || c.Fingerprint != "fp-1" {
+			t.Errorf("comment %s targets %s/%s", c.Kind, c.IssueID, c.Fingerprint)
+		}
+		kinds = append(kinds, c.Kind)
+	}
+	if got := strings.Join(kinds, ","); got != "severity,moved,message" {
+		t.Fatalf("kinds = %s, want severity,moved,message", got)
+	}
+
+	body := comments[2].Body("scan-1")
+	for _, want := range []string{"strung: scanner message changed", "Current: new message", "Scan: scan-1"} {
+		if !strings.Contains(body, want) {
+			t.Errorf("body missing %q:\n%s", want, body)
+		}
+	}
+	if comments[0].Summary != "severity changed from warning to critical" {
+		t.Errorf("summary = %q", comments[0].Summary)
+	}
+}
+
+func TestIssueComment_Digest(t *testing.T) {
+	prev := &db.Finding{Fingerprint: "fp-1", IssueID: "bd-1", File: "a.go", Line: 10, Severity: "warning"}
+	change := func(line int) ChangeRecord {
+		return ChangeRecord{Previous: prev, Current: parser.UBSFinding{File: "a.go", Line: line, Severity: "warning"},
+			Fields: []FieldChange{{Field: "line"}}}
+	}
+
+	// The same drift seen by another scan is the same comment
+	a, b := ChangeComments(change(12))[0], ChangeComments(change(12))[0]
+	if a.Digest() != b.Digest() {
+		t.Error("identical comments have different digests")
+	}
+	if a.Body("scan-1") == a.Body("scan-2") {
+		t.Error("body does not name the scan")
+	}
+	if c := ChangeComments(change(14))[0]; c.Digest() == a.Digest() {
+		t.Error("different moves have the same digest")
+	}
+}
@@ -3,6 +3,8 @@ package sync
 
 import (
 	"fmt"
+	"strconv"
+	"time"
 
 	"github.com/TheEditor/strung/pkg/db"
 	"github.com/TheEditor/strung/pkg/parser"
@@ -11,92 +13,438 @@ import (
 // DiffResult contains categorized findings after comparing scan vs DB
 type DiffResult struct {
 	New      []parser.UBSFinding // Not in DB
-	Changed  []ChangeRecord      // In DB but severity changed
+	Changed  []ChangeRecord      // In DB but tracked fields drifted
 	Resolved []*db.Finding       // In DB but not in current scan
+	Moved    []MoveRecord        // Fuzzy-matched across a rename, line shift or rewording
+
+	// Unchanged findings still need last_seen/position refreshed but
+	// require no issue tracker action
+	Unchanged []ChangeRecord
+
+	// FixedOnBranch lists inherited findings missing from a branch scan.
+	// They are resolved for the branch only; their issues stay open until
+	// the branch is merged.
+	FixedOnBranch []*db.Finding
 }
 
 // ChangeRecord represents a changed finding
 type ChangeRecord struct {
 	Previous *db.Finding
 	Current  parser.UBSFinding
+	Fields   []FieldChange // Tracked fields that differ between Previous and Current
+}
+
+// FieldChange records a single tracked field that drifted between scans
+type FieldChange struct {
+	Field    string // "severity", "line", "file", "category", "message", "suggestion", "code_snippet", or "issue" (set by sync)
+	Previous string
+	Current  string
+}
+
+// String returns "field previous → current"
+func (fc FieldChange) String() string {
+	return fmt.Sprintf("%s %s → %s", fc.Field, fc.Previous, fc.Current)
+}
+
+// HasField reports whether the named field changed
+func (cr ChangeRecord) HasField(field string) bool {
+	for _, fc := range cr.Fields {
+		if fc.Field == field {
+			return true
+		}
+	}
+	return false
 }
 
 // Differ computes diffs between scans and DB state
 type Differ struct {
 	db *db.TrackingDB
+
+	// MatchThreshold is the minimum similarity for reporting an unmatched
+	// new/resolved pair as Moved. Zero disables fuzzy matching.
+	MatchThreshold float64
+
+	// Renames maps old file paths to new ones (e.g. from git rename
+	// detection) so renamed files pair up during fuzzy matching
+	Renames map[string]string
 }
 
 // NewDiffer creates a new differ
 func NewDiffer(database *db.TrackingDB) *Differ {
-	return &Differ{db: database}
+	return &Differ{db: database, MatchThreshold: DefaultMatchThreshold}
 }
 
 // Diff computes diff between current scan and DB state.
-// Returns categorized findings: new, changed, resolved.
+// Returns categorized findings: new, changed, resolved, moved.
+//
+// Findings on every branch are matched, so a branch scan recognises the
+// findings it inherited instead of re-creating them, but only findings in
+// the database's branch view can be resolved. In a branch view, inherited
+// findings are never updated or closed: drift is ignored and missing
+// findings are reported as FixedOnBranch.
 func (d *Differ) Diff(currentFindings []parser.UBSFinding) (*DiffResult, error) {
+	// Get all unresolved findings from DB
+	dbFindings, err := d.db.GetUnresolved()
+	if err != nil {
+		return nil, fmt.Errorf("get unresolved findings: %w", err)
+	}
+
+	result := diffFindings(currentFindings, dbFindings, d.db.Project(), d.Renames, d.MatchThreshold, d.db.InView)
+	if err := d.splitTaken(result); err != nil {
+		return nil, err
+	}
+	if d.db.Branch() != "" {
+		d.splitInherited(result)
+	}
+	return result, nil
+}
+
+// splitTaken reports moves onto a fingerprint a resolved row still holds as
+// New and Resolved: the tracked finding cannot be re-keyed onto it, and the
+// current finding is a regression of the resolved one
+func (d *Differ) splitTaken(result *DiffResult) error {
+	moved := result.Moved[:0]
+	for _, m := range result.Moved {
+		row, err := d.db.Get(m.Fingerprint)
+		if err != nil {
+			return fmt.Errorf("check moved finding: %w", err)
+		}
+		if row == nil {
+			moved = append(moved, m)
+			continue
+		}
+		result.New = append(result.New, m.Current)
+		result.Resolved = append(result.Resolved, m.Previous)
+	}
+	result.Moved = moved
+	return nil
+}
+
+// splitInherited moves inherited findings out of the actionable categories
+// of a branch view's diff
+func (d *Differ) splitInherited(result *DiffResult) {
+	changed := result.Changed[:0]
+	for _, c := range result.Changed {
+		if d.db.Inherited(c.Previous) {
+			result.Unchanged = append(result.Unchanged, ChangeRecord{Previous: c.Previous, Current: c.Current})
+		} else {
+			changed = append(changed, c)
+		}
+	}
+	result.Changed = changed
+
+	moved := result.Moved[:0]
+	for _, m := range result.Moved {
+		if d.db.Inherited(m.Previous) {
+			result.Unchanged = append(result.Unchanged, ChangeRecord{Previous: m.Previous, Current: m.Current})
+		} else {
+			moved = append(moved, m)
+		}
+	}
+	result.Moved = moved
+
+	resolved := result.Resolved[:0]
+	for _, r := range result.Resolved {
+		if d.db.Inherited(r) {
+			result.FixedOnBranch = append(result.FixedOnBranch, r)
+		} else {
+			resolved = append(resolved, r)
+		}
+	}
+	result.Resolved = resolved
+}
+
+// DiffScans compares two scans directly, without a tracking database. Base
+// findings stand in for tracked rows, so Resolved lists findings fixed in
+// head and records carry no issue IDs or first-seen times.
+func DiffScans(base, head []parser.UBSFinding, renames map[string]string, threshold float64) *DiffResult {
+	previous := make([]*db.Finding, 0, len(base))
+	for _, f := range base {
+		previous = append(previous, &db.Finding{
+			Fingerprint:        Fingerprint(f),
+			FingerprintVersion: db.FingerprintVersion,
+			File:               f.File,
+			Line:               f.Line,
+			Column:             f.Column,
+			Severity:           f.Severity,
+			Category:           f.Category,
+			Message:            f.Message,
+			Suggestion:         f.Suggestion,
+			CodeSnippet:        f.CodeSnippet,
+			SourceContext:      f.SourceContext,
+			Tool:               f.Tool,
+			RuleID:             f.RuleID,
+		})
+	}
+
+	result := diffFindings(head, previous, "", renames, threshold, nil)
+
+	// Base rows carry no issue hash but always have a full payload, so
+	// suggestion and snippet drift is compared here
+	for i := range result.Changed {
+		c := &result.Changed[i]
+		c.Fields = append(c.Fields, comparePayload(c.Previous, c.Current)...)
+	}
+	for i := range result.Moved {
+		m := &result.Moved[i]
+		m.Fields = append(m.Fields, comparePayload(m.Previous, m.Current)...)
+	}
+	unchanged := result.Unchanged[:0]
+	for _, u := range result.Unchanged {
+		if fields := comparePayload(u.Previous, u.Current); len(fields) > 0 {
+			result.Changed = append(result.Changed, ChangeRecord{Previous: u.Previous, Current: u.Current, Fields: fields})
+		} else {
+			unchanged = append(unchanged, u)
+		}
+	}
+	result.Unchanged = unchanged
+
+	return result
+}
+
+// diffFindings classifies the current findings against the given
+// unresolved rows, keyed in the project's namespace. Unmatched rows are only
+// reported as resolved when visible returns true (nil allows all).
+func diffFindings(currentFindings []parser.UBSFinding, dbFindings []*db.Finding, project string,
+	renames map[string]string, threshold float64, visible func(*db.Finding) bool) *DiffResult {
 	result := &DiffResult{
 		New:      make([]parser.UBSFinding, 0),
 		Changed:  make([]ChangeRecord, 0),
 		Resolved: make([]*db.Finding, 0),
+		Moved:    make([]MoveRecord, 0),
+
+		Unchanged: make([]ChangeRecord, 0),
 	}
 
 	// Build map of current findings by fingerprint
 	currentMap := make(map[string]parser.UBSFinding)
 	for _, f := range currentFindings {
-		fp := db.ComputeFingerprint(f.File, f.Category, f.Message, f.CodeSnippet, f.Line)
+		fp := db.ProjectFingerprint(project, Fingerprint(f))
 		currentMap[fp] = f
 	}
 
-	// Get all unresolved findings from DB
-	dbFindings, err := d.db.GetUnresolved()
-	if err != nil {
-		return nil, fmt.Errorf("get unresolved findings: %w", err)
-	}
-
-	// Build map of DB findings by fingerprint
+	// Build map of DB findings by fingerprint. Rows keyed on source context
+	// also match scans without it (no --source-root) on their line.
 	dbMap := make(map[string]*db.Finding)
+	lineMap := make(map[string]*db.Finding)
 	for _, f := range dbFindings {
 		dbMap[f.Fingerprint] = f
+		if f.SourceContext != "" && f.CodeSnippet == "" {
+			fp, _ := db.ComputeFingerprintVersion(f.FingerprintVersion, f.File, f.Category, f.Message, "", f.Line)
+			lineMap[db.ProjectFingerprint(project, fp)] = f
+		}
 	}
 
+	// Rows keyed with an older fingerprint algorithm still match until they
+	// are re-keyed, so look each finding up under every version in use
+	versions := db.FingerprintVersions(dbFindings)
+	matched := make(map[string]bool)
+
 	// Find new and changed
 	for fp, current := range currentMap {
-		previous, exists := dbMap[fp]
+		previous, exists := lookupFinding(dbMap, versions, project, fp, current)
+		if !exists && current.CodeSnippet == "" && current.SourceContext == "" {
+			previous, exists = lineMap[fp]
+		}
+		if exists {
+			matched[previous.Fingerprint] = true
+		}
 		if !exists {
 			// New finding
 			result.New = append(result.New, current)
-		} else if previous.Severity != current.Severity {
-			// Severity changed
+		} else if fields := compareFields(previous, current); len(fields) > 0 {
+			// Content drifted (severity, line, ...)
 			result.Changed = append(result.Changed, ChangeRecord{
 				Previous: previous,
 				Current:  current,
+				Fields:   fields,
+			})
+		} else {
+			// Same fingerprint + identical tracked fields = only refresh last_seen
+			result.Unchanged = append(result.Unchanged, ChangeRecord{
+				Previous: previous,
+				Current:  current,
 			})
 		}
-		// Note: Same fingerprint + same severity = no action needed
 	}
 
 	// Find resolved (in DB but not in current scan)
 	for fp, previous := range dbMap {
-		if _, exists := currentMap[fp]; !exists {
+		if !matched[fp] && (visible == nil || visible(previous)) {
 			result.Resolved = append(result.Resolved, previous)
 		}
 	}
 
-	return result, nil
+	// Second pass: pair leftovers that look like the same finding moved
+	moves, remainingNew, remainingResolved := matchMoves(result.New, result.Resolved, renames, threshold)
+	if len(moves) > 0 {
+		for i := range moves {
+			moves[i].Fingerprint = db.ProjectFingerprint(project, moves[i].Fingerprint)
+		}
+		result.Moved = moves
+		result.New = append(make([]parser.UBSFinding, 0, len(remainingNew)), remainingNew...)
+		result.Resolved = append(make([]*db.Finding, 0, len(remainingResolved)), remainingResolved...)
+	}
+
+	return result
+}
+
+// lookupFinding finds the tracked row for a current finding, trying the
+// latest fingerprint first and then each older algorithm version in use
+func lookupFinding(dbMap map[string]*db.Finding, versions []int, project, latestFP string,
+	f parser.UBSFinding) (*db.Finding, bool) {
+	if previous, ok := dbMap[latestFP]; ok {
+		return previous, true
+	}
+
+	for _, v := range versions {
+		if v == db.FingerprintVersion {
+			continue
+		}
+		fp, err := db.ComputeFingerprintContext(v, f.File, f.Category, f.Message, f.CodeSnippet, f.SourceContext, f.Line)
+		if err != nil {
+			continue
+		}
+		if previous, ok := dbMap[db.ProjectFingerprint(project, fp)]; ok && previous.FingerprintVersion == v {
+			return previous, true
+		}
+	}
+
+	// Rows keyed before source context was loaded (see keyedWithoutContext)
+	if f.SourceContext != "" && f.CodeSnippet == "" {
+		fp := db.ComputeFingerprint(f.File, f.Category, f.Message, "", f.Line)
+		if previous, ok := dbMap[db.ProjectFingerprint(project, fp)]; ok && previous.SourceContext == "" {
+			return previous, true
+		}
+	}
+
+	return nil, false
+}
+
+// Fingerprint computes a current finding's fingerprint with the latest
+// algorithm, outside any project namespace
+func Fingerprint(f parser.UBSFinding) string {
+	fp, _ := db.ComputeFingerprintContext(db.FingerprintVersion, f.File, f.Category, f.Message, f.CodeSnippet,
+		f.SourceContext, f.Line)
+	return fp
+}
+
+// compareFields returns every tracked field that differs between the stored
+// finding and the current scan. Fingerprints only cover a subset of these
+// (e.g. line is ignored when a code snippet is available), so a matching
+// fingerprint does not imply the issue text is still accurate.
+func compareFields(previous *db.Finding, current parser.UBSFinding) []FieldChange {
+	var fields []FieldChange
+	add := func(field, prev, cur string) {
+		if prev != cur {
+			fields = append(fields, FieldChange{Field: field, Previous: prev, Current: cur})
+		}
+	}
+
+	add("severity", previous.Severity, current.Severity)
+	add("file", previous.File, current.File)
+	add("line", strconv.Itoa(previous.Line), strconv.Itoa(current.Line))
+	add("category", previous.Category, current.Category)
+	add("message", previous.Message, current.Message)
+
+	// Rows stored before payload tracking have no suggestion/snippet to
+	// compare against; they are backfilled instead of reported as drift
+	if previous.HasPayload() {
+		fields = append(fields, comparePayload(previous, current)...)
+	}
+
+	return fields
+}
+
+// comparePayload returns the suggestion and code snippet drift
+func comparePayload(previous *db.Finding, current parser.UBSFinding) []FieldChange {
+	var fields []FieldChange
+	if previous.Suggestion != current.Suggestion {
+		fields = append(fields, FieldChange{Field: "suggestion", Previous: previous.Suggestion, Current: current.Suggestion})
+	}
+	if previous.CodeSnippet != current.CodeSnippet {
+		fields = append(fields, FieldChange{Field: "code_snippet", Previous: previous.CodeSnippet, Current: current.CodeSnippet})
+	}
+	return fields
 }
 
 // Stats returns summary string
 func (dr *DiffResult) Stats() string {
-	return fmt.Sprintf("New: %d, Changed: %d, Resolved: %d",
+	stats := fmt.Sprintf("New: %d, Changed: %d, Resolved: %d",
 		len(dr.New), len(dr.Changed), len(dr.Resolved))
+	if len(dr.Moved) > 0 {
+		stats += fmt.Sprintf(", Moved: %d", len(dr.Moved))
+	}
+	if len(dr.FixedOnBranch) > 0 {
+		stats += fmt.Sprintf(", Fixed on branch: %d", len(dr.FixedOnBranch))
+	}
+	return stats
+}
+
+// Observations returns the refresh records for findings that need no issue
+// tracker action, stamped with the given scan ID and time
+func (dr *DiffResult) Observations(scanID string, seenAt time.Time) []db.Observation {
+	obs := make([]db.Observation, 0, len(dr.Unchanged))
+	for _, u := range dr.Unchanged {
+		obs = append(obs, db.Observation{
+			Fingerprint: u.Previous.Fingerprint,
+			Line:        u.Current.Line,
+			Column:      u.Current.Column,
+			Severity:    u.Current.Severity,
+			ScanID:      scanID,
+			SeenAt:      seenAt,
+		})
+	}
+	return obs
+}
+
+// OutdatedFingerprints counts matched or resolved rows keyed with an older
+// fingerprint algorithm than db.FingerprintVersion, or keyed on the line
+// although the scan now has source context for them
+func (dr *DiffResult) OutdatedFingerprints() int {
+	n := 0
+	outdated := func(f *db.Finding) {
+		if f.FingerprintVersion != 0 && f.FingerprintVersion < db.FingerprintVersion {
+			n++
+		}
+	}
+	matched := func(c ChangeRecord) {
+		if f := c.Previous; f.FingerprintVersion >= db.FingerprintVersion && keyedWithoutContext(f, c.Current) {
+			n++
+		} else {
+			outdated(f)
+		}
+	}
+	for _, c := range dr.Changed {
+		matched(c)
+	}
+	for _, u := range dr.Unchanged {
+		matched(u)
+	}
+	for _, r := range dr.Resolved {
+		outdated(r)
+	}
+	for _, m := range dr.Moved {
+		outdated(m.Previous)
+	}
+	return n
+}
+
+// keyedWithoutContext reports whether a row was keyed on the line of a
+// finding the scan now has source context for. It keeps matching, but only
+// "strung fingerprint migrate --source-root" re-keys it on the context.
+func keyedWithoutContext(previous *db.Finding, current parser.UBSFinding) bool {
+	return current.CodeSnippet == "" && current.SourceContext != "" &&
+		previous.CodeSnippet == "" && previous.SourceContext == ""
 }
 
 // IsEmpty returns true if no changes detected
 func (dr *DiffResult) IsEmpty() bool {
-	return len(dr.New) == 0 && len(dr.Changed) == 0 && len(dr.Resolved) == 0
+	return len(dr.New) == 0 && len(dr.Changed) == 0 && len(dr.Resolved) == 0 && len(dr.Moved) == 0 &&
+		len(dr.FixedOnBranch) == 0
 }
 
 // TotalActions returns total number of actions needed
 func (dr *DiffResult) TotalActions() int {
-	return len(dr.New) + len(dr.Changed) + len(dr.Resolved)
+	return len(dr.New) + len(dr.Changed) + len(dr.Resolved) + len(dr.Moved)
 }
@@ -81,6 +81,97 @@ func TestDiffer_ChangedSeverity(t *testing.T) {
 	if change.Current.Severity != "critical" {
 		t.Errorf("Current severity wrong: %s", change.Current.Severity)
 	}
+	if len(change.Fields) != 1 || change.Fields[0].Field != "severity" {
+		t.Errorf("Expected only severity to change, got %v", change.Fields)
+	}
+}
+
+func TestDiffer_ChangedLine(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	now := time.Now()
+
+	// Snippet-based fingerprint survives the line moving
+	fp := db.ComputeFingerprint("test.ts", "null-safety", "msg", "return x.y;", 42)
+	database.Store(&db.Finding{
+		Fingerprint: fp, IssueID: "test-001",
+		File: "test.ts", Line: 42, Severity: "warning",
+		Category: "null-safety", Message: "msg",
+		FirstSeen: now, LastSeen: now,
+	})
+
+	currentFindings := []parser.UBSFinding{
+		{File: "test.ts", Line: 50, Severity: "warning", Category: "null-safety",
+			Message: "msg", CodeSnippet: "return x.y;"},
+	}
+
+	differ := NewDiffer(database)
+	result, err := differ.Diff(currentFindings)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	if len(result.New) != 0 || len(result.Resolved) != 0 {
+		t.Fatalf("Line move should not churn: %s", result.Stats())
+	}
+	if len(result.Changed) != 1 {
+		t.Fatalf("Expected 1 changed, got %d", len(result.Changed))
+	}
+
+	change := result.Changed[0]
+	if !change.HasField("line") || change.HasField("severity") {
+		t.Errorf("Expected only line to change, got %v", change.Fields)
+	}
+	if got := change.Fields[0].String(); got != "line 42 → 50" {
+		t.Errorf("FieldChange string wrong: %s", got)
+	}
+}
+
+func TestDiffer_ChangedPayload(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	now := time.Now()
+
+	fp := db.ComputeFingerprint("test.ts", "x", "msg", "", 1)
+	database.Store(&db.Finding{
+		Fingerprint: fp, IssueID: "test-001",
+		File: "test.ts", Line: 1, Severity: "warning",
+		Category: "x", Message: "msg", Suggestion: "Old advice",
+		FirstSeen: now, LastSeen: now, IssueHash: "hash",
+	})
+
+	legacyFp := db.ComputeFingerprint("legacy.ts", "x", "msg", "", 1)
+	database.Store(&db.Finding{
+		Fingerprint: legacyFp, IssueID: "test-002",
+		File: "legacy.ts", Line: 1, Severity: "warning",
+		Category: "x", Message: "msg",
+		FirstSeen: now, LastSeen: now,
+	})
+
+	currentFindings := []parser.UBSFinding{
+		{File: "test.ts", Line: 1, Severity: "warning", Category: "x", Message: "msg", Suggestion: "New advice"},
+		{File: "legacy.ts", Line: 1, Severity: "warning", Category: "x", Message: "msg", Suggestion: "Advice"},
+	}
+
+	differ := NewDiffer(database)
+	result, err := differ.Diff(currentFindings)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	if len(result.Changed) != 1 || !result.Changed[0].HasField("suggestion") {
+		t.Fatalf("Expected suggestion drift on tracked payload, got %+v", result.Changed)
+	}
+	if result.Changed[0].Previous.IssueID != "test-001" {
+		t.Errorf("Wrong finding changed: %s", result.Changed[0].Previous.IssueID)
+	}
+
+	// Rows without a stored payload are not reported as drift
+	if len(result.Unchanged) != 1 || result.Unchanged[0].Previous.IssueID != "test-002" {
+		t.Errorf("Legacy row should be unchanged: %+v", result.Unchanged)
+	}
 }
 
 func TestDiffer_ResolvedFindings(t *testing.T) {
@@ -195,6 +286,140 @@ func TestDiffer_NoChanges(t *testing.T) {
 	if !result.IsEmpty() {
 		t.Errorf("Expected empty result, got: %s", result.Stats())
 	}
+	if len(result.Unchanged) != 1 {
+		t.Fatalf("Expected 1 unchanged, got %d", len(result.Unchanged))
+	}
+
+	obs := result.Observations("scan-1", now)
+	if len(obs) != 1 || obs[0].Fingerprint != fp || obs[0].ScanID != "scan-1" {
+		t.Errorf("Observations wrong: %+v", obs)
+	}
+}
+
+func TestDiffer_OlderFingerprintVersion(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	now := time.Now()
+
+	// Row keyed with V1 over a raw path; V2 would hash it differently
+	fp, _ := db.ComputeFingerprintVersion(db.FingerprintV1, "./src/a.ts", "x", "msg", "", 1)
+	database.Store(&db.Finding{
+		Fingerprint: fp, IssueID: "test-001",
+		File: "./src/a.ts", Line: 1, Severity: "warning",
+		Category: "x", Message: "msg",
+		FirstSeen: now, LastSeen: now, FingerprintVersion: db.FingerprintV1,
+	})
+
+	currentFindings := []parser.UBSFinding{
+		{File: "./src/a.ts", Line: 1, Severity: "warning", Category: "x", Message: "msg"},
+	}
+
+	differ := NewDiffer(database)
+	result, err := differ.Diff(currentFindings)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	if len(result.New) != 0 || len(result.Resolved) != 0 {
+		t.Fatalf("Older fingerprint version should still match: %s", result.Stats())
+	}
+	if result.OutdatedFingerprints() != 1 {
+		t.Errorf("Expected 1 outdated fingerprint, got %d", result.OutdatedFingerprints())
+	}
+}
+
+func TestDiffer_BranchView(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	now := time.Now()
+	store := func(issueID, file, message string) *db.Finding {
+		f := &db.Finding{
+			Fingerprint: db.ComputeFingerprint(file, "test", message, "", 1),
+			IssueID:     issueID,
+			File:        file,
+			Line:        1,
+			Severity:    "warning",
+			Category:    "test",
+			Message:     message,
+			FirstSeen:   now,
+			LastSeen:    now,
+		}
+		if err := database.Store(f); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+		return f
+	}
+
+	// Base branch findings
+	store("base-1", "kept.ts", "Kept")
+	store("base-2", "fixed.ts", "Fixed")
+	// Findings of the feature branch and of another branch
+	database.SetBranch("other")
+	store("other-1", "other.ts", "Other")
+	database.SetBranch("feature")
+	store("feat-1", "gone.ts", "Gone")
+
+	current := []parser.UBSFinding{
+		// Inherited, with drifted severity: not updated on a branch
+		{File: "kept.ts", Line: 1, Severity: "critical", Category: "test", Message: "Kept"},
+		{File: "new.ts", Line: 1, Severity: "warning", Category: "test", Message: "New"},
+	}
+
+	result, err := NewDiffer(database).Diff(current)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	if len(result.New) != 1 || result.New[0].File != "new.ts" {
+		t.Errorf("New = %v, want new.ts", result.New)
+	}
+	if len(result.Changed) != 0 {
+		t.Errorf("Changed = %v, want none for inherited findings", result.Changed)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0].Previous.IssueID != "base-1" {
+		t.Errorf("Unchanged = %v, want base-1", result.Unchanged)
+	}
+	if len(result.Resolved) != 1 || result.Resolved[0].IssueID != "feat-1" {
+		t.Errorf("Resolved = %v, want only the branch's own feat-1", result.Resolved)
+	}
+	if len(result.FixedOnBranch) != 1 || result.FixedOnBranch[0].IssueID != "base-2" {
+		t.Errorf("FixedOnBranch = %v, want base-2", result.FixedOnBranch)
+	}
+}
+
+func TestDiffer_Project(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	now := time.Now()
+	store := func(project, issueID string) {
+		database.SetProject(project)
+		if err := database.Store(&db.Finding{
+			Fingerprint: database.Fingerprint("main.go", "test", "Same", "", "", 1),
+			IssueID:     issueID, File: "main.go", Line: 1, Severity: "warning", Category: "test",
+			Message: "Same", FirstSeen: now, LastSeen: now,
+		}); err != nil {
+			t.Fatalf("Store failed: %v", err)
+		}
+	}
+	store("api", "api-1")
+	store("web", "web-1")
+
+	// The same finding in another project is not matched or resolved
+	database.SetProject("api")
+	current := []parser.UBSFinding{{File: "main.go", Line: 1, Severity: "warning", Category: "test", Message: "Same"}}
+	result, err := NewDiffer(database).Diff(current)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.New) != 0 || len(result.Resolved) != 0 {
+		t.Errorf("New = %v, Resolved = %v, want none", result.New, result.Resolved)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0].Previous.IssueID != "api-1" {
+		t.Errorf("Unchanged = %v, want api-1", result.Unchanged)
+	}
 }
 
 func TestDiffResult_Stats(t *testing.T) {
@@ -228,3 +453,93 @@ func setupTestDB(t *testing.T) *db.TrackingDB {
 
 	return database
 }
+
+func TestDiffScans(t *testing.T) {
+	base := []parser.UBSFinding{
+		{File: "a.go", Line: 10, Severity: "warning", Category: "null", Message: "kept", Suggestion: "check nil"},
+		{File: "a.go", Line: 20, Severity: "warning", Category: "leak", Message: "escalated"},
+		{File: "a.go", Line: 30, Severity: "info", Category: "style", Message: "fixed"},
+		{File: "old/b.go", Line: 5, Severity: "critical", Category: "sql", Message: "query built from input"},
+	}
+	head := []parser.UBSFinding{
+		{File: "a.go", Line: 10, Severity: "warning", Category: "null", Message: "kept", Suggestion: "check nil"},
+		{File: "a.go", Line: 20, Severity: "critical", Category: "leak", Message: "escalated"},
+		{File: "a.go", Line: 40, Severity: "critical", Category: "xss", Message: "introduced"},
+		{File: "new/b.go", Line: 5, Severity: "critical", Category: "sql", Message: "query built from input"},
+	}
+
+	result := DiffScans(base, head, map[string]string{"old/b.go": "new/b.go"}, DefaultMatchThreshold)
+
+	if len(result.New) != 1 || result.New[0].Message != "introduced" {
+		t.Errorf("New = %+v", result.New)
+	}
+	if len(result.Resolved) != 1 || result.Resolved[0].Message != "fixed" {
+		t.Errorf("Resolved = %+v", result.Resolved)
+	}
+	if len(result.Changed) != 1 || !result.Changed[0].HasField("severity") {
+		t.Errorf("Changed = %+v", result.Changed)
+	}
+	if len(result.Moved) != 1 || !result.Moved[0].Renamed {
+		t.Errorf("Moved = %+v", result.Moved)
+	}
+	if len(result.Unchanged) != 1 {
+		t.Errorf("Unchanged = %+v", result.Unchanged)
+	}
+
+	// Suggestion drift counts even though base rows have no issue hash
+	head[0].Suggestion = "use a guard clause"
+	result = DiffScans(base, head, nil, DefaultMatchThreshold)
+	if len(result.Changed) != 2 || len(result.Unchanged) != 0 {
+		t.Errorf("Changed = %d, Unchanged = %d, want 2 and 0", len(result.Changed), len(result.Unchanged))
+	}
+}
+
+func TestDiffer_SourceContext(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	// Tracked before --source-root: keyed on the line
+	storeFinding(t, database, "test-001", parser.UBSFinding{
+		File: "a.go", Line: 10, Severity: "warning", Category: "errors", Message: "error ignored",
+	})
+
+	current := []parser.UBSFinding{
+		{File: "a.go", Line: 10, Severity: "warning", Category: "errors", Message: "error ignored",
+			SourceContext: "f, _ := os.Open(p)\n}"},
+		// Same file, category and message on another line: the windows differ
+		{File: "a.go", Line: 20, Severity: "warning", Category: "errors", Message: "error ignored",
+			SourceContext: "_ = w.Close()\n}"},
+	}
+
+	result, err := NewDiffer(database).Diff(current)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.Unchanged) != 1 || len(result.New) != 1 || len(result.Moved) != 0 || len(result.Resolved) != 0 {
+		t.Fatalf("Expected the line-keyed row to keep matching, got %s", result.Stats())
+	}
+	if result.Unchanged[0].Previous.IssueID != "test-001" || result.New[0].Line != 20 {
+		t.Errorf("Unexpected match: %+v / %+v", result.Unchanged[0], result.New[0])
+	}
+	if n := result.OutdatedFingerprints(); n != 1 {
+		t.Errorf("OutdatedFingerprints = %d, want 1 (keyed without context)", n)
+	}
+
+	// Rows keyed on context still match a scan without --source-root
+	keyed := current[1]
+	if err := database.Store(&db.Finding{
+		Fingerprint: Fingerprint(keyed), IssueID: "test-002", File: keyed.File, Line: keyed.Line,
+		Severity: keyed.Severity, Category: keyed.Category, Message: keyed.Message,
+		SourceContext: keyed.SourceContext, FirstSeen: time.Now(), LastSeen: time.Now(),
+	}); err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+	keyed.SourceContext = ""
+	result, err = NewDiffer(database).Diff([]parser.UBSFinding{keyed})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.Unchanged) != 1 || result.Unchanged[0].Previous.IssueID != "test-002" {
+		t.Errorf("Expected the context-keyed row to match on its line, got %s", result.Stats())
+	}
+}
@@ -0,0 +1,214 @@
+package sync
+
+import (
+	"math"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+// DefaultMatchThreshold is the minimum similarity for pairing a new finding
+// with a resolved one as a move
+const DefaultMatchThreshold = 0.7
+
+// lineWindow is the line distance beyond which line proximity scores zero.
+// Within the same file, a pair further apart only matches on its snippet.
+const lineWindow = 50
+
+// snippetFloor is the snippet similarity a same-file pair further apart than
+// lineWindow needs to match
+const snippetFloor = 0.5
+
+// Similarity weights. Snippet similarity only counts when both sides have a
+// snippet; scores are normalized by the weights that apply.
+const (
+	weightPath    = 0.30
+	weightMessage = 0.35
+	weightSnippet = 0.20
+	weightLine    = 0.15
+)
+
+// MoveRecord pairs a tracked finding whose fingerprint disappeared with a
+// new finding that is most likely the same problem after a file rename,
+// line shift or message rewording
+type MoveRecord struct {
+	Previous    *db.Finding
+	Current     parser.UBSFinding
+	Fingerprint string        // Fingerprint of Current under db.FingerprintVersion
+	Score       float64       // Similarity in [0, 1]
+	Renamed     bool          // Paths paired through git rename detection
+	Fields      []FieldChange // Tracked fields that differ between Previous and Current
+}
+
+// Displaced reports whether the move may be a different finding that took
+// the place of a fixed one: its code snippet changed, or without snippets on
+// both sides, it changed files without a detected rename. Without snippets a
+// line shift within the file cannot be told apart from a swap.
+func (m MoveRecord) Displaced() bool {
+	if m.Previous.CodeSnippet != "" && m.Current.CodeSnippet != "" {
+		return lineSetSimilarity(m.Previous.CodeSnippet, m.Current.CodeSnippet) < snippetFloor
+	}
+	return !m.Renamed && m.Previous.File != m.Current.File
+}
+
+type matchCandidate struct {
+	prev, cur int
+	score     float64
+	renamed   bool
+}
+
+// matchMoves pairs otherwise-new findings with otherwise-resolved ones.
+// Pairs are assigned greedily by descending score; each finding is used at
+// most once. It returns the moves plus the findings left unpaired.
+func matchMoves(newFindings []parser.UBSFinding, resolved []*db.Finding, renames map[string]string,
+	threshold float64) ([]MoveRecord, []parser.UBSFinding, []*db.Finding) {
+	if threshold <= 0 || len(newFindings) == 0 || len(resolved) == 0 {
+		return nil, newFindings, resolved
+	}
+
+	var candidates []matchCandidate
+	for pi, prev := range resolved {
+		for ci, cur := range newFindings {
+			score, renamed := similarity(prev, cur, renames)
+			if score >= threshold {
+				candidates = append(candidates, matchCandidate{prev: pi, cur: ci, score: score, renamed: renamed})
+			}
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].score > candidates[j].score
+	})
+
+	usedPrev := make(map[int]bool)
+	usedCur := make(map[int]bool)
+	var moves []MoveRecord
+	for _, c := range candidates {
+		if usedPrev[c.prev] || usedCur[c.cur] {
+			continue
+		}
+		usedPrev[c.prev] = true
+		usedCur[c.cur] = true
+
+		prev, cur := resolved[c.prev], newFindings[c.cur]
+		moves = append(moves, MoveRecord{
+			Previous:    prev,
+			Current:     cur,
+			Fingerprint: Fingerprint(cur),
+			Score:       c.score,
+			Renamed:     c.renamed,
+			Fields:      compareFields(prev, cur),
+		})
+	}
+
+	var remainingNew []parser.UBSFinding
+	for i, f := range newFindings {
+		if !usedCur[i] {
+			remainingNew = append(remainingNew, f)
+		}
+	}
+	var remainingResolved []*db.Finding
+	for i, f := range resolved {
+		if !usedPrev[i] {
+			remainingResolved = append(remainingResolved, f)
+		}
+	}
+
+	return moves, remainingNew, remainingResolved
+}
+
+// similarity scores how likely cur is prev after a move. Findings in
+// different categories never match, nor do findings in the same file more
+// than lineWindow lines apart unless their snippets are alike: fixing one
+// occurrence while adding another elsewhere in the file is not a move.
+func similarity(prev *db.Finding, cur parser.UBSFinding, renames map[string]string) (float64, bool) {
+	if prev.Category != cur.Category {
+		return 0, false
+	}
+
+	pathScore, renamed := pathSimilarity(prev.File, cur.File, renames)
+
+	total := weightPath*pathScore + weightMessage*tokenSimilarity(prev.Message, cur.Message)
+	weights := weightPath + weightMessage
+
+	snippetScore := 0.0
+	if prev.CodeSnippet != "" && cur.CodeSnippet != "" {
+		snippetScore = lineSetSimilarity(prev.CodeSnippet, cur.CodeSnippet)
+		total += weightSnippet * snippetScore
+		weights += weightSnippet
+	}
+
+	// Line numbers are only comparable within the same (or renamed) file
+	lineScore := 0.0
+	if pathScore == 1 {
+		delta := math.Abs(float64(prev.Line - cur.Line))
+		if delta > lineWindow && snippetScore < snippetFloor {
+			return 0, false
+		}
+		lineScore = math.Max(0, 1-delta/lineWindow)
+	}
+	total += weightLine * lineScore
+	weights += weightLine
+
+	return total / weights, renamed
+}
+
+// pathSimilarity is 1 for the same or a git-renamed path, 0.6 for the same
+// file name in another directory, 0.3 for another file in the same directory
+func pathSimilarity(prevPath, curPath string, renames map[string]string) (float64, bool) {
+	switch {
+	case prevPath == curPath:
+		return 1, false
+	case renames[prevPath] == curPath:
+		return 1, true
+	case path.Base(prevPath) == path.Base(curPath):
+		return 0.6, false
+	case path.Dir(prevPath) == path.Dir(curPath):
+		return 0.3, false
+	}
+	return 0, false
+}
+
+// tokenSimilarity is the Jaccard index of lower-cased word sets
+func tokenSimilarity(a, b string) float64 {
+	return jaccard(strings.Fields(strings.ToLower(a)), strings.Fields(strings.ToLower(b)))
+}
+
+// lineSetSimilarity is the Jaccard index of whitespace-normalized lines
+func lineSetSimilarity(a, b string) float64 {
+	normalize := func(s string) []string {
+		var lines []string
+		for _, l := range strings.Split(s, "\n") {
+			if l = strings.Join(strings.Fields(l), " "); l != "" {
+				lines = append(lines, l)
+			}
+		}
+		return lines
+	}
+	return jaccard(normalize(a), normalize(b))
+}
+
+func jaccard(a, b []string) float64 {
+	if len(a) == 0 && len(b) == 0 {
+		return 1
+	}
+
+	set := make(map[string]int)
+	for _, s := range a {
+		set[s] |= 1
+	}
+	for _, s := range b {
+		set[s] |= 2
+	}
+
+	both := 0
+	for _, v := range set {
+		if v == 3 {
+			both++
+		}
+	}
+	return float64(both) / float64(len(set))
+}
@@ -0,0 +1,209 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+func storeFinding(t *testing.T, database *db.TrackingDB, issueID string, f parser.UBSFinding) {
+	t.Helper()
+	now := time.Now()
+	err := database.Store(&db.Finding{
+		Fingerprint: db.ComputeFingerprint(f.File, f.Category, f.Message, f.CodeSnippet, f.Line),
+		IssueID:     issueID,
+		File:        f.File,
+		Line:        f.Line,
+		Severity:    f.Severity,
+		Category:    f.Category,
+		Message:     f.Message,
+		CodeSnippet: f.CodeSnippet,
+		FirstSeen:   now,
+		LastSeen:    now,
+	})
+	if err != nil {
+		t.Fatalf("Store failed: %v", err)
+	}
+}
+
+func TestDiffer_MovedRenamedFile(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	storeFinding(t, database, "test-001", parser.UBSFinding{
+		File: "src/old.ts", Line: 10, Severity: "warning", Category: "null-safety",
+		Message: "Possible null dereference", CodeSnippet: "const x = obj.prop;",
+	})
+
+	current := []parser.UBSFinding{{
+		File: "src/new.ts", Line: 10, Severity: "warning", Category: "null-safety",
+		Message: "Possible null dereference", CodeSnippet: "const x = obj.prop;",
+	}}
+
+	differ := NewDiffer(database)
+	differ.Renames = map[string]string{"src/old.ts": "src/new.ts"}
+	result, err := differ.Diff(current)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+
+	if len(result.Moved) != 1 || len(result.New) != 0 || len(result.Resolved) != 0 {
+		t.Fatalf("Expected 1 move, got %s", result.Stats())
+	}
+	move := result.Moved[0]
+	if move.Previous.IssueID != "test-001" || !move.Renamed || move.Displaced() {
+		t.Errorf("Unexpected move: %+v", move)
+	}
+	if move.Fingerprint != db.ComputeFingerprint("src/new.ts", "null-safety", "Possible null dereference", "const x = obj.prop;", 10) {
+		t.Error("Move should carry the new fingerprint")
+	}
+}
+
+func TestDiffer_MovedRewordedMessage(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	storeFinding(t, database, "test-001", parser.UBSFinding{
+		File: "app.ts", Line: 40, Severity: "warning", Category: "leak",
+		Message: "Event listener is never removed from window",
+	})
+
+	current := []parser.UBSFinding{{
+		File: "app.ts", Line: 44, Severity: "warning", Category: "leak",
+		Message: "Event listener is never removed from the window",
+	}}
+
+	result, err := NewDiffer(database).Diff(current)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.Moved) != 1 {
+		t.Fatalf("Expected 1 move, got %s", result.Stats())
+	}
+	if !hasFieldChange(result.Moved[0].Fields, "message") || !hasFieldChange(result.Moved[0].Fields, "line") {
+		t.Errorf("Expected message and line drift, got %v", result.Moved[0].Fields)
+	}
+}
+
+func TestDiffer_MoveOntoResolvedFingerprint(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	storeFinding(t, database, "test-001", parser.UBSFinding{
+		File: "app.ts", Line: 40, Severity: "warning", Category: "leak",
+		Message: "Event listener is never removed from window",
+	})
+	// A resolved row already holds the current finding's fingerprint
+	current := parser.UBSFinding{
+		File: "app.ts", Line: 44, Severity: "warning", Category: "leak",
+		Message: "Event listener is never removed from the window",
+	}
+	storeFinding(t, database, "test-002", current)
+	fp := db.ComputeFingerprint(current.File, current.Category, current.Message, current.CodeSnippet, current.Line)
+	if err := database.MarkResolved(fp, time.Now()); err != nil {
+		t.Fatalf("MarkResolved failed: %v", err)
+	}
+
+	result, err := NewDiffer(database).Diff([]parser.UBSFinding{current})
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.Moved) != 0 || len(result.New) != 1 || len(result.Resolved) != 1 {
+		t.Errorf("Expected new + resolved instead of a move onto a taken fingerprint, got %s", result.Stats())
+	}
+}
+
+func TestDiffer_MatchingDisabled(t *testing.T) {
+	database := setupTestDB(t)
+	defer database.Close()
+
+	storeFinding(t, database, "test-001", parser.UBSFinding{
+		File: "app.ts", Line: 40, Severity: "warning", Category: "leak",
+		Message: "Event listener is never removed from window",
+	})
+
+	current := []parser.UBSFinding{{
+		File: "app.ts", Line: 44, Severity: "warning", Category: "leak",
+		Message: "Event listener is never removed from the window",
+	}}
+
+	differ := NewDiffer(database)
+	differ.MatchThreshold = 0
+	result, err := differ.Diff(current)
+	if err != nil {
+		t.Fatalf("Diff failed: %v", err)
+	}
+	if len(result.Moved) != 0 || len(result.New) != 1 || len(result.Resolved) != 1 {
+		t.Errorf("Expected new + resolved with matching disabled, got %s", result.Stats())
+	}
+}
+
+func TestMatchMoves_Unrelated(t *testing.T) {
+	resolved := []*db.Finding{
+		{File: "a.ts", Line: 1, Category: "leak", Message: "Timer never cleared"},
+		{File: "b.ts", Line: 5, Category: "null-safety", Message: "Possible null dereference"},
+	}
+	newFindings := []parser.UBSFinding{
+		{File: "c/d.ts", Line: 300, Category: "leak", Message: "Unclosed file handle"},
+		{File: "b.ts", Line: 5, Category: "security", Message: "Possible null dereference"},
+	}
+
+	moves, remainingNew, remainingResolved := matchMoves(newFindings, resolved, nil, DefaultMatchThreshold)
+	if len(moves) != 0 {
+		t.Errorf("Expected no moves, got %+v", moves)
+	}
+	if len(remainingNew) != 2 || len(remainingResolved) != 2 {
+		t.Error("Unmatched findings should be returned unchanged")
+	}
+}
+
+func TestMatchMoves_BestPairWins(t *testing.T) {
+	resolved := []*db.Finding{
+		{File: "a.ts", Line: 10, Category: "leak", Message: "Timer never cleared"},
+	}
+	newFindings := []parser.UBSFinding{
+		{File: "a.ts", Line: 40, Category: "leak", Message: "Timer never cleared"},
+		{File: "a.ts", Line: 12, Category: "leak", Message: "Timer never cleared"},
+	}
+
+	moves, remainingNew, _ := matchMoves(newFindings, resolved, nil, DefaultMatchThreshold)
+	if len(moves) != 1 || moves[0].Current.Line != 12 {
+		t.Fatalf("Expected the closer finding to match, got %+v", moves)
+	}
+	if len(remainingNew) != 1 || remainingNew[0].Line != 40 {
+		t.Errorf("Expected the farther finding to stay new, got %+v", remainingNew)
+	}
+}
+
+func TestMatchMoves_FarInSameFile(t *testing.T) {
+	resolved := []*db.Finding{
+		{File: "a.ts", Line: 10, Category: "leak", Message: "Timer never cleared"},
+		{File: "b.ts", Line: 10, Category: "leak", Message: "Timer never cleared", CodeSnippet: "setInterval(tick, 1000);"},
+	}
+	newFindings := []parser.UBSFinding{
+		// Another occurrence far away, not the fixed one moved
+		{File: "a.ts", Line: 400, Category: "leak", Message: "Timer never cleared"},
+		// The same code moved far down the file
+		{File: "b.ts", Line: 400, Category: "leak", Message: "Timer never cleared", CodeSnippet: "setInterval(tick, 1000);"},
+	}
+
+	moves, remainingNew, remainingResolved := matchMoves(newFindings, resolved, nil, DefaultMatchThreshold)
+	if len(moves) != 1 || moves[0].Current.File != "b.ts" {
+		t.Fatalf("Expected only the snippet-backed pair to match, got %+v", moves)
+	}
+	if len(remainingNew) != 1 || remainingNew[0].File != "a.ts" || len(remainingResolved) != 1 {
+		t.Errorf("Expected the far pair without snippets to stay new + resolved, got %+v / %+v",
+			remainingNew, remainingResolved)
+	}
+}
+
+func hasFieldChange(fields []FieldChange, name string) bool {
+	for _, fc := range fields {
+		if fc.Field == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,155 @@
+package sync
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/TheEditor/strung/pkg/beads"
+	"github.com/TheEditor/strung/pkg/db"
+)
+
+// IssueState is an issue's state as read back from the issue tracker. A
+// zero Status means the issue was not found.
+type IssueState struct {
+	Status      string
+	CloseReason string
+}
+
+// Closed reports whether the issue was closed in the tracker
+func (s IssueState) Closed() bool {
+	return s.Status == beads.StatusClosed
+}
+
+// Conflict is tracker state sync cannot reconcile on its own
+type Conflict struct {
+	Finding *db.Finding
+	Reason  string
+}
+
+// String returns "issue: reason"
+func (c Conflict) String() string {
+	return fmt.Sprintf("%s: %s", c.Finding.IssueID, c.Reason)
+}
+
+// Reconciliation brings tracked findings in line with their issues' state
+type Reconciliation struct {
+	// Closed lists findings still present whose issue was closed in the
+	// tracker since the last sync, e.g. as won't-fix
+	Closed []*db.Finding
+
+	// Reopened lists suppressed findings whose issue was reopened in the
+	// tracker; sync manages them again
+	Reopened []*db.Finding
+
+	// AlreadyClosed lists findings missing from the scan whose issue is
+	// already closed, so only the database needs resolving
+	AlreadyClosed []*db.Finding
+
+	Conflicts []Conflict
+
+	// Skip holds fingerprints whose issues sync must not write: closed,
+	// suppressed or missing issues
+	Skip map[string]bool
+}
+
+// Tracked returns the tracked findings the diff matched or resolved
+func (dr *DiffResult) Tracked() []*db.Finding {
+	var tracked []*db.Finding
+	for _, u := range dr.Unchanged {
+		tracked = append(tracked, u.Previous)
+	}
+	for _, c := range dr.Changed {
+		tracked = append(tracked, c.Previous)
+	}
+	for _, m := range dr.Moved {
+		tracked = append(tracked, m.Previous)
+	}
+	return append(tracked, dr.Resolved...)
+}
+
+// Reconcile compares the diff with issue states keyed by issue ID. Findings
+// whose issue was not read are left alone. AlreadyClosed findings are
+// removed from result.Resolved, as there is nothing left to close.
+func Reconcile(result *DiffResult, states map[string]IssueState) *Reconciliation {
+	rec := &Reconciliation{Skip: make(map[string]bool)}
+
+	present := func(f *db.Finding, fields []FieldChange) {
+		state, ok := states[f.IssueID]
+		switch {
+		case !ok:
+		case state.Status == "":
+			rec.Conflicts = append(rec.Conflicts, Conflict{f, "issue not found, finding still present"})
+			rec.Skip[f.Fingerprint] = true
+		case state.Closed():
+			if !f.Suppressed() {
+				rec.Closed = append(rec.Closed, f)
+			} else if len(fields) > 0 {
+				rec.Conflicts = append(rec.Conflicts, Conflict{f,
+					"issue closed, finding changed (" + joinFields(fields) + ")"})
+			}
+			rec.Skip[f.Fingerprint] = true
+		case f.Suppressed():
+			rec.Reopened = append(rec.Reopened, f)
+		}
+	}
+	for _, u := range result.Unchanged {
+		present(u.Previous, nil)
+	}
+	for _, c := range result.Changed {
+		present(c.Previous, c.Fields)
+	}
+	for _, m := range result.Moved {
+		present(m.Previous, []FieldChange{{Field: "location",
+			Previous: fmt.Sprintf("%s:%d", m.Previous.File, m.Previous.Line),
+			Current:  fmt.Sprintf("%s:%d", m.Current.File, m.Current.Line)}})
+	}
+
+	open := result.Resolved[:0]
+	for _, f := range result.Resolved {
+		state, ok := states[f.IssueID]
+		switch {
+		case !ok:
+		case state.Status == "":
+			rec.Conflicts = append(rec.Conflicts, Conflict{f, "issue not found, finding resolved"})
+			rec.Skip[f.Fingerprint] = true
+		case state.Closed():
+			rec.AlreadyClosed = append(rec.AlreadyClosed, f)
+			continue
+		}
+		open = append(open, f)
+	}
+	result.Resolved = open
+
+	return rec
+}
+
+// StoredSkip returns the fingerprints whose issues sync must not write as
+// far as the database knows: suppressed findings and findings whose issue
+// was last read as closed. It stands in for Reconcile's Skip when the
+// tracker is not read.
+func StoredSkip(findings []*db.Finding) map[string]bool {
+	skip := make(map[string]bool)
+	for _, f := range findings {
+		if f.Suppressed() || f.IssueStatus == beads.StatusClosed {
+			skip[f.Fingerprint] = true
+		}
+	}
+	return skip
+}
+
+// Stats returns a summary, or "" if there is nothing to report
+func (r *Reconciliation) Stats() string {
+	if len(r.Closed)+len(r.Reopened)+len(r.AlreadyClosed)+len(r.Conflicts) == 0 {
+		return ""
+	}
+	return fmt.Sprintf("Closed in Beads: %d, Reopened in Beads: %d, Already closed: %d, Conflicts: %d",
+		len(r.Closed), len(r.Reopened), len(r.AlreadyClosed), len(r.Conflicts))
+}
+
+func joinFields(fields []FieldChange) string {
+	parts := make([]string, len(fields))
+	for i, fc := range fields {
+		parts[i] = fc.String()
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,103 @@
+package sync
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheEditor/strung/pkg/db"
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+func TestReconcile(t *testing.T) {
+	now := time.Now()
+	finding := func(issueID string) *db.Finding {
+		return &db.Finding{Fingerprint: "fp-" + issueID, IssueID: issueID, File: "a.go", Line: 1}
+	}
+	suppressed := finding("bd-4")
+	suppressed.SuppressedAt = &now
+	stillClosed := finding("bd-7")
+	stillClosed.SuppressedAt = &now
+
+	result := &DiffResult{
+		Unchanged: []ChangeRecord{
+			{Previous: finding("bd-1")}, // open: nothing to do
+			{Previous: finding("bd-2")}, // closed by hand
+			{Previous: finding("bd-3")}, // missing from Beads
+			{Previous: suppressed},      // reopened by hand
+			{Previous: finding("bd-8")}, // not read
+		},
+		Changed: []ChangeRecord{
+			{Previous: stillClosed, Current: parser.UBSFinding{Severity: "critical"},
+				Fields: []FieldChange{{Field: "severity", Previous: "warning", Current: "critical"}}},
+		},
+		Resolved: []*db.Finding{finding("bd-5"), finding("bd-6")},
+	}
+	states := map[string]IssueState{
+		"bd-1": {Status: "open"},
+		"bd-2": {Status: "closed", CloseReason: "wontfix"},
+		"bd-3": {},
+		"bd-4": {Status: "in_progress"},
+		"bd-5": {Status: "closed"},
+		"bd-6": {Status: "open"},
+		"bd-7": {Status: "closed"},
+	}
+
+	rec := Reconcile(result, states)
+
+	if len(rec.Closed) != 1 || rec.Closed[0].IssueID != "bd-2" {
+		t.Errorf("Closed = %v, want bd-2", rec.Closed)
+	}
+	if len(rec.Reopened) != 1 || rec.Reopened[0].IssueID != "bd-4" {
+		t.Errorf("Reopened = %v, want bd-4", rec.Reopened)
+	}
+	if len(rec.AlreadyClosed) != 1 || rec.AlreadyClosed[0].IssueID != "bd-5" {
+		t.Errorf("AlreadyClosed = %v, want bd-5", rec.AlreadyClosed)
+	}
+	if len(result.Resolved) != 1 || result.Resolved[0].IssueID != "bd-6" {
+		t.Errorf("Resolved = %v, want only bd-6 left to close", result.Resolved)
+	}
+
+	want := []string{
+		"bd-3: issue not found, finding still present",
+		"bd-7: issue closed, finding changed (severity warning → critical)",
+	}
+	if len(rec.Conflicts) != len(want) {
+		t.Fatalf("Conflicts = %v, want %v", rec.Conflicts, want)
+	}
+	for i, w := range want {
+		if got := rec.Conflicts[i].String(); got != w {
+			t.Errorf("Conflicts[%d] = %q, want %q", i, got, w)
+		}
+	}
+
+	for _, fp := range []string{"fp-bd-2", "fp-bd-3", "fp-bd-7"} {
+		if !rec.Skip[fp] {
+			t.Errorf("Skip missing %s", fp)
+		}
+	}
+	if rec.Skip["fp-bd-1"] || rec.Skip["fp-bd-4"] {
+		t.Errorf("Skip = %v, want open issues writable", rec.Skip)
+	}
+}
+
+func TestReconcile_NothingRead(t *testing.T) {
+	result := &DiffResult{Resolved: []*db.Finding{{Fingerprint: "fp", IssueID: "bd-1"}}}
+
+	rec := Reconcile(result, nil)
+	if rec.Stats() != "" || len(result.Resolved) != 1 {
+		t.Errorf("unread issues were reconciled: %s, resolved %v", rec.Stats(), result.Resolved)
+	}
+}
+
+func TestStoredSkip(t *testing.T) {
+	now := time.Now()
+	suppressed := &db.Finding{Fingerprint: "fp-1", IssueID: "bd-1", SuppressedAt: &now}
+	closed := &db.Finding{Fingerprint: "fp-2", IssueID: "bd-2", IssueStatus: "closed"}
+	open := &db.Finding{Fingerprint: "fp-3", IssueID: "bd-3", IssueStatus: "open"}
+	unread := &db.Finding{Fingerprint: "fp-4", IssueID: "bd-4"}
+
+	skip := StoredSkip([]*db.Finding{suppressed, closed, open, unread})
+	if !skip["fp-1"] || !skip["fp-2"] || skip["fp-3"] || skip["fp-4"] {
+		t.Errorf("StoredSkip = %v, want fp-1 and fp-2", skip)
+	}
+}
@@ -0,0 +1,150 @@
+// Package taxonomy maps UBS finding categories to CWE weaknesses, OWASP Top
+// 10 categories and remediation references.
+package taxonomy
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Entry classifies one finding category
+type Entry struct {
+	CWE   []string `json:"cwe,omitempty"`   // e.g. "CWE-476"
+	OWASP []string `json:"owasp,omitempty"` // e.g. "A03:2021-Injection"
+	Docs  []string `json:"docs,omitempty"`  // Remediation references
+}
+
+// IsEmpty reports whether the entry carries no classification
+func (e Entry) IsEmpty() bool {
+	return len(e.CWE) == 0 && len(e.OWASP) == 0 && len(e.Docs) == 0
+}
+
+// Labels returns issue labels: "cwe:476" and "owasp:A03:2021"
+func (e Entry) Labels() []string {
+	var labels []string
+	for _, id := range e.CWE {
+		labels = append(labels, "cwe:"+CWENumber(id))
+	}
+	for _, cat := range e.OWASP {
+		code, _, _ := strings.Cut(cat, "-")
+		labels = append(labels, "owasp:"+code)
+	}
+	return labels
+}
+
+// CWENumber returns the numeric part of a CWE ID ("CWE-476" → "476")
+func CWENumber(id string) string {
+	id = strings.TrimSpace(id)
+	if len(id) > 4 && strings.EqualFold(id[:4], "CWE-") {
+		return id[4:]
+	}
+	return id
+}
+
+// CWEURL returns the MITRE definition page for a CWE ID
+func CWEURL(id string) string {
+	return fmt.Sprintf("https://cwe.mitre.org/data/definitions/%s.html", CWENumber(id))
+}
+
+// Taxonomy maps categories to entries
+type Taxonomy map[string]Entry
+
+const cheatSheets = "https://cheatsheetseries.owasp.org/cheatsheets/"
+
+// Default returns the built-in taxonomy for common UBS categories
+func Default() Taxonomy {
+	return Taxonomy{
+		"null-safety":        {CWE: []string{"CWE-476"}},
+		"resource-lifecycle": {CWE: []string{"CWE-772"}},
+		"resource-leak":      {CWE: []string{"CWE-772"}},
+		"memory-leak":        {CWE: []string{"CWE-401"}},
+		"error-handling":     {CWE: []string{"CWE-755"}},
+		"async":              {CWE: []string{"CWE-248"}},
+		"concurrency":        {CWE: []string{"CWE-362"}},
+		"race-condition":     {CWE: []string{"CWE-362"}},
+		"type-safety":        {CWE: []string{"CWE-704"}},
+		"integer-overflow":   {CWE: []string{"CWE-190"}},
+		"code-quality":       {CWE: []string{"CWE-710"}},
+		"regex":              {CWE: []string{"CWE-1333"}},
+		"redos":              {CWE: []string{"CWE-1333"}},
+		"sql-injection": {
+			CWE:   []string{"CWE-89"},
+			OWASP: []string{"A03:2021-Injection"},
+			Docs:  []string{cheatSheets + "SQL_Injection_Prevention_Cheat_Sheet.html"},
+		},
+		"command-injection": {
+			CWE:   []string{"CWE-78"},
+			OWASP: []string{"A03:2021-Injection"},
+			Docs:  []string{cheatSheets + "OS_Command_Injection_Defense_Cheat_Sheet.html"},
+		},
+		"code-injection": {
+			CWE:   []string{"CWE-95"},
+			OWASP: []string{"A03:2021-Injection"},
+		},
+		"xss": {
+			CWE:   []string{"CWE-79"},
+			OWASP: []string{"A03:2021-Injection"},
+			Docs:  []string{cheatSheets + "Cross_Site_Scripting_Prevention_Cheat_Sheet.html"},
+		},
+		"path-traversal": {
+			CWE:   []string{"CWE-22"},
+			OWASP: []string{"A01:2021-Broken Access Control"},
+		},
+		"hardcoded-secrets": {
+			CWE:   []string{"CWE-798"},
+			OWASP: []string{"A07:2021-Identification and Authentication Failures"},
+			Docs:  []string{cheatSheets + "Secrets_Management_Cheat_Sheet.html"},
+		},
+		"weak-crypto": {
+			CWE:   []string{"CWE-327"},
+			OWASP: []string{"A02:2021-Cryptographic Failures"},
+			Docs:  []string{cheatSheets + "Cryptographic_Storage_Cheat_Sheet.html"},
+		},
+		"deserialization": {
+			CWE:   []string{"CWE-502"},
+			OWASP: []string{"A08:2021-Software and Data Integrity Failures"},
+			Docs:  []string{cheatSheets + "Deserialization_Cheat_Sheet.html"},
+		},
+		"ssrf": {
+			CWE:   []string{"CWE-918"},
+			OWASP: []string{"A10:2021-Server-Side Request Forgery"},
+			Docs:  []string{cheatSheets + "Server_Side_Request_Forgery_Prevention_Cheat_Sheet.html"},
+		},
+		"sensitive-logging": {
+			CWE:   []string{"CWE-532"},
+			OWASP: []string{"A09:2021-Security Logging and Monitoring Failures"},
+			Docs:  []string{cheatSheets + "Logging_Cheat_Sheet.html"},
+		},
+	}
+}
+
+// With returns a copy of the taxonomy with overrides applied. An override
+// replaces the whole entry for its category; an empty entry removes the
+// category's classification.
+func (t Taxonomy) With(overrides map[string]Entry) Taxonomy {
+	merged := make(Taxonomy, len(t)+len(overrides))
+	for cat, e := range t {
+		merged[normalize(cat)] = e
+	}
+	for cat, e := range overrides {
+		if e.IsEmpty() {
+			delete(merged, normalize(cat))
+		} else {
+			merged[normalize(cat)] = e
+		}
+	}
+	return merged
+}
+
+// Lookup returns the entry for a category, matched case-insensitively
+func (t Taxonomy) Lookup(category string) (Entry, bool) {
+	if e, ok := t[category]; ok {
+		return e, true
+	}
+	e, ok := t[normalize(category)]
+	return e, ok
+}
+
+func normalize(category string) string {
+	return strings.ToLower(strings.TrimSpace(category))
+}
@@ -0,0 +1,36 @@
+package taxonomy
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEntry_Labels(t *testing.T) {
+	e := Entry{CWE: []string{"CWE-89", "cwe-564"}, OWASP: []string{"A03:2021-Injection"}}
+	want := []string{"cwe:89", "cwe:564", "owasp:A03:2021"}
+	if got := e.Labels(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Labels = %v, want %v", got, want)
+	}
+}
+
+func TestTaxonomy_With(t *testing.T) {
+	base := Default()
+	tax := base.With(map[string]Entry{
+		"Null-Safety":  {CWE: []string{"CWE-690"}},
+		"code-quality": {},
+		"my-rule":      {Docs: []string{"https://example.com"}},
+	})
+
+	if e, _ := tax.Lookup("null-safety"); !reflect.DeepEqual(e.CWE, []string{"CWE-690"}) {
+		t.Errorf("Override not applied: %+v", e)
+	}
+	if _, ok := tax.Lookup("code-quality"); ok {
+		t.Error("Empty override should remove the category")
+	}
+	if _, ok := tax.Lookup("MY-RULE"); !ok {
+		t.Error("Lookup should be case-insensitive")
+	}
+	if e, _ := base.Lookup("null-safety"); e.CWE[0] != "CWE-476" {
+		t.Error("With must not modify the receiver")
+	}
+}
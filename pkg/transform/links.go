@@ -0,0 +1,203 @@
+package transform
+
+import (
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// LinkTarget identifies a file location to link to on a forge
+type LinkTarget struct {
+	RepoURL     string // Browsable repository URL, no trailing slash
+	Ref         string // Commit SHA or branch name
+	RefIsCommit bool   // Ref is a commit SHA (some forges use different routes)
+	Path        string // Repository-relative path using forward slashes
+	Line        int    // First line (1-based); 0 links the file
+	EndLine     int    // Last line of a range; <= Line links a single line
+	Column      int    // Column of Line (1-based); 0 when unknown
+}
+
+// LinkBuilder renders file permalinks for one forge's URL scheme
+type LinkBuilder interface {
+	FileLink(t LinkTarget) string
+}
+
+// Forge names accepted by LinkBuilderFor
+const (
+	ForgeGitHub    = "github"
+	ForgeGitLab    = "gitlab"
+	ForgeBitbucket = "bitbucket"
+	ForgeGitea     = "gitea"
+	ForgeAzure     = "azure"
+	ForgeSourcehut = "sourcehut"
+)
+
+var linkBuilders = map[string]LinkBuilder{
+	ForgeGitHub:    githubLinks{},
+	ForgeGitLab:    gitlabLinks{},
+	ForgeBitbucket: bitbucketLinks{},
+	ForgeGitea:     giteaLinks{},
+	ForgeAzure:     azureLinks{},
+	ForgeSourcehut: sourcehutLinks{},
+}
+
+// Forges returns the supported forge names, sorted
+func Forges() []string {
+	names := make([]string, 0, len(linkBuilders))
+	for name := range linkBuilders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LinkBuilderFor returns the builder for a forge name. "" or "auto" detects
+// the forge from the repository URL host.
+func LinkBuilderFor(forge, repoURL string) (LinkBuilder, error) {
+	if forge == "" || forge == "auto" {
+		forge = DetectForge(repoURL)
+	}
+	lb, ok := linkBuilders[forge]
+	if !ok {
+		return nil, fmt.Errorf("unknown forge %q (use: auto, %s)", forge, strings.Join(Forges(), ", "))
+	}
+	return lb, nil
+}
+
+// DetectForge guesses the forge from the repository URL host. Self-hosted
+// instances are recognized when the host names the product (e.g.
+// gitlab.example.com); anything unrecognized is treated as GitHub.
+func DetectForge(repoURL string) string {
+	host := repoURL
+	if u, err := url.Parse(repoURL); err == nil && u.Host != "" {
+		host = u.Hostname()
+	}
+	host = strings.ToLower(host)
+
+	switch {
+	case host == "dev.azure.com" || strings.HasSuffix(host, ".visualstudio.com"):
+		return ForgeAzure
+	case host == "git.sr.ht":
+		return ForgeSourcehut
+	case strings.Contains(host, "bitbucket"):
+		return ForgeBitbucket
+	case strings.Contains(host, "gitlab"):
+		return ForgeGitLab
+	case host == "codeberg.org" || strings.Contains(host, "gitea") || strings.Contains(host, "forgejo"):
+		return ForgeGitea
+	}
+	return ForgeGitHub
+}
+
+// isRange reports whether the target spans more than one line
+func (t LinkTarget) isRange() bool {
+	return t.Line > 0 && t.EndLine > t.Line
+}
+
+// githubLinks: /blob/<ref>/<path>#L10-L12
+type githubLinks struct{}
+
+func (githubLinks) FileLink(t LinkTarget) string {
+	link := fmt.Sprintf("%s/blob/%s/%s", t.RepoURL, t.Ref, t.Path)
+	if t.isRange() {
+		return fmt.Sprintf("%s#L%d-L%d", link, t.Line, t.EndLine)
+	} else if t.Line > 0 {
+		return fmt.Sprintf("%s#L%d", link, t.Line)
+	}
+	return link
+}
+
+// gitlabLinks: /-/blob/<ref>/<path>#L10-12
+type gitlabLinks struct{}
+
+func (gitlabLinks) FileLink(t LinkTarget) string {
+	link := fmt.Sprintf("%s/-/blob/%s/%s", t.RepoURL, t.Ref, t.Path)
+	if t.isRange() {
+		return fmt.Sprintf("%s#L%d-%d", link, t.Line, t.EndLine)
+	} else if t.Line > 0 {
+		return fmt.Sprintf("%s#L%d", link, t.Line)
+	}
+	return link
+}
+
+// bitbucketLinks: /src/<ref>/<path>#lines-10:12
+type bitbucketLinks struct{}
+
+func (bitbucketLinks) FileLink(t LinkTarget) string {
+	link := fmt.Sprintf("%s/src/%s/%s", t.RepoURL, t.Ref, t.Path)
+	if t.isRange() {
+		return fmt.Sprintf("%s#lines-%d:%d", link, t.Line, t.EndLine)
+	} else if t.Line > 0 {
+		return fmt.Sprintf("%s#lines-%d", link, t.Line)
+	}
+	return link
+}
+
+// giteaLinks (Gitea, Forgejo, Codeberg): /src/commit/<sha>/<path>#L10-L12
+// or /src/branch/<name>/<path>
+type giteaLinks struct{}
+
+func (giteaLinks) FileLink(t LinkTarget) string {
+	kind := "branch"
+	if t.RefIsCommit {
+		kind = "commit"
+	}
+	link := fmt.Sprintf("%s/src/%s/%s/%s", t.RepoURL, kind, t.Ref, t.Path)
+	if t.isRange() {
+		return fmt.Sprintf("%s#L%d-L%d", link, t.Line, t.EndLine)
+	} else if t.Line > 0 {
+		return fmt.Sprintf("%s#L%d", link, t.Line)
+	}
+	return link
+}
+
+// azureLinks (Azure DevOps Repos): ?path=/<path>&version=GC<sha>&line=10...
+// The only supported forge that can highlight a column.
+type azureLinks struct{}
+
+func (azureLinks) FileLink(t LinkTarget) string {
+	version := "GB" + t.Ref
+	if t.RefIsCommit {
+		version = "GC" + t.Ref
+	}
+
+	q := url.Values{}
+	q.Set("path", "/"+t.Path)
+	q.Set("version", version)
+	if t.Line > 0 {
+		// Whole lines are selected by ending at column 1 of the next line;
+		// a single line with a known column selects just that character
+		end, startCol, endCol := t.Line+1, 1, 1
+		if t.isRange() {
+			end = t.EndLine + 1
+		}
+		if t.Column > 0 {
+			startCol = t.Column
+			if !t.isRange() {
+				end, endCol = t.Line, t.Column+1
+			}
+		}
+		q.Set("line", fmt.Sprint(t.Line))
+		q.Set("lineEnd", fmt.Sprint(end))
+		q.Set("lineStartColumn", fmt.Sprint(startCol))
+		q.Set("lineEndColumn", fmt.Sprint(endCol))
+		q.Set("lineStyle", "plain")
+	}
+	q.Set("_a", "contents")
+
+	return t.RepoURL + "?" + q.Encode()
+}
+
+// sourcehutLinks: /tree/<ref>/item/<path>#L10-12
+type sourcehutLinks struct{}
+
+func (sourcehutLinks) FileLink(t LinkTarget) string {
+	link := fmt.Sprintf("%s/tree/%s/item/%s", t.RepoURL, t.Ref, t.Path)
+	if t.isRange() {
+		return fmt.Sprintf("%s#L%d-%d", link, t.Line, t.EndLine)
+	} else if t.Line > 0 {
+		return fmt.Sprintf("%s#L%d", link, t.Line)
+	}
+	return link
+}
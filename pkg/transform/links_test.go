@@ -0,0 +1,85 @@
+package transform
+
+import "testing"
+
+func TestDetectForge(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://github.com/user/repo", ForgeGitHub},
+		{"https://github.example.com/user/repo", ForgeGitHub},
+		{"https://gitlab.com/group/repo", ForgeGitLab},
+		{"https://gitlab.internal.example.com/group/repo", ForgeGitLab},
+		{"https://bitbucket.org/team/repo", ForgeBitbucket},
+		{"https://codeberg.org/user/repo", ForgeGitea},
+		{"https://gitea.example.com/user/repo", ForgeGitea},
+		{"https://dev.azure.com/org/project/_git/repo", ForgeAzure},
+		{"https://org.visualstudio.com/project/_git/repo", ForgeAzure},
+		{"https://git.sr.ht/~user/repo", ForgeSourcehut},
+	}
+
+	for _, tt := range tests {
+		if got := DetectForge(tt.url); got != tt.want {
+			t.Errorf("DetectForge(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestLinkBuilders(t *testing.T) {
+	const sha = "3f2a9c1"
+	tests := []struct {
+		forge  string
+		repo   string
+		target LinkTarget
+		want   string
+	}{
+		{ForgeGitHub, "https://github.com/u/r",
+			LinkTarget{Ref: sha, RefIsCommit: true, Path: "src/a.ts", Line: 10, EndLine: 12},
+			"https://github.com/u/r/blob/3f2a9c1/src/a.ts#L10-L12"},
+		{ForgeGitHub, "https://github.com/u/r",
+			LinkTarget{Ref: "main", Path: "src/a.ts", Line: 10},
+			"https://github.com/u/r/blob/main/src/a.ts#L10"},
+		{ForgeGitLab, "https://gitlab.com/g/r",
+			LinkTarget{Ref: sha, RefIsCommit: true, Path: "src/a.ts", Line: 10, EndLine: 12},
+			"https://gitlab.com/g/r/-/blob/3f2a9c1/src/a.ts#L10-12"},
+		{ForgeBitbucket, "https://bitbucket.org/t/r",
+			LinkTarget{Ref: sha, RefIsCommit: true, Path: "src/a.ts", Line: 10, EndLine: 12},
+			"https://bitbucket.org/t/r/src/3f2a9c1/src/a.ts#lines-10:12"},
+		{ForgeBitbucket, "https://bitbucket.org/t/r",
+			LinkTarget{Ref: "main", Path: "src/a.ts", Line: 10},
+			"https://bitbucket.org/t/r/src/main/src/a.ts#lines-10"},
+		{ForgeGitea, "https://codeberg.org/u/r",
+			LinkTarget{Ref: sha, RefIsCommit: true, Path: "src/a.ts", Line: 10, EndLine: 12},
+			"https://codeberg.org/u/r/src/commit/3f2a9c1/src/a.ts#L10-L12"},
+		{ForgeGitea, "https://codeberg.org/u/r",
+			LinkTarget{Ref: "main", Path: "src/a.ts", Line: 10},
+			"https://codeberg.org/u/r/src/branch/main/src/a.ts#L10"},
+		{ForgeAzure, "https://dev.azure.com/o/p/_git/r",
+			LinkTarget{Ref: sha, RefIsCommit: true, Path: "src/a.ts", Line: 10, Column: 5},
+			"https://dev.azure.com/o/p/_git/r?_a=contents&line=10&lineEnd=10&lineEndColumn=6&lineStartColumn=5&lineStyle=plain&path=%2Fsrc%2Fa.ts&version=GC3f2a9c1"},
+		{ForgeAzure, "https://dev.azure.com/o/p/_git/r",
+			LinkTarget{Ref: "main", Path: "src/a.ts", Line: 10, EndLine: 12},
+			"https://dev.azure.com/o/p/_git/r?_a=contents&line=10&lineEnd=13&lineEndColumn=1&lineStartColumn=1&lineStyle=plain&path=%2Fsrc%2Fa.ts&version=GBmain"},
+		{ForgeSourcehut, "https://git.sr.ht/~u/r",
+			LinkTarget{Ref: sha, RefIsCommit: true, Path: "src/a.ts", Line: 10, EndLine: 12},
+			"https://git.sr.ht/~u/r/tree/3f2a9c1/item/src/a.ts#L10-12"},
+	}
+
+	for _, tt := range tests {
+		lb, err := LinkBuilderFor(tt.forge, tt.repo)
+		if err != nil {
+			t.Fatalf("LinkBuilderFor(%q): %v", tt.forge, err)
+		}
+		tt.target.RepoURL = tt.repo
+		if got := lb.FileLink(tt.target); got != tt.want {
+			t.Errorf("%s link:\n got  %s\n want %s", tt.forge, got, tt.want)
+		}
+	}
+}
+
+func TestLinkBuilderFor_Unknown(t *testing.T) {
+	if _, err := LinkBuilderFor("fossil", ""); err == nil {
+		t.Error("Expected error for unknown forge")
+	}
+}
@@ -0,0 +1,199 @@
+package transform
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits caps the size of generated issue text. Zero fields use the
+// defaults; negative fields disable that limit.
+type Limits struct {
+	Title        int // Maximum title length in characters
+	Description  int // Maximum description length in characters
+	SnippetLines int // Maximum code snippet lines
+}
+
+// DefaultLimits keeps issues readable in terminals and well under tracker
+// field limits
+var DefaultLimits = Limits{
+	Title:        200,
+	Description:  20000,
+	SnippetLines: 50,
+}
+
+// withDefaults fills zero fields from DefaultLimits
+func (l Limits) withDefaults() Limits {
+	if l.Title == 0 {
+		l.Title = DefaultLimits.Title
+	}
+	if l.Description == 0 {
+		l.Description = DefaultLimits.Description
+	}
+	if l.SnippetLines == 0 {
+		l.SnippetLines = DefaultLimits.SnippetLines
+	}
+	return l
+}
+
+// stripControl removes control characters other than newline and tab,
+// including bidirectional overrides that can disguise code, and
+// normalizes line endings
+func stripControl(s string) string {
+	s = strings.ReplaceAll(s, "\r\n", "\n")
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r == '\n' || r == '\t':
+			return r
+		case unicode.IsControl(r), r == utf8.RuneError:
+			return -1
+		case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+			return -1
+		}
+		return r
+	}, s)
+}
+
+// inlineEscaper escapes characters with inline Markdown meaning
+var inlineEscaper = strings.NewReplacer(
+	`\`, `\\`, "`", "\\`", `*`, `\*`, `_`, `\_`, `[`, `\[`, `]`, `\]`,
+	`<`, `\<`, `>`, `\>`, `|`, `\|`, `~`, `\~`,
+)
+
+// escapeMarkdown makes scanner text render literally: inline markup is
+// escaped and block markers at the start of a line (headings, lists,
+// quotes, rules) are neutralized
+func escapeMarkdown(s string) string {
+	lines := strings.Split(inlineEscaper.Replace(stripControl(s)), "\n")
+	for i, line := range lines {
+		trimmed := strings.TrimLeft(line, " \t")
+		indent := line[:len(line)-len(trimmed)]
+
+		switch {
+		case trimmed == "":
+		case strings.ContainsRune("#-+=", rune(trimmed[0])):
+			lines[i] = indent + `\` + trimmed
+		default:
+			// Ordered list: "1. " or "1) "
+			digits := len(trimmed) - len(strings.TrimLeft(trimmed, "0123456789"))
+			if digits > 0 && digits < len(trimmed) && (trimmed[digits] == '.' || trimmed[digits] == ')') {
+				lines[i] = indent + trimmed[:digits] + `\` + trimmed[digits:]
+			}
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+// codeSpan wraps text in an inline code span whose backtick run is longer
+// than any run inside it
+func codeSpan(s string) string {
+	s = strings.ReplaceAll(stripControl(s), "\n", " ")
+	ticks := strings.Repeat("`", longestRun(s, '`')+1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	return ticks + s + ticks
+}
+
+// codeBlock renders a fenced code block whose fence is longer than any
+// backtick run in the content, so the content cannot close it early.
+// Blocks longer than maxLines are cut with a "… N more lines" marker.
+func codeBlock(info, content string, maxLines int) string {
+	content = strings.TrimRight(stripControl(content), "\n")
+	lines := strings.Split(content, "\n")
+	if maxLines > 0 && len(lines) > maxLines {
+		more := len(lines) - maxLines
+		lines = append(lines[:maxLines], moreLines(more))
+	}
+	content = strings.Join(lines, "\n")
+
+	fence := strings.Repeat("`", max(3, longestRun(content, '`')+1))
+	return fmt.Sprintf("%s%s\n%s\n%s\n", fence, info, content, fence)
+}
+
+// markdownLink renders [text](url) with the text escaped and characters
+// that would end the URL early percent-encoded
+func markdownLink(text, url string) string {
+	url = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29", "<", "%3C", ">", "%3E").Replace(url)
+	return fmt.Sprintf("[%s](%s)", escapeMarkdown(text), url)
+}
+
+// moreLines is the truncation marker
+func moreLines(n int) string {
+	if n == 1 {
+		return "… 1 more line"
+	}
+	return fmt.Sprintf("… %d more lines", n)
+}
+
+// truncateTitle cuts a single-line title to max characters with an ellipsis
+func truncateTitle(s string, limit int) string {
+	s = strings.Join(strings.Fields(stripControl(s)), " ")
+	if limit <= 0 || utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	runes := []rune(s)
+	return strings.TrimRight(string(runes[:limit-1]), " ") + "…"
+}
+
+// truncateMarkdown keeps whole lines of md up to limit characters, closing
+// a code fence left open by the cut and noting how many lines were dropped
+func truncateMarkdown(md string, limit int) string {
+	if limit <= 0 || utf8.RuneCountInString(md) <= limit {
+		return md
+	}
+
+	lines := strings.Split(strings.TrimRight(md, "\n"), "\n")
+	var kept []string
+	openFence := ""
+	size := 0
+	for _, line := range lines {
+		// Reserve room for the marker and a closing fence
+		n := utf8.RuneCountInString(line) + 1
+		if size+n > limit-40-len(openFence) {
+			break
+		}
+		size += n
+		kept = append(kept, line)
+
+		if run := longestPrefixRun(line, '`'); run >= 3 {
+			switch {
+			case openFence == "":
+				openFence = strings.Repeat("`", run)
+			case run >= len(openFence) && strings.TrimSpace(line) == strings.Repeat("`", run):
+				openFence = ""
+			}
+		}
+	}
+
+	dropped := len(lines) - len(kept)
+	if openFence != "" {
+		kept = append(kept, openFence)
+	}
+	kept = append(kept, "", moreLines(dropped))
+	return strings.Join(kept, "\n") + "\n"
+}
+
+// longestRun returns the longest run of c in s
+func longestRun(s string, c byte) int {
+	longest, run := 0, 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == c {
+			run++
+			longest = max(longest, run)
+		} else {
+			run = 0
+		}
+	}
+	return longest
+}
+
+// longestPrefixRun counts leading c characters
+func longestPrefixRun(s string, c byte) int {
+	n := 0
+	for n < len(s) && s[n] == c {
+		n++
+	}
+	return n
+}
@@ -0,0 +1,110 @@
+package transform
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TheEditor/strung/pkg/parser"
+)
+
+func TestEscapeMarkdown(t *testing.T) {
+	tests := map[string]string{
+		"Use `eval` with *care*":     "Use \\`eval\\` with \\*care\\*",
+		"see [docs](http://x)":       "see \\[docs\\](http://x)",
+		"# not a heading":            "\\# not a heading",
+		"line\n- not a list\n1. two": "line\n\\- not a list\n1\\. two",
+		"a <script> tag":             "a \\<script\\> tag",
+		"bell\x07 and \x1b[31mred":   "bell and \\[31mred",
+		"rtl\u202eoverride":          "rtloverride",
+	}
+
+	for in, want := range tests {
+		if got := escapeMarkdown(in); got != want {
+			t.Errorf("escapeMarkdown(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestCodeSpan(t *testing.T) {
+	if got := codeSpan("a.ts:1"); got != "`a.ts:1`" {
+		t.Errorf("codeSpan = %q", got)
+	}
+	if got := codeSpan("we`ird.ts:1"); got != "``we`ird.ts:1``" {
+		t.Errorf("codeSpan with backtick = %q", got)
+	}
+}
+
+func TestCodeBlock(t *testing.T) {
+	got := codeBlock("md", "text\n```\nbreakout\n```", 0)
+	if !strings.HasPrefix(got, "````md\n") || !strings.HasSuffix(got, "\n````\n") {
+		t.Errorf("Fence should outgrow backtick runs in content:\n%s", got)
+	}
+
+	got = codeBlock("", "1\n2\n3\n4\n5", 2)
+	if got != "```\n1\n2\n… 3 more lines\n```\n" {
+		t.Errorf("Expected truncated block, got:\n%s", got)
+	}
+}
+
+func TestTruncateTitle(t *testing.T) {
+	if got := truncateTitle("UBS: x in\nfile.ts:1", 0); got != "UBS: x in file.ts:1" {
+		t.Errorf("Title should be single-line, got %q", got)
+	}
+	if got := truncateTitle("abcdefghij", 5); got != "abcd…" {
+		t.Errorf("truncateTitle = %q", got)
+	}
+}
+
+func TestTruncateMarkdown(t *testing.T) {
+	md := "**Code:**\n```go\n" + strings.Repeat("x := 1\n", 100) + "```\n\n**Suggestion:** fix\n"
+
+	got := truncateMarkdown(md, 200)
+	if len(got) > 200 {
+		t.Errorf("Truncated length %d exceeds limit", len(got))
+	}
+	if strings.Count(got, "```") != 2 {
+		t.Errorf("Open fence should be closed:\n%s", got)
+	}
+	if !strings.Contains(got, "more lines") {
+		t.Errorf("Missing truncation marker:\n%s", got)
+	}
+
+	if truncateMarkdown("short", 200) != "short" {
+		t.Error("Short text should be unchanged")
+	}
+}
+
+func TestTransform_HostileFinding(t *testing.T) {
+	finding := parser.UBSFinding{
+		File:        "src/a.md",
+		Line:        1,
+		Severity:    "warning",
+		Category:    "x",
+		Message:     "Injected [link](http://evil) and\x1b[2J escape",
+		CodeSnippet: "```\n# escaped fence\n" + strings.Repeat("line\n", 80),
+	}
+
+	base, err := NewTransformer().Transform(finding)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+	enriched, err := NewTransformerWithConfig(&TransformConfig{Limits: Limits{SnippetLines: 10}}).Transform(finding)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	for name, desc := range map[string]string{"base": base.Description, "enriched": enriched.Description} {
+		if strings.Contains(desc, "[link]") || strings.Contains(desc, "\x1b") {
+			t.Errorf("%s: message not sanitized:\n%s", name, desc)
+		}
+		if !strings.Contains(desc, "````markdown\n") {
+			t.Errorf("%s: snippet fence should be lengthened:\n%s", name, desc)
+		}
+	}
+	if !strings.Contains(base.Description, "… 32 more lines") {
+		t.Errorf("base: default snippet limit not applied:\n%s", base.Description)
+	}
+	if !strings.Contains(enriched.Description, "… 72 more lines") {
+		t.Errorf("enriched: configured snippet limit not applied:\n%s", enriched.Description)
+	}
+}
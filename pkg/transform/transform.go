@@ -9,24 +9,70 @@ import (
 	"time"
 
 	"github.com/TheEditor/strung/pkg/beads"
+	"github.com/TheEditor/strung/pkg/git"
+	"github.com/TheEditor/strung/pkg/lang"
 	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/source"
+	"github.com/TheEditor/strung/pkg/taxonomy"
 )
 
 // Transformer converts UBS findings to Beads issues
 type Transformer struct {
-	Verbose bool // Enable debug logging
+	Verbose  bool              // Enable debug logging
+	Limits   Limits            // Size caps for generated text; zero fields use DefaultLimits
+	Taxonomy taxonomy.Taxonomy // CWE/OWASP classification by category
 }
 
 // NewTransformer creates a new transformer
 func NewTransformer() *Transformer {
-	return &Transformer{}
+	return &Transformer{Taxonomy: taxonomy.Default()}
 }
 
 // TransformConfig provides context for enhanced transformation
 type TransformConfig struct {
 	RepoURL    string    // e.g., "https://github.com/user/repo"
-	RepoBranch string    // e.g., "main"
+	RepoBranch string    // e.g., "main"; used for links when Commit is empty
+	Commit     string    // Commit SHA; pins links so they don't drift as the branch moves
+	PathPrefix string    // Prepended to finding paths in links, e.g. "services/api/"
 	ScanTime   time.Time // When scan was performed
+	Limits     Limits    // Size caps for generated text; zero fields use DefaultLimits
+
+	// Taxonomy classifies categories; nil uses taxonomy.Default()
+	Taxonomy taxonomy.Taxonomy
+
+	// Links renders file links; nil detects the forge from RepoURL
+	Links LinkBuilder
+
+	// Owners assigns issues from CODEOWNERS; nil leaves them unassigned.
+	// OwnerAliases maps owners (e.g. "@org/backend") to Beads users.
+	Owners       OwnerLookup
+	OwnerAliases map[string]string
+
+	// Blame records who last changed the flagged lines; nil skips it.
+	// Files without code owners are assigned to the author when their
+	// email is in BlameUsers.
+	Blame      BlameLookup
+	BlameUsers map[string]string
+
+	// Source supplies code context from the working tree; nil renders the
+	// scanner snippet only. ContextLines are shown on each side.
+	Source       ExcerptLookup
+	ContextLines int
+}
+
+// OwnerLookup maps a repository-relative path to its owners
+type OwnerLookup interface {
+	Owners(path string) []string
+}
+
+// ExcerptLookup reads source lines around a finding
+type ExcerptLookup interface {
+	Excerpt(file string, line, column, context int) (*source.Excerpt, error)
+}
+
+// BlameLookup finds the most recent commit touching a line range of a file
+type BlameLookup interface {
+	LastChange(file string, start, end int) (*git.BlameLine, error)
 }
 
 // TransformerWithConfig embeds base transformer with enrichment
@@ -37,8 +83,13 @@ type TransformerWithConfig struct {
 
 // NewTransformerWithConfig creates enriched transformer
 func NewTransformerWithConfig(config *TransformConfig) *TransformerWithConfig {
+	base := NewTransformer()
+	base.Limits = config.Limits
+	if config.Taxonomy != nil {
+		base.Taxonomy = config.Taxonomy
+	}
 	return &TransformerWithConfig{
-		Transformer: NewTransformer(),
+		Transformer: base,
 		config:      config,
 	}
 }
@@ -78,6 +129,7 @@ func (t *Transformer) Transform(finding parser.UBSFinding) (*beads.Issue, error)
 
 	// Add tags
 	issue.Tags = []string{"ubs", finding.Category}
+	issue.Tags = append(issue.Tags, t.classify(finding).Labels()...)
 
 	return issue, nil
 }
@@ -100,35 +152,76 @@ func (t *Transformer) TransformAll(findings []parser.UBSFinding) []*beads.Issue
 // makeTitle creates issue title from finding
 func (t *Transformer) makeTitle(f parser.UBSFinding) string {
 	filename := filepath.Base(f.File)
-	return fmt.Sprintf("UBS: %s in %s:%d", f.Category, filename, f.Line)
+	title := fmt.Sprintf("UBS: %s in %s:%d", f.Category, filename, f.Line)
+	return truncateTitle(title, t.Limits.withDefaults().Title)
 }
 
 // makeDescription builds issue description
 func (t *Transformer) makeDescription(f parser.UBSFinding) string {
-	desc := fmt.Sprintf("**Location:** `%s:%d`\n\n", f.File, f.Line)
-	desc += fmt.Sprintf("**Message:** %s\n\n", f.Message)
+	limits := t.Limits.withDefaults()
+
+	desc := fmt.Sprintf("**Location:** %s\n\n", codeSpan(fmt.Sprintf("%s:%d", f.File, f.Line)))
+	desc += fmt.Sprintf("**Message:** %s\n\n", escapeMarkdown(f.Message))
 
 	if f.CodeSnippet != "" {
-		desc += fmt.Sprintf("**Code:**\n```\n%s\n```\n", f.CodeSnippet)
+		desc += "**Code:**\n" + codeBlock(lang.Fence(lang.Detect(f.File)), f.CodeSnippet, limits.SnippetLines)
+	}
+
+	if section := taxonomySection(t.classify(f)); section != "" {
+		desc += "\n" + strings.TrimSuffix(section, "\n")
 	}
 
 	if f.Suggestion != "" {
-		desc += fmt.Sprintf("\n**Suggestion:** %s\n", f.Suggestion)
+		desc += fmt.Sprintf("\n**Suggestion:** %s\n", escapeMarkdown(f.Suggestion))
 	}
 
-	return desc
+	return truncateMarkdown(desc, limits.Description)
+}
+
+// classify looks up the finding's category in the taxonomy
+func (t *Transformer) classify(f parser.UBSFinding) taxonomy.Entry {
+	e, _ := t.Taxonomy.Lookup(f.Category)
+	return e
+}
+
+// taxonomySection renders CWE, OWASP and reference links, or "" when the
+// category is unclassified
+func taxonomySection(e taxonomy.Entry) string {
+	var b strings.Builder
+	if len(e.CWE) > 0 {
+		links := make([]string, len(e.CWE))
+		for i, id := range e.CWE {
+			links[i] = markdownLink(id, taxonomy.CWEURL(id))
+		}
+		fmt.Fprintf(&b, "**CWE:** %s\n\n", strings.Join(links, ", "))
+	}
+	if len(e.OWASP) > 0 {
+		cats := make([]string, len(e.OWASP))
+		for i, c := range e.OWASP {
+			cats[i] = escapeMarkdown(c)
+		}
+		fmt.Fprintf(&b, "**OWASP:** %s\n\n", strings.Join(cats, ", "))
+	}
+	if len(e.Docs) > 0 {
+		b.WriteString("**References:**\n")
+		for _, doc := range e.Docs {
+			fmt.Fprintf(&b, "- %s\n", markdownLink(doc, doc))
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
 }
 
 // makeDesign builds design notes
 func (t *Transformer) makeDesign(f parser.UBSFinding) string {
 	return fmt.Sprintf("Category: %s\nSeverity: %s\nDetected by: UBS static analysis",
-		f.Category, f.Severity)
+		escapeMarkdown(f.Category), escapeMarkdown(f.Severity))
 }
 
 // makeAcceptance builds acceptance criteria
 func (t *Transformer) makeAcceptance(f parser.UBSFinding) string {
 	if f.Suggestion != "" {
-		return fmt.Sprintf("Fixed when: %s", f.Suggestion)
+		return fmt.Sprintf("Fixed when: %s", escapeMarkdown(f.Suggestion))
 	}
 	return "Code passes UBS scan without this finding"
 }
@@ -168,17 +261,157 @@ func (t *TransformerWithConfig) Transform(finding parser.UBSFinding) (*beads.Iss
 		return nil, err
 	}
 
+	change := t.lastChange(finding)
+
 	// Enrich description with metadata
-	issue.Description = t.makeEnrichedDescription(finding)
+	issue.Description = t.makeEnrichedDescription(finding, change)
+
+	if change != nil {
+		issue.Design += fmt.Sprintf("\nLast changed by: %s \\<%s\\> in %s (%s)",
+			escapeMarkdown(change.Author), escapeMarkdown(change.AuthorEmail),
+			shortSHA(change.Commit), escapeMarkdown(change.Summary))
+	}
 
 	// Add richer tags
 	issue.Tags = t.makeEnrichedTags(finding)
 
+	// Assign to the first code owner, labelling every owner; fall back to
+	// the last author when their Beads user is known
+	if owners := t.owners(finding); len(owners) > 0 {
+		assignee := t.assignee(owners[0])
+		issue.Assignee = &assignee
+		for _, o := range owners {
+			issue.Tags = append(issue.Tags, "owner:"+strings.TrimPrefix(o, "@"))
+		}
+	} else if change != nil {
+		if user, ok := t.config.BlameUsers[change.AuthorEmail]; ok {
+			issue.Assignee = &user
+		}
+	}
+
 	return issue, nil
 }
 
+// lastChange blames the finding's lines (the whole snippet when it spans
+// several, taken to start at the finding's line; the blamer clamps the
+// range to the file). Blame is best-effort: untracked files and errors
+// yield nil.
+func (t *TransformerWithConfig) lastChange(f parser.UBSFinding) *git.BlameLine {
+	if t.config.Blame == nil {
+		return nil
+	}
+
+	end := f.Line
+	if n := snippetLines(f.CodeSnippet); n > 1 {
+		end = f.Line + n - 1
+	}
+
+	change, err := t.config.Blame.LastChange(f.File, f.Line, end)
+	if err != nil {
+		if t.Verbose {
+			log.Printf("WARN: blame %s:%d: %v", f.File, f.Line, err)
+		}
+		return nil
+	}
+	return change
+}
+
+// excerpt loads source context for a finding whose snippet is missing or is
+// just the flagged line. Multi-line scanner snippets are kept as reported.
+func (t *TransformerWithConfig) excerpt(f parser.UBSFinding) *source.Excerpt {
+	if t.config.Source == nil {
+		return nil
+	}
+
+	ex, err := t.config.Source.Excerpt(f.File, f.Line, f.Column, t.config.ContextLines)
+	if err != nil {
+		if t.Verbose {
+			log.Printf("WARN: source context %s:%d: %v", f.File, f.Line, err)
+		}
+		return nil
+	}
+
+	if f.CodeSnippet != "" && strings.TrimSpace(f.CodeSnippet) != strings.TrimSpace(ex.TargetLine()) {
+		return nil
+	}
+	return ex
+}
+
+// renderExcerpt numbers each line, marks the flagged line with ">" and
+// points at the flagged column with a caret
+func renderExcerpt(ex *source.Excerpt) string {
+	width := len(fmt.Sprint(ex.End()))
+
+	var b strings.Builder
+	for i, line := range ex.Lines {
+		n := ex.Start + i
+		marker := " "
+		if n == ex.Target {
+			marker = ">"
+		}
+		fmt.Fprintf(&b, "%s %*d | %s\n", marker, width, n, line)
+
+		if n == ex.Target && ex.Column > 0 && ex.Column <= len(line)+1 {
+			// Reuse the line's own tabs so the caret lines up
+			var pad strings.Builder
+			for _, c := range line[:ex.Column-1] {
+				if c == '\t' {
+					pad.WriteRune('\t')
+				} else {
+					pad.WriteRune(' ')
+				}
+			}
+			fmt.Fprintf(&b, "  %*s | %s^\n", width, "", pad.String())
+		}
+	}
+	return b.String()
+}
+
+// language detects the finding's language from its file name, falling
+// back to the shebang line when source context is available
+func (t *TransformerWithConfig) language(f parser.UBSFinding) string {
+	if l := lang.Detect(f.File); l != "" || t.config.Source == nil {
+		return l
+	}
+	if ex, err := t.config.Source.Excerpt(f.File, 1, 0, 0); err == nil {
+		return lang.DetectShebang(ex.TargetLine())
+	}
+	return ""
+}
+
+// shortSHA abbreviates a commit SHA for display
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
+
+// owners looks up the code owners of a finding's file
+func (t *TransformerWithConfig) owners(f parser.UBSFinding) []string {
+	if t.config.Owners == nil {
+		return nil
+	}
+	return t.config.Owners.Owners(t.repoPath(f.File))
+}
+
+// assignee maps a code owner to a Beads user: the configured alias, else
+// the owner without its leading "@"
+func (t *TransformerWithConfig) assignee(owner string) string {
+	if alias, ok := t.config.OwnerAliases[owner]; ok {
+		return alias
+	}
+	return strings.TrimPrefix(owner, "@")
+}
+
+// repoPath converts a scanner path to a repository-relative slash path
+func (t *TransformerWithConfig) repoPath(file string) string {
+	return t.config.PathPrefix + strings.TrimPrefix(filepath.ToSlash(file), "./")
+}
+
 // makeEnrichedDescription builds description with links and timestamps
-func (t *TransformerWithConfig) makeEnrichedDescription(f parser.UBSFinding) string {
+func (t *TransformerWithConfig) makeEnrichedDescription(f parser.UBSFinding, change *git.BlameLine) string {
+	limits := t.Limits.withDefaults()
 	var desc strings.Builder
 
 	// Scan timestamp
@@ -188,29 +421,78 @@ func (t *TransformerWithConfig) makeEnrichedDescription(f parser.UBSFinding) str
 	}
 
 	// File link (if repo URL provided)
+	location := fmt.Sprintf("%s:%d", f.File, f.Line)
 	if t.config.RepoURL != "" {
-		fileLink := fmt.Sprintf("%s/blob/%s/%s#L%d",
-			strings.TrimSuffix(t.config.RepoURL, "/"),
-			t.config.RepoBranch,
-			f.File,
-			f.Line)
-		desc.WriteString(fmt.Sprintf("**Location:** [%s:%d](%s)\n\n",
-			f.File, f.Line, fileLink))
+		desc.WriteString(fmt.Sprintf("**Location:** %s\n\n", markdownLink(location, t.fileLink(f))))
 	} else {
-		desc.WriteString(fmt.Sprintf("**Location:** `%s:%d`\n\n", f.File, f.Line))
+		desc.WriteString(fmt.Sprintf("**Location:** %s\n\n", codeSpan(location)))
 	}
 
-	desc.WriteString(fmt.Sprintf("**Message:** %s\n\n", f.Message))
+	if change != nil {
+		desc.WriteString(fmt.Sprintf("**Last changed:** %s \\<%s\\> in `%s` (%s): %s\n\n",
+			escapeMarkdown(change.Author), escapeMarkdown(change.AuthorEmail), shortSHA(change.Commit),
+			change.AuthorTime.Format("2006-01-02"), escapeMarkdown(change.Summary)))
+	}
 
-	if f.CodeSnippet != "" {
-		desc.WriteString(fmt.Sprintf("**Code:**\n```\n%s\n```\n\n", f.CodeSnippet))
+	desc.WriteString(fmt.Sprintf("**Message:** %s\n\n", escapeMarkdown(f.Message)))
+
+	fenceInfo := lang.Fence(t.language(f))
+	if ex := t.excerpt(f); ex != nil {
+		desc.WriteString("**Code:**\n" + codeBlock(fenceInfo, renderExcerpt(ex), limits.SnippetLines) + "\n")
+	} else if f.CodeSnippet != "" {
+		desc.WriteString("**Code:**\n" + codeBlock(fenceInfo, f.CodeSnippet, limits.SnippetLines) + "\n")
 	}
 
+	desc.WriteString(taxonomySection(t.classify(f)))
+
 	if f.Suggestion != "" {
-		desc.WriteString(fmt.Sprintf("**Suggestion:** %s\n", f.Suggestion))
+		desc.WriteString(fmt.Sprintf("**Suggestion:** %s\n", escapeMarkdown(f.Suggestion)))
+	}
+
+	return truncateMarkdown(desc.String(), limits.Description)
+}
+
+// fileLink builds the permalink for a finding, pinned to the commit when
+// known. Multi-line snippets link the whole range starting at the finding.
+func (t *TransformerWithConfig) fileLink(f parser.UBSFinding) string {
+	target := LinkTarget{
+		RepoURL:     strings.TrimSuffix(t.config.RepoURL, "/"),
+		Ref:         t.config.Commit,
+		RefIsCommit: t.config.Commit != "",
+		Path:        t.repoPath(f.File),
+		Line:        f.Line,
+		Column:      f.Column,
+	}
+	if target.Ref == "" {
+		target.Ref = t.config.RepoBranch
+	}
+	if n := snippetLines(f.CodeSnippet); n > 1 {
+		target.EndLine = f.Line + n - 1
 	}
 
-	return desc.String()
+	links := t.config.Links
+	if links == nil {
+		links, _ = LinkBuilderFor("auto", t.config.RepoURL)
+	}
+	return links.FileLink(target)
+}
+
+// Permalink returns the forge URL of a finding's location, or "" without a
+// repository URL
+func (t *TransformerWithConfig) Permalink(f parser.UBSFinding) string {
+	if t.config.RepoURL == "" {
+		return ""
+	}
+	return t.fileLink(f)
+}
+
+// snippetLines counts the lines in a code snippet, ignoring a trailing newline
+func snippetLines(snippet string) int {
+	snippet = strings.TrimRight(snippet, "\n")
+	if snippet == "" {
+		return 0
+	}
+	return strings.Count(snippet, "\n") + 1
 }
 
 // makeEnrichedTags creates comprehensive tag set
@@ -221,10 +503,12 @@ func (t *TransformerWithConfig) makeEnrichedTags(f parser.UBSFinding) []string {
 		fmt.Sprintf("severity:%s", f.Severity),
 	}
 
-	// Add file extension as tag for filtering
-	if ext := filepath.Ext(f.File); ext != "" {
-		tags = append(tags, fmt.Sprintf("lang:%s", strings.TrimPrefix(ext, ".")))
+	// Add canonical language as tag for filtering
+	if l := t.language(f); l != "" {
+		tags = append(tags, fmt.Sprintf("lang:%s", l))
 	}
 
+	tags = append(tags, t.classify(f).Labels()...)
+
 	return tags
 }
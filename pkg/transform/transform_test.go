@@ -5,7 +5,10 @@ import (
 	"testing"
 	"time"
 
+	"github.com/TheEditor/strung/pkg/git"
 	"github.com/TheEditor/strung/pkg/parser"
+	"github.com/TheEditor/strung/pkg/source"
+	"github.com/TheEditor/strung/pkg/taxonomy"
 )
 
 func TestTransform(t *testing.T) {
@@ -55,8 +58,8 @@ func TestTransform(t *testing.T) {
 		t.Error("Acceptance missing suggestion")
 	}
 
-	// Verify tags
-	if len(issue.Tags) != 2 || issue.Tags[0] != "ubs" || issue.Tags[1] != "null-safety" {
+	// Verify tags (null-safety is classified as CWE-476)
+	if len(issue.Tags) != 3 || issue.Tags[0] != "ubs" || issue.Tags[1] != "null-safety" || issue.Tags[2] != "cwe:476" {
 		t.Errorf("Tags incorrect: %v", issue.Tags)
 	}
 }
@@ -188,7 +191,7 @@ func TestTransformWithConfig_Tags(t *testing.T) {
 	}
 
 	// Check tags
-	expectedTags := []string{"ubs", "ubs:resource-leak", "severity:warning", "lang:ts"}
+	expectedTags := []string{"ubs", "ubs:resource-leak", "severity:warning", "lang:typescript"}
 	for _, expected := range expectedTags {
 		found := false
 		for _, tag := range issue.Tags {
@@ -232,3 +235,230 @@ func TestTransformWithConfig_NoRepoURL(t *testing.T) {
 		t.Error("Should not have link without RepoURL")
 	}
 }
+
+func TestTransformWithConfig_CommitPermalink(t *testing.T) {
+	config := &TransformConfig{
+		RepoURL:    "https://github.com/user/repo/",
+		RepoBranch: "main",
+		Commit:     "0123456789abcdef0123456789abcdef01234567",
+		PathPrefix: "services/api/",
+	}
+
+	transformer := NewTransformerWithConfig(config)
+
+	finding := parser.UBSFinding{
+		File:        "./src/test.ts",
+		Line:        42,
+		Severity:    "warning",
+		Category:    "leak",
+		Message:     "Test",
+		CodeSnippet: "const t = setInterval(tick, 1000);\nstart(t);\nreturn;\n",
+	}
+
+	issue, err := transformer.Transform(finding)
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	expectedLink := "https://github.com/user/repo/blob/0123456789abcdef0123456789abcdef01234567/services/api/src/test.ts#L42-L44"
+	if !strings.Contains(issue.Description, expectedLink) {
+		t.Errorf("Description missing pinned link %s:\n%s", expectedLink, issue.Description)
+	}
+}
+
+type staticOwners map[string][]string
+
+func (s staticOwners) Owners(path string) []string { return s[path] }
+
+func TestTransformWithConfig_Owners(t *testing.T) {
+	config := &TransformConfig{
+		PathPrefix:   "api/",
+		Owners:       staticOwners{"api/src/db.ts": {"@org/backend", "@bob"}},
+		OwnerAliases: map[string]string{"@org/backend": "alice"},
+	}
+
+	transformer := NewTransformerWithConfig(config)
+
+	issue, err := transformer.Transform(parser.UBSFinding{
+		File: "src/db.ts", Line: 1, Severity: "warning", Category: "x", Message: "Test",
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if issue.Assignee == nil || *issue.Assignee != "alice" {
+		t.Errorf("Expected aliased assignee alice, got %v", issue.Assignee)
+	}
+	for _, want := range []string{"owner:org/backend", "owner:bob"} {
+		found := false
+		for _, tag := range issue.Tags {
+			found = found || tag == want
+		}
+		if !found {
+			t.Errorf("Missing tag %s (got: %v)", want, issue.Tags)
+		}
+	}
+
+	unowned, _ := transformer.Transform(parser.UBSFinding{
+		File: "other.ts", Line: 1, Severity: "warning", Category: "x", Message: "Test",
+	})
+	if unowned.Assignee != nil {
+		t.Errorf("Unowned file should stay unassigned, got %q", *unowned.Assignee)
+	}
+}
+
+type staticBlame struct{ line *git.BlameLine }
+
+func (s staticBlame) LastChange(file string, start, end int) (*git.BlameLine, error) {
+	return s.line, nil
+}
+
+func TestTransformWithConfig_Blame(t *testing.T) {
+	config := &TransformConfig{
+		Blame: staticBlame{&git.BlameLine{
+			Commit:      "0123456789abcdef0123456789abcdef01234567",
+			Author:      "Alice",
+			AuthorEmail: "alice@example.com",
+			AuthorTime:  time.Date(2026, 3, 4, 0, 0, 0, 0, time.UTC),
+			Summary:     "Add handler",
+		}},
+		BlameUsers: map[string]string{"alice@example.com": "alice"},
+	}
+
+	transformer := NewTransformerWithConfig(config)
+
+	issue, err := transformer.Transform(parser.UBSFinding{
+		File: "src/handler.ts", Line: 3, Severity: "warning", Category: "x", Message: "Test",
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	if !strings.Contains(issue.Description, "**Last changed:** Alice \\<alice@example.com\\> in `0123456` (2026-03-04): Add handler") {
+		t.Errorf("Description missing blame: %s", issue.Description)
+	}
+	if !strings.Contains(issue.Design, "Last changed by: Alice") {
+		t.Errorf("Design missing blame: %s", issue.Design)
+	}
+	if issue.Assignee == nil || *issue.Assignee != "alice" {
+		t.Errorf("Expected blame assignee alice, got %v", issue.Assignee)
+	}
+
+	// Code owners take precedence over blame
+	config.Owners = staticOwners{"src/handler.ts": {"@bob"}}
+	issue, _ = transformer.Transform(parser.UBSFinding{
+		File: "src/handler.ts", Line: 3, Severity: "warning", Category: "x", Message: "Test",
+	})
+	if issue.Assignee == nil || *issue.Assignee != "bob" {
+		t.Errorf("Expected code owner assignee, got %v", issue.Assignee)
+	}
+}
+
+type staticSource map[string][]string
+
+func (s staticSource) Excerpt(file string, line, column, context int) (*source.Excerpt, error) {
+	lines := s[file]
+	start := max(1, line-context)
+	end := min(len(lines), line+context)
+	return &source.Excerpt{Start: start, Target: line, Column: column, Lines: lines[start-1 : end]}, nil
+}
+
+func TestTransformWithConfig_SourceExcerpt(t *testing.T) {
+	config := &TransformConfig{
+		Source: staticSource{"src/a.ts": {
+			"function f(obj) {", "\tconst x = obj.prop;", "\treturn x;", "}",
+		}},
+		ContextLines: 1,
+	}
+
+	transformer := NewTransformerWithConfig(config)
+
+	issue, err := transformer.Transform(parser.UBSFinding{
+		File: "src/a.ts", Line: 2, Column: 12, Severity: "warning", Category: "null-safety",
+		Message: "Test", CodeSnippet: "const x = obj.prop;",
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	expected := "**Code:**\n```typescript\n" +
+		"  1 | function f(obj) {\n" +
+		"> 2 | \tconst x = obj.prop;\n" +
+		"    | \t          ^\n" +
+		"  3 | \treturn x;\n" +
+		"```\n"
+	if !strings.Contains(issue.Description, expected) {
+		t.Errorf("Description missing numbered excerpt:\n%s", issue.Description)
+	}
+
+	// Multi-line scanner snippets are kept as reported
+	issue, _ = transformer.Transform(parser.UBSFinding{
+		File: "src/a.ts", Line: 2, Severity: "warning", Category: "null-safety",
+		Message: "Test", CodeSnippet: "const x = obj.prop;\nreturn x;",
+	})
+	if !strings.Contains(issue.Description, "```typescript\nconst x = obj.prop;\nreturn x;\n```") {
+		t.Errorf("Scanner snippet should be rendered as-is:\n%s", issue.Description)
+	}
+}
+
+func TestTransformWithConfig_ShebangLanguage(t *testing.T) {
+	transformer := NewTransformerWithConfig(&TransformConfig{
+		Source: staticSource{"bin/deploy": {"#!/usr/bin/env bash", "rm -rf $DIR/"}},
+	})
+
+	issue, err := transformer.Transform(parser.UBSFinding{
+		File: "bin/deploy", Line: 2, Severity: "critical", Category: "shell", Message: "Unquoted variable",
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	found := false
+	for _, tag := range issue.Tags {
+		found = found || tag == "lang:shell"
+	}
+	if !found {
+		t.Errorf("Expected lang:shell from shebang, got %v", issue.Tags)
+	}
+	if !strings.Contains(issue.Description, "```shell\n") {
+		t.Errorf("Expected shell code fence:\n%s", issue.Description)
+	}
+}
+
+func TestTransformWithConfig_Taxonomy(t *testing.T) {
+	config := &TransformConfig{
+		Taxonomy: taxonomy.Default().With(map[string]taxonomy.Entry{
+			"custom-sqli": {
+				CWE:   []string{"CWE-89"},
+				OWASP: []string{"A03:2021-Injection"},
+				Docs:  []string{"https://wiki.example.com/sqli"},
+			},
+		}),
+	}
+
+	issue, err := NewTransformerWithConfig(config).Transform(parser.UBSFinding{
+		File: "db.ts", Line: 1, Severity: "critical", Category: "custom-sqli", Message: "Test",
+	})
+	if err != nil {
+		t.Fatalf("Transform failed: %v", err)
+	}
+
+	for _, want := range []string{"cwe:89", "owasp:A03:2021"} {
+		found := false
+		for _, tag := range issue.Tags {
+			found = found || tag == want
+		}
+		if !found {
+			t.Errorf("Missing tag %s (got: %v)", want, issue.Tags)
+		}
+	}
+	for _, want := range []string{
+		"**CWE:** [CWE-89](https://cwe.mitre.org/data/definitions/89.html)",
+		"**OWASP:** A03:2021-Injection",
+		"- [https://wiki.example.com/sqli](https://wiki.example.com/sqli)",
+	} {
+		if !strings.Contains(issue.Description, want) {
+			t.Errorf("Description missing %q:\n%s", want, issue.Description)
+		}
+	}
+}